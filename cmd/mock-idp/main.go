@@ -0,0 +1,351 @@
+// mock-idp adalah identity provider OIDC sederhana untuk development dan pengujian login SSO
+// tanpa IdP kampus. Kunci tanda tangan dibuat ulang setiap start dan data hanya disimpan di memori.
+//
+//	go run ./cmd/mock-idp
+//
+// Lalu jalankan API dengan OIDC_ISSUER_URL=http://localhost:9000 dan OIDC_CLIENT_ID=course-planner.
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	KEY_ID         = "mock-idp"
+	CODE_TTL       = time.Minute
+	ID_TOKEN_TTL   = 5 * time.Minute
+	ACCESS_TTL     = time.Hour
+	TOKEN_BYTES    = 24
+	DEFAULT_ADDR   = ":9000"
+	DEFAULT_ISSUER = "http://localhost:9000"
+)
+
+// mockUser adalah akun di IdP; email, NIM dan NIDN sama dengan data cmd/seed
+type mockUser struct {
+	Subject string
+	Name    string
+	Email   string
+	NIM     string
+	NIDN    string
+}
+
+var users = []mockUser{
+	{Subject: "mock-admin-1", Name: "Admin Satu", Email: "admin@example.com"},
+	{Subject: "mock-dosen-1", Name: "Dosen Satu", Email: "dosen@example.com", NIDN: "1234567890"},
+	{Subject: "mock-dosen-2", Name: "Dosen Dua", Email: "dosen2@example.com", NIDN: "0987654321"},
+	{Subject: "mock-mahasiswa-1", Name: "Mahasiswa Satu", Email: "mahasiswa@example.com", NIM: "2024000001"},
+	{Subject: "mock-mahasiswa-2", Name: "Mahasiswa Dua", Email: "mahasiswa2@example.com", NIM: "2024000002"},
+	// belum ada di database: untuk mencoba OIDC_AUTO_PROVISION
+	{Subject: "mock-mahasiswa-baru", Name: "Mahasiswa Baru", Email: "mahasiswa.baru@example.com", NIM: "2024000099"},
+}
+
+// authorization adalah authorization code yang belum ditukar di token endpoint
+type authorization struct {
+	user          mockUser
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	expiresAt     time.Time
+}
+
+type server struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	key          *rsa.PrivateKey
+
+	mu     sync.Mutex
+	codes  map[string]authorization
+	tokens map[string]mockUser // access token -> user untuk /userinfo
+}
+
+func main() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("failed to generate signing key: %v", err)
+	}
+
+	s := &server{
+		issuer:       strings.TrimSuffix(getEnv("MOCK_IDP_ISSUER", DEFAULT_ISSUER), "/"),
+		clientID:     getEnv("MOCK_IDP_CLIENT_ID", "course-planner"),
+		clientSecret: getEnv("MOCK_IDP_CLIENT_SECRET", ""),
+		key:          key,
+		codes:        make(map[string]authorization),
+		tokens:       make(map[string]mockUser),
+	}
+
+	addr := getEnv("MOCK_IDP_ADDR", DEFAULT_ADDR)
+	log.Printf("mock IdP listening on %s (issuer %s, client_id %s)", addr, s.issuer, s.clientID)
+	log.Fatal(http.ListenAndServe(addr, s.routes()))
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/jwks", s.jwks)
+	mux.HandleFunc("/userinfo", s.userinfo)
+	return mux
+}
+
+func (s *server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.issuer,
+		"authorization_endpoint":                s.issuer + "/authorize",
+		"token_endpoint":                        s.issuer + "/token",
+		"jwks_uri":                              s.issuer + "/jwks",
+		"userinfo_endpoint":                     s.issuer + "/userinfo",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+	})
+}
+
+var pickerTemplate = template.Must(template.New("picker").Parse(`<!doctype html>
+<html><head><meta charset="utf-8"><title>Mock IdP</title></head>
+<body style="font-family: sans-serif">
+<h1>Mock IdP - pilih akun</h1>
+<ul>{{range .}}<li><a href="{{.URL}}">{{.Name}}</a> &lt;{{.Email}}&gt;{{if .NIM}} NIM {{.NIM}}{{end}}{{if .NIDN}} NIDN {{.NIDN}}{{end}}</li>{{end}}</ul>
+</body></html>`))
+
+// authorize menampilkan daftar akun; memilih akun (atau mengirim login_hint berisi email) langsung
+// mengarahkan kembali ke redirect_uri dengan code dan state
+func (s *server) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != s.clientID {
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	}
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || !redirectURI.IsAbs() {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if query.Get("response_type") != "code" {
+		redirectError(w, r, redirectURI, query.Get("state"), "unsupported_response_type")
+		return
+	}
+	if query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256" {
+		redirectError(w, r, redirectURI, query.Get("state"), "invalid_request")
+		return
+	}
+
+	hint := strings.ToLower(query.Get("login_hint"))
+	if hint == "" {
+		type option struct {
+			mockUser
+			URL string
+		}
+		options := make([]option, 0, len(users))
+		for _, user := range users {
+			q := r.URL.Query()
+			q.Set("login_hint", user.Email)
+			options = append(options, option{mockUser: user, URL: "/authorize?" + q.Encode()})
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = pickerTemplate.Execute(w, options)
+		return
+	}
+
+	user, ok := findUser(hint)
+	if !ok {
+		redirectError(w, r, redirectURI, query.Get("state"), "access_denied")
+		return
+	}
+
+	code := randomToken()
+	s.mu.Lock()
+	s.codes[code] = authorization{
+		user:          user,
+		redirectURI:   redirectURI.String(),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		expiresAt:     time.Now().Add(CODE_TTL),
+	}
+	s.mu.Unlock()
+
+	params := redirectURI.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// token menukar authorization code (sekali pakai) dengan ID token setelah memeriksa client,
+// redirect_uri dan code_verifier PKCE
+func (s *server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != s.clientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(s.clientSecret)) != 1 {
+		oauthError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		oauthError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+
+	s.mu.Lock()
+	auth, found := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	if !found || time.Now().After(auth.expiresAt) || auth.redirectURI != r.PostForm.Get("redirect_uri") {
+		oauthError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != auth.codeChallenge {
+		oauthError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := userClaims(auth.user)
+	claims["iss"] = s.issuer
+	claims["aud"] = s.clientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(ID_TOKEN_TTL).Unix()
+	if auth.nonce != "" {
+		claims["nonce"] = auth.nonce
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = KEY_ID
+	signed, err := idToken.SignedString(s.key)
+	if err != nil {
+		oauthError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+
+	accessToken := randomToken()
+	s.mu.Lock()
+	s.tokens[accessToken] = auth.user
+	s.mu.Unlock()
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(ACCESS_TTL.Seconds()),
+		"id_token":     signed,
+	})
+}
+
+func (s *server) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := s.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": KEY_ID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *server) userinfo(w http.ResponseWriter, r *http.Request) {
+	accessToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	s.mu.Lock()
+	user, ok := s.tokens[accessToken]
+	s.mu.Unlock()
+	if !ok {
+		oauthError(w, http.StatusUnauthorized, "invalid_token")
+		return
+	}
+	writeJSON(w, http.StatusOK, userClaims(user))
+}
+
+// userClaims adalah claim profil user; nim / nidn hanya dikirim jika ada
+func userClaims(user mockUser) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"sub":            user.Subject,
+		"name":           user.Name,
+		"email":          user.Email,
+		"email_verified": true,
+	}
+	if user.NIM != "" {
+		claims["nim"] = user.NIM
+	}
+	if user.NIDN != "" {
+		claims["nidn"] = user.NIDN
+	}
+	return claims
+}
+
+func findUser(email string) (mockUser, bool) {
+	for _, user := range users {
+		if strings.EqualFold(user.Email, email) {
+			return user, true
+		}
+	}
+	return mockUser{}, false
+}
+
+func redirectError(w http.ResponseWriter, r *http.Request, redirectURI *url.URL, state, code string) {
+	params := redirectURI.Query()
+	params.Set("error", code)
+	params.Set("state", state)
+	redirectURI.RawQuery = params.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func oauthError(w http.ResponseWriter, status int, code string) {
+	writeJSON(w, status, map[string]string{"error": code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func randomToken() string {
+	buf := make([]byte, TOKEN_BYTES)
+	if _, err := rand.Read(buf); err != nil {
+		log.Fatalf("failed to generate token: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func getEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -14,45 +14,66 @@ import (
 func main() {
 	db := config.LoadDatabase()
 
+	// Seed fakultas & prodi
+	faculty := &models.Faculty{
+		Kode: "FILKOM",
+		Nama: "Fakultas Ilmu Komputer",
+	}
+	if err := seedFacultyIfNotExists(db, faculty); err != nil {
+		log.Fatalf("failed to seed faculty: %v", err)
+	}
+
+	prodi := &models.StudyProgram{
+		Kode:      "SI",
+		Nama:      "Sistem Informasi",
+		Jenjang:   "S1",
+		FacultyID: faculty.ID,
+	}
+	if err := seedStudyProgramIfNotExists(db, prodi); err != nil {
+		log.Fatalf("failed to seed study program: %v", err)
+	}
+
 	// Seed admin
 	if err := seedUserIfNotExists(db, "admin@example.com", &models.User{
-		Name:   "Admin Satu",
-		Email:  "admin@example.com",
-		Role:   "admin",
-		NIM:    "",
-		NIDN:   "",
+		Name:  "Admin Satu",
+		Email: "admin@example.com",
+		Role:  "admin",
+		NIM:   "",
+		NIDN:  "",
 	}); err != nil {
 		log.Fatalf("failed to seed admin: %v", err)
 	}
 
 	if err := seedUserIfNotExists(db, "admin2@example.com", &models.User{
-		Name:   "Admin Dua",
-		Email:  "admin2@example.com",
-		Role:   "admin",
-		NIM:    "",
-		NIDN:   "",
+		Name:  "Admin Dua",
+		Email: "admin2@example.com",
+		Role:  "admin",
+		NIM:   "",
+		NIDN:  "",
 	}); err != nil {
 		log.Fatalf("failed to seed admin kedua: %v", err)
 	}
 
 	// Seed dosen
 	dosen := &models.User{
-		Name:  "Dosen Satu",
-		Email: "dosen@example.com",
-		Role:  "dosen",
-		NIM:   "",
-		NIDN:  "1234567890",
+		Name:           "Dosen Satu",
+		Email:          "dosen@example.com",
+		Role:           "dosen",
+		NIM:            "",
+		NIDN:           "1234567890",
+		StudyProgramID: &prodi.ID,
 	}
 	if err := seedUserIfNotExists(db, dosen.Email, dosen); err != nil {
 		log.Fatalf("failed to seed dosen: %v", err)
 	}
 
 	dosen2 := &models.User{
-		Name:  "Dosen Dua",
-		Email: "dosen2@example.com",
-		Role:  "dosen",
-		NIM:   "",
-		NIDN:  "0987654321",
+		Name:           "Dosen Dua",
+		Email:          "dosen2@example.com",
+		Role:           "dosen",
+		NIM:            "",
+		NIDN:           "0987654321",
+		StudyProgramID: &prodi.ID,
 	}
 	if err := seedUserIfNotExists(db, dosen2.Email, dosen2); err != nil {
 		log.Fatalf("failed to seed dosen kedua: %v", err)
@@ -66,24 +87,26 @@ func main() {
 
 	// Seed mahasiswa with dosen_pa_id
 	mahasiswa := &models.User{
-		Name:      "Mahasiswa Satu",
-		Email:     "mahasiswa@example.com",
-		Role:      "mahasiswa",
-		NIM:       "2024000001",
-		NIDN:      "",
-		DosenPAID: &dosenUser.ID,
+		Name:           "Mahasiswa Satu",
+		Email:          "mahasiswa@example.com",
+		Role:           "mahasiswa",
+		NIM:            "2024000001",
+		NIDN:           "",
+		DosenPAID:      &dosenUser.ID,
+		StudyProgramID: &prodi.ID,
 	}
 	if err := seedUserIfNotExists(db, mahasiswa.Email, mahasiswa); err != nil {
 		log.Fatalf("failed to seed mahasiswa: %v", err)
 	}
 
 	mahasiswa2 := &models.User{
-		Name:      "Mahasiswa Dua",
-		Email:     "mahasiswa2@example.com",
-		Role:      "mahasiswa",
-		NIM:       "2024000002",
-		NIDN:      "",
-		DosenPAID: &dosenUser.ID,
+		Name:           "Mahasiswa Dua",
+		Email:          "mahasiswa2@example.com",
+		Role:           "mahasiswa",
+		NIM:            "2024000002",
+		NIDN:           "",
+		DosenPAID:      &dosenUser.ID,
+		StudyProgramID: &prodi.ID,
 	}
 	if err := seedUserIfNotExists(db, mahasiswa2.Email, mahasiswa2); err != nil {
 		log.Fatalf("failed to seed mahasiswa kedua: %v", err)
@@ -91,17 +114,19 @@ func main() {
 
 	// Seed courses
 	if err := seedCourseIfNotExists(db, "JSI60214", &models.Course{
-		Kode: "JSI60214",
-		Nama: "Aplikasi Berbasis Layanan",
-		SKS:  3,
+		Kode:           "JSI60214",
+		Nama:           "Aplikasi Berbasis Layanan",
+		SKS:            3,
+		StudyProgramID: &prodi.ID,
 	}); err != nil {
 		log.Fatalf("failed to seed course ABL: %v", err)
 	}
 
 	if err := seedCourseIfNotExists(db, "JSI60204", &models.Course{
-		Kode: "JSI60204",
-		Nama: "Tata Kelola",
-		SKS:  3,
+		Kode:           "JSI60204",
+		Nama:           "Tata Kelola",
+		SKS:            3,
+		StudyProgramID: &prodi.ID,
 	}); err != nil {
 		log.Fatalf("failed to seed course Tata Kelola: %v", err)
 	}
@@ -147,10 +172,28 @@ func seedUserIfNotExists(db *gorm.DB, email string, user *models.User) error {
 		return err
 	}
 	user.Password = string(hashed)
+	user.MustChangePassword = true
+	user.IsActive = true
 
 	return db.Create(user).Error
 }
 
+func seedFacultyIfNotExists(db *gorm.DB, faculty *models.Faculty) error {
+	if err := db.Where("kode = ?", faculty.Kode).First(faculty).Error; err == nil {
+		// sudah ada, pakai yang lama
+		return nil
+	}
+	return db.Create(faculty).Error
+}
+
+func seedStudyProgramIfNotExists(db *gorm.DB, program *models.StudyProgram) error {
+	if err := db.Where("kode = ?", program.Kode).First(program).Error; err == nil {
+		// sudah ada, pakai yang lama
+		return nil
+	}
+	return db.Create(program).Error
+}
+
 func seedCourseIfNotExists(db *gorm.DB, kode string, course *models.Course) error {
 	var existing models.Course
 	if err := db.Where("kode = ?", kode).First(&existing).Error; err == nil {
@@ -191,8 +234,11 @@ func seedClassIfNotExists(db *gorm.DB) error {
 	}
 
 	class := &models.Class{
-		CourseID:          courseID,
-		DosenID:           dosenID,
+		CourseID: courseID,
+		DosenID:  dosenID,
+		Dosens: []models.ClassDosen{
+			{DosenID: dosenID, Role: "coordinator"},
+		},
 		NamaKelas:         "B",
 		Hari:              "Senin",
 		JamMulai:          jamMulai,
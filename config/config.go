@@ -1,9 +1,13 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -37,3 +41,272 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+const (
+	// REGISTRATION_MODE_DISABLED menutup /api/auth/register; akun hanya dibuat admin / lewat undangan
+	REGISTRATION_MODE_DISABLED = "disabled"
+	// REGISTRATION_MODE_MAHASISWA hanya mengizinkan mahasiswa yang sudah diimport admin mengklaim akunnya lewat NIM
+	REGISTRATION_MODE_MAHASISWA = "mahasiswa"
+)
+
+type AuthConfig struct {
+	RegistrationMode string
+	InvitationTTL    time.Duration
+	AccessTokenTTL   time.Duration
+	RefreshTokenTTL  time.Duration
+	JWTKeysDir       string // folder berisi file <kid>.pem
+	JWTActiveKID     string // kid yang dipakai sign; boleh kosong jika hanya ada satu private key
+	JWTIssuer        string
+	PasswordResetTTL time.Duration
+	PasswordResetURL string // link di email reset; token ditambahkan sebagai ?token=
+	ActivationURL    string // link di email aktivasi registrasi mandiri; token ditambahkan sebagai ?token=
+
+	LoginMaxFailures     int           // gagal login berturut-turut sebelum akun dikunci
+	LoginLockoutDuration time.Duration // lama akun dikunci
+	LoginIPMaxFailures   int           // gagal login dari satu IP sebelum IP diperlambat
+	LoginFailureWindow   time.Duration // rentang waktu penghitungan gagal login
+
+	TOTPEncryptionKey []byte // kunci AES-256 untuk mengenkripsi secret 2FA di database
+	TOTPIssuer        string // nama aplikasi yang tampil di authenticator app
+}
+
+// LoadAuthConfig membaca REGISTRATION_MODE (default disabled), INVITATION_TTL_HOURS (default 72),
+// ACCESS_TOKEN_TTL_MINUTES (default 15), REFRESH_TOKEN_TTL_DAYS (default 30),
+// JWT_KEYS_DIR (wajib), JWT_ACTIVE_KID, JWT_ISSUER (default course-planner-api),
+// PASSWORD_RESET_TTL_MINUTES (default 60), PASSWORD_RESET_URL, LOGIN_MAX_FAILURES (default 5),
+// LOGIN_LOCKOUT_MINUTES (default 15), LOGIN_IP_MAX_FAILURES (default 20),
+// LOGIN_FAILURE_WINDOW_MINUTES (default 15), TOTP_ENCRYPTION_KEY (wajib, 32 byte base64) dan
+// TOTP_ISSUER (default Course Planner)
+func LoadAuthConfig() AuthConfig {
+	mode := getEnv("REGISTRATION_MODE", REGISTRATION_MODE_DISABLED)
+	if mode != REGISTRATION_MODE_DISABLED && mode != REGISTRATION_MODE_MAHASISWA {
+		log.Fatalf("invalid REGISTRATION_MODE %q (allowed: %s, %s)", mode, REGISTRATION_MODE_DISABLED, REGISTRATION_MODE_MAHASISWA)
+	}
+
+	ttlHours, err := strconv.Atoi(getEnv("INVITATION_TTL_HOURS", "72"))
+	if err != nil || ttlHours <= 0 {
+		log.Fatalf("invalid INVITATION_TTL_HOURS %q", os.Getenv("INVITATION_TTL_HOURS"))
+	}
+
+	accessMinutes, err := strconv.Atoi(getEnv("ACCESS_TOKEN_TTL_MINUTES", "15"))
+	if err != nil || accessMinutes <= 0 {
+		log.Fatalf("invalid ACCESS_TOKEN_TTL_MINUTES %q", os.Getenv("ACCESS_TOKEN_TTL_MINUTES"))
+	}
+
+	refreshDays, err := strconv.Atoi(getEnv("REFRESH_TOKEN_TTL_DAYS", "30"))
+	if err != nil || refreshDays <= 0 {
+		log.Fatalf("invalid REFRESH_TOKEN_TTL_DAYS %q", os.Getenv("REFRESH_TOKEN_TTL_DAYS"))
+	}
+
+	resetMinutes, err := strconv.Atoi(getEnv("PASSWORD_RESET_TTL_MINUTES", "60"))
+	if err != nil || resetMinutes <= 0 {
+		log.Fatalf("invalid PASSWORD_RESET_TTL_MINUTES %q", os.Getenv("PASSWORD_RESET_TTL_MINUTES"))
+	}
+
+	maxFailures, err := strconv.Atoi(getEnv("LOGIN_MAX_FAILURES", "5"))
+	if err != nil || maxFailures <= 0 {
+		log.Fatalf("invalid LOGIN_MAX_FAILURES %q", os.Getenv("LOGIN_MAX_FAILURES"))
+	}
+
+	lockoutMinutes, err := strconv.Atoi(getEnv("LOGIN_LOCKOUT_MINUTES", "15"))
+	if err != nil || lockoutMinutes <= 0 {
+		log.Fatalf("invalid LOGIN_LOCKOUT_MINUTES %q", os.Getenv("LOGIN_LOCKOUT_MINUTES"))
+	}
+
+	ipMaxFailures, err := strconv.Atoi(getEnv("LOGIN_IP_MAX_FAILURES", "20"))
+	if err != nil || ipMaxFailures <= 0 {
+		log.Fatalf("invalid LOGIN_IP_MAX_FAILURES %q", os.Getenv("LOGIN_IP_MAX_FAILURES"))
+	}
+
+	windowMinutes, err := strconv.Atoi(getEnv("LOGIN_FAILURE_WINDOW_MINUTES", "15"))
+	if err != nil || windowMinutes <= 0 {
+		log.Fatalf("invalid LOGIN_FAILURE_WINDOW_MINUTES %q", os.Getenv("LOGIN_FAILURE_WINDOW_MINUTES"))
+	}
+
+	keysDir := getEnv("JWT_KEYS_DIR", "")
+	if keysDir == "" {
+		log.Fatalf("JWT_KEYS_DIR is not set")
+	}
+
+	totpKey, err := base64.StdEncoding.DecodeString(getEnv("TOTP_ENCRYPTION_KEY", ""))
+	if err != nil || len(totpKey) != 32 {
+		log.Fatalf("TOTP_ENCRYPTION_KEY must be 32 random bytes encoded as base64 (openssl rand -base64 32)")
+	}
+
+	return AuthConfig{
+		RegistrationMode: mode,
+		InvitationTTL:    time.Duration(ttlHours) * time.Hour,
+		AccessTokenTTL:   time.Duration(accessMinutes) * time.Minute,
+		RefreshTokenTTL:  time.Duration(refreshDays) * 24 * time.Hour,
+		JWTKeysDir:       keysDir,
+		JWTActiveKID:     getEnv("JWT_ACTIVE_KID", ""),
+		JWTIssuer:        getEnv("JWT_ISSUER", "course-planner-api"),
+		PasswordResetTTL: time.Duration(resetMinutes) * time.Minute,
+		PasswordResetURL: getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+		ActivationURL:    getEnv("ACTIVATION_URL", "http://localhost:3000/activate"),
+
+		LoginMaxFailures:     maxFailures,
+		LoginLockoutDuration: time.Duration(lockoutMinutes) * time.Minute,
+		LoginIPMaxFailures:   ipMaxFailures,
+		LoginFailureWindow:   time.Duration(windowMinutes) * time.Minute,
+
+		TOTPEncryptionKey: totpKey,
+		TOTPIssuer:        getEnv("TOTP_ISSUER", "Course Planner"),
+	}
+}
+
+const (
+	MAILER_LOG  = "log"  // email hanya ditulis ke log dengan token disamarkan (untuk development)
+	MAILER_FILE = "file" // email ditulis sebagai file .eml di MAIL_OUTBOX_DIR
+	MAILER_SMTP = "smtp"
+)
+
+type MailConfig struct {
+	Driver       string
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	OutboxDir    string
+}
+
+// LoadMailConfig membaca MAILER (log | file | smtp, wajib diisi), MAIL_FROM, SMTP_HOST, SMTP_PORT,
+// SMTP_USERNAME, SMTP_PASSWORD dan MAIL_OUTBOX_DIR (default ./tmp/mail).
+// MAILER tidak punya default agar deployment tidak diam-diam memakai mailer development.
+func LoadMailConfig() MailConfig {
+	driver := getEnv("MAILER", "")
+	if driver == "" {
+		log.Fatalf("MAILER is not set (allowed: %s, %s, %s)", MAILER_SMTP, MAILER_FILE, MAILER_LOG)
+	}
+	if driver != MAILER_LOG && driver != MAILER_FILE && driver != MAILER_SMTP {
+		log.Fatalf("invalid MAILER %q (allowed: %s, %s, %s)", driver, MAILER_LOG, MAILER_FILE, MAILER_SMTP)
+	}
+
+	port, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil || port <= 0 {
+		log.Fatalf("invalid SMTP_PORT %q", os.Getenv("SMTP_PORT"))
+	}
+
+	cfg := MailConfig{
+		Driver:       driver,
+		From:         getEnv("MAIL_FROM", "Course Planner <no-reply@localhost>"),
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     port,
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		OutboxDir:    getEnv("MAIL_OUTBOX_DIR", "./tmp/mail"),
+	}
+	if driver == MAILER_SMTP && cfg.SMTPHost == "" {
+		log.Fatalf("SMTP_HOST is required when MAILER=%s", MAILER_SMTP)
+	}
+	return cfg
+}
+
+type ServerConfig struct {
+	ProxyHeader    string   // header berisi IP client asli dari reverse proxy, kosong = pakai IP koneksi
+	TrustedProxies []string // IP / CIDR reverse proxy yang boleh mengisi ProxyHeader
+}
+
+// LoadServerConfig membaca PROXY_HEADER (misal X-Real-IP) dan TRUSTED_PROXIES (IP / CIDR dipisah koma).
+// PROXY_HEADER tanpa TRUSTED_PROXIES ditolak karena header bisa dipalsukan langsung oleh client.
+func LoadServerConfig() ServerConfig {
+	cfg := ServerConfig{ProxyHeader: getEnv("PROXY_HEADER", "")}
+	for _, proxy := range strings.Split(getEnv("TRUSTED_PROXIES", ""), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			cfg.TrustedProxies = append(cfg.TrustedProxies, proxy)
+		}
+	}
+	if cfg.ProxyHeader != "" && len(cfg.TrustedProxies) == 0 {
+		log.Fatalf("TRUSTED_PROXIES is required when PROXY_HEADER is set")
+	}
+	return cfg
+}
+
+const (
+	STORAGE_LOCAL = "local" // file disimpan di disk dan disajikan aplikasi di /uploads
+	STORAGE_S3    = "s3"    // S3 atau layanan kompatibel (MinIO, R2, ...)
+)
+
+type StorageConfig struct {
+	Driver        string
+	LocalDir      string
+	PublicBaseURL string // prefix URL publik file; default /uploads untuk local, endpoint/bucket untuk s3
+	S3Endpoint    string
+	S3Region      string
+	S3Bucket      string
+	S3AccessKey   string
+	S3SecretKey   string
+	S3PathStyle   bool // https://endpoint/bucket/key (MinIO) alih-alih https://bucket.endpoint/key
+}
+
+// LoadStorageConfig membaca STORAGE_DRIVER (local | s3, default local), STORAGE_LOCAL_DIR (default ./uploads),
+// STORAGE_PUBLIC_URL, S3_ENDPOINT, S3_REGION (default us-east-1), S3_BUCKET, S3_ACCESS_KEY, S3_SECRET_KEY
+// dan S3_PATH_STYLE (default true)
+func LoadStorageConfig() StorageConfig {
+	driver := getEnv("STORAGE_DRIVER", STORAGE_LOCAL)
+	if driver != STORAGE_LOCAL && driver != STORAGE_S3 {
+		log.Fatalf("invalid STORAGE_DRIVER %q (allowed: %s, %s)", driver, STORAGE_LOCAL, STORAGE_S3)
+	}
+
+	pathStyle, err := strconv.ParseBool(getEnv("S3_PATH_STYLE", "true"))
+	if err != nil {
+		log.Fatalf("invalid S3_PATH_STYLE %q", os.Getenv("S3_PATH_STYLE"))
+	}
+
+	cfg := StorageConfig{
+		Driver:        driver,
+		LocalDir:      getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+		PublicBaseURL: getEnv("STORAGE_PUBLIC_URL", ""),
+		S3Endpoint:    getEnv("S3_ENDPOINT", ""),
+		S3Region:      getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:      getEnv("S3_BUCKET", ""),
+		S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+		S3PathStyle:   pathStyle,
+	}
+	if driver == STORAGE_S3 && (cfg.S3Endpoint == "" || cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "") {
+		log.Fatalf("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required when STORAGE_DRIVER=%s", STORAGE_S3)
+	}
+	return cfg
+}
+
+type OIDCConfig struct {
+	IssuerURL     string // kosong berarti login OIDC nonaktif
+	ClientID      string
+	ClientSecret  string // kosong untuk public client (hanya PKCE)
+	RedirectURL   string // halaman frontend yang menerima ?code=&state= dari IdP
+	Scopes        []string
+	NIMClaim      string // claim ID token berisi NIM mahasiswa
+	NIDNClaim     string // claim ID token berisi NIDN dosen
+	AutoProvision bool   // buat akun baru untuk user IdP yang belum terdaftar
+}
+
+func (c OIDCConfig) Enabled() bool {
+	return c.IssuerURL != ""
+}
+
+// LoadOIDCConfig membaca OIDC_ISSUER_URL (kosong = nonaktif), OIDC_CLIENT_ID, OIDC_CLIENT_SECRET,
+// OIDC_REDIRECT_URL, OIDC_SCOPES (default "openid email profile"), OIDC_NIM_CLAIM (default nim),
+// OIDC_NIDN_CLAIM (default nidn) dan OIDC_AUTO_PROVISION (default false)
+func LoadOIDCConfig() OIDCConfig {
+	autoProvision, err := strconv.ParseBool(getEnv("OIDC_AUTO_PROVISION", "false"))
+	if err != nil {
+		log.Fatalf("invalid OIDC_AUTO_PROVISION %q", os.Getenv("OIDC_AUTO_PROVISION"))
+	}
+
+	cfg := OIDCConfig{
+		IssuerURL:     strings.TrimSuffix(getEnv("OIDC_ISSUER_URL", ""), "/"),
+		ClientID:      getEnv("OIDC_CLIENT_ID", ""),
+		ClientSecret:  getEnv("OIDC_CLIENT_SECRET", ""),
+		RedirectURL:   getEnv("OIDC_REDIRECT_URL", "http://localhost:3000/oidc/callback"),
+		Scopes:        strings.Fields(getEnv("OIDC_SCOPES", "openid email profile")),
+		NIMClaim:      getEnv("OIDC_NIM_CLAIM", "nim"),
+		NIDNClaim:     getEnv("OIDC_NIDN_CLAIM", "nidn"),
+		AutoProvision: autoProvision,
+	}
+	if cfg.Enabled() && cfg.ClientID == "" {
+		log.Fatalf("OIDC_CLIENT_ID is required when OIDC_ISSUER_URL is set")
+	}
+	return cfg
+}
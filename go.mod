@@ -4,8 +4,6 @@ go 1.24.0
 
 require (
 	github.com/gofiber/fiber/v2 v2.52.9
-	github.com/gofiber/jwt/v3 v3.3.10
-	github.com/golang-jwt/jwt/v4 v4.5.0
 	github.com/joho/godotenv v1.5.1
 	gorm.io/driver/postgres v1.6.0
 	gorm.io/gorm v1.31.1
@@ -0,0 +1,64 @@
+// Package export berisi writer tabular (CSV / XLSX) yang menulis baris per baris
+// ke io.Writer sehingga export data besar tidak perlu dimuat ke memori.
+package export
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+const (
+	FORMAT_CSV  = "csv"
+	FORMAT_XLSX = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format, use csv or xlsx")
+
+// Writer menulis satu baris tabel setiap pemanggilan Write.
+// Close wajib dipanggil untuk menyelesaikan file.
+type Writer interface {
+	Write(record []string) error
+	Close() error
+}
+
+// NewWriter membuat writer sesuai format ("csv" atau "xlsx")
+func NewWriter(format string, w io.Writer, sheetName string) (Writer, error) {
+	switch format {
+	case FORMAT_CSV:
+		return NewCSVWriter(w), nil
+	case FORMAT_XLSX:
+		return NewXLSXWriter(w, sheetName)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// ContentType mengembalikan MIME type untuk format export
+func ContentType(format string) string {
+	if format == FORMAT_XLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// IsSupported memeriksa apakah format export dikenali
+func IsSupported(format string) bool {
+	return format == FORMAT_CSV || format == FORMAT_XLSX
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Write(record []string) error {
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
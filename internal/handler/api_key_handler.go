@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// API_KEY_HEADER adalah header tempat sistem lain mengirim API key
+const API_KEY_HEADER = "X-API-Key"
+
+// APIKeyHandler mengelola API key sistem lain (admin global) dan memverifikasinya di request
+type APIKeyHandler struct {
+	service service.APIKeyService
+}
+
+func NewAPIKeyHandler(s service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: s}
+}
+
+type CreateAPIKeyRequest struct {
+	Name           string     `json:"name"`
+	Scopes         []string   `json:"scopes"`
+	StudyProgramID *string    `json:"study_program_id,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"` // RFC3339
+}
+
+type UpdateAPIKeyRequest struct {
+	Name      *string    `json:"name,omitempty"`
+	Scopes    []string   `json:"scopes,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ListAPIKeyScopes menampilkan permission yang boleh diberikan ke API key
+func (h *APIKeyHandler) ListAPIKeyScopes(c *fiber.Ctx) error {
+	return c.JSON(h.service.ListScopes())
+}
+
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	keys, err := h.service.ListAPIKeys()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(keys)
+}
+
+// CreateAPIKey membuat key baru. Key asli hanya dikembalikan di response ini.
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	var req CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	input := service.CreateAPIKeyInput{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if req.StudyProgramID != nil && *req.StudyProgramID != "" {
+		id, err := uuid.Parse(*req.StudyProgramID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study_program_id"})
+		}
+		input.StudyProgramID = &id
+	}
+	if userID, err := getUserIDFromContext(c); err == nil {
+		input.CreatedByID = &userID
+	}
+
+	issued, err := h.service.CreateAPIKey(input)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "API key created successfully, store the key now as it will not be shown again",
+		"api_key": issued.APIKey,
+		"key":     issued.Key,
+	})
+}
+
+func (h *APIKeyHandler) GetAPIKey(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid API key id"})
+	}
+
+	key, err := h.service.GetAPIKey(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(key)
+}
+
+func (h *APIKeyHandler) UpdateAPIKey(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid API key id"})
+	}
+
+	var req UpdateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	key, err := h.service.UpdateAPIKey(id, service.UpdateAPIKeyInput{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return apiKeyError(c, err)
+	}
+	return c.JSON(key)
+}
+
+// RevokeAPIKey mencabut key secara permanen; request berikutnya dengan key ini ditolak
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid API key id"})
+	}
+
+	if err := h.service.RevokeAPIKey(id); err != nil {
+		return apiKeyError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Authenticate memverifikasi header X-API-Key lalu menyimpan scope key sebagai claim "permissions"
+// di Locals("user"), sehingga pengecekan permission dan pembatasan prodi berlaku sama seperti JWT
+func (h *APIKeyHandler) Authenticate(c *fiber.Ctx) error {
+	key, err := h.service.Authenticate(c.Get(API_KEY_HEADER), c.IP())
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	permissions := make([]interface{}, 0, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		permissions = append(permissions, scope)
+	}
+	claims := jwt.MapClaims{
+		"api_key_id":  key.ID.String(),
+		"role":        "api_key",
+		"permissions": permissions,
+	}
+	if key.StudyProgramID != nil {
+		claims["study_program_id"] = key.StudyProgramID.String()
+	}
+
+	c.Locals("user", &jwt.Token{Claims: claims, Valid: true})
+	return c.Next()
+}
+
+// AuditSnapshot memuat key dari param :id, atau dari id di response create.
+// Key asli tidak pernah ikut tercatat karena hanya hash-nya yang disimpan.
+func (h *APIKeyHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	if c.Params("id") != "" {
+		return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+			return h.service.GetAPIKey(id)
+		})
+	}
+
+	id, err := uuid.Parse(entityIDFromBody(c.Response().Body()))
+	if err != nil {
+		return "", nil
+	}
+	key, err := h.service.GetAPIKey(id)
+	if err != nil {
+		// jangan sampai after jatuh ke body response yang berisi key
+		return id.String(), fiber.Map{"id": id}
+	}
+	return id.String(), key
+}
+
+func apiKeyError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrAPIKeyRevoked):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"course-planner-api/internal/repository"
+	"course-planner-api/internal/service"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AuditSnapshotFunc mengembalikan id dan state terkini entity yang disentuh sebuah request.
+// Dipanggil sebelum dan sesudah handler untuk mengisi before / after di audit log.
+type AuditSnapshotFunc func(c *fiber.Ctx) (entityID string, snapshot interface{})
+
+type AuditHandler struct {
+	service service.AuditService
+}
+
+func NewAuditHandler(s service.AuditService) *AuditHandler {
+	return &AuditHandler{service: s}
+}
+
+// Track adalah middleware per-route yang mencatat request yang berhasil mengubah data.
+// snapshot boleh nil; jika begitu, after diisi dari body response.
+func (h *AuditHandler) Track(entity, action string, snapshot AuditSnapshotFunc) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// preview dan dry-run tidak mengubah data
+		if c.QueryBool("preview") || c.QueryBool("dry_run") {
+			return c.Next()
+		}
+
+		var entityID string
+		var before interface{}
+		if snapshot != nil {
+			entityID, before = snapshot(c)
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusBadRequest {
+			return nil
+		}
+
+		var after interface{}
+		if snapshot != nil {
+			id, state := snapshot(c)
+			if entityID == "" {
+				entityID = id
+			}
+			after = state
+		}
+
+		body := c.Response().Body()
+		if after == nil && action != "delete" && json.Valid(body) {
+			after = json.RawMessage(append([]byte(nil), body...))
+		}
+		if entityID == "" {
+			entityID = entityIDFromBody(body)
+		}
+
+		entry := service.AuditEntry{
+			ActorRole:  getRoleFromContext(c),
+			Action:     action,
+			Entity:     entity,
+			EntityID:   entityID,
+			Before:     before,
+			After:      after,
+			Method:     c.Method(),
+			Path:       c.OriginalURL(),
+			StatusCode: status,
+			IPAddress:  c.IP(),
+			UserAgent:  c.Get(fiber.HeaderUserAgent),
+			RequestID:  c.GetRespHeader(fiber.HeaderXRequestID),
+		}
+		if actorID, err := getUserIDFromContext(c); err == nil {
+			entry.ActorID = &actorID
+		}
+
+		// kegagalan audit tidak boleh menggagalkan request yang sudah sukses
+		if err := h.service.Record(entry); err != nil {
+			log.Printf("failed to record audit log %s.%s: %v", entity, action, err)
+		}
+		return nil
+	}
+}
+
+// ListAuditLogs mendukung filter ?actor_id=, ?actor_role=, ?entity=, ?entity_id=, ?action=,
+// ?from= & ?to= (YYYY-MM-DD atau RFC3339; tanggal "to" inklusif), serta ?limit= & ?offset=
+func (h *AuditHandler) ListAuditLogs(c *fiber.Ctx) error {
+	filter := repository.AuditFilter{
+		ActorRole: c.Query("actor_role"),
+		Entity:    c.Query("entity"),
+		EntityID:  c.Query("entity_id"),
+		Action:    c.Query("action"),
+		Limit:     c.QueryInt("limit"),
+		Offset:    c.QueryInt("offset"),
+	}
+
+	var err error
+	if filter.ActorID, err = parseQueryUUID(c, "actor_id"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if filter.From, err = parseAuditTime(c.Query("from"), false); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid from, expected YYYY-MM-DD or RFC3339"})
+	}
+	if filter.To, err = parseAuditTime(c.Query("to"), true); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid to, expected YYYY-MM-DD or RFC3339"})
+	}
+
+	logs, total, err := h.service.ListAuditLogs(filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": logs, "total": total})
+}
+
+// parseAuditTime menerima tanggal (YYYY-MM-DD) atau timestamp RFC3339.
+// Untuk batas akhir, tanggal saja dianggap sampai akhir hari tersebut.
+func parseAuditTime(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// entityIDFromBody mengambil "id" dari body response create, baik di level atas
+// (contoh: kelas) maupun di dalam objek pembungkus (contoh: {"course": {...}})
+func entityIDFromBody(body []byte) string {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(body, &top); err != nil {
+		return ""
+	}
+	if id := rawString(top["id"]); id != "" {
+		return id
+	}
+	for _, value := range top {
+		var nested map[string]json.RawMessage
+		if err := json.Unmarshal(value, &nested); err == nil {
+			if id := rawString(nested["id"]); id != "" {
+				return id
+			}
+		}
+	}
+	return ""
+}
+
+func rawString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return ""
+	}
+	return s
+}
+
+// AuditIDParam hanya mencatat id dari route param tanpa snapshot state
+func AuditIDParam(param string) AuditSnapshotFunc {
+	return func(c *fiber.Ctx) (string, interface{}) {
+		return c.Params(param), nil
+	}
+}
+
+// snapshotByParam memuat state entity berdasarkan id di route param
+func snapshotByParam(c *fiber.Ctx, param string, load func(id uuid.UUID) (interface{}, error)) (string, interface{}) {
+	id, err := uuid.Parse(c.Params(param))
+	if err != nil {
+		return "", nil
+	}
+	entity, err := load(id)
+	if err != nil {
+		return id.String(), nil
+	}
+	return id.String(), entity
+}
@@ -1,10 +1,15 @@
 package handler
 
 import (
+	"course-planner-api/internal/models"
 	"course-planner-api/internal/service"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
@@ -15,31 +20,57 @@ func NewAuthHandler(authService service.AuthService) *AuthHandler {
 	return &AuthHandler{authService: authService}
 }
 
+// registerRequest hanya untuk mahasiswa yang sudah diimport admin (REGISTRATION_MODE=mahasiswa)
 type registerRequest struct {
-	Name     string `json:"name"`
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	NIM   string `json:"nim"`
+	Email string `json:"email"`
 }
 
 type loginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	DeviceName string `json:"device_name"` // opsional, ditampilkan di daftar sesi
 }
 
+type verifyTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"` // kode TOTP 6 digit atau recovery code
+}
+
+type oidcCallbackRequest struct {
+	Code       string `json:"code"`  // dari query redirect IdP ke OIDC_REDIRECT_URL
+	State      string `json:"state"` // dari query redirect IdP, harus sama dengan hasil authorize
+	DeviceName string `json:"device_name"`
+}
+
+type passwordLoginRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Register meminta link aktivasi untuk akun mahasiswa pending; password diatur lewat /auth/activate
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	var body registerRequest
 	if err := c.BodyParser(&body); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid request"})
 	}
 
-	user, err := h.authService.Register(body.Name, body.Email, body.Password)
+	err := h.authService.Register(service.RegisterInput{
+		NIM:   body.NIM,
+		Email: body.Email,
+	})
 	if err != nil {
+		if errors.Is(err, service.ErrRegistrationDisabled) {
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.Status(http.StatusCreated).JSON(fiber.Map{
-		"message": "User registered successfully",
-		"user":    user,
+	return c.Status(http.StatusAccepted).JSON(fiber.Map{
+		"message": "If the NIM and email match a pending account, an activation link has been sent to the email",
 	})
 }
 
@@ -49,13 +80,335 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid request"})
 	}
 
-	token, user, err := h.authService.Login(body.Email, body.Password)
+	result, err := h.authService.Login(body.Email, body.Password, clientInfo(c, body.DeviceName))
+	if err != nil {
+		return loginError(c, err)
+	}
+
+	return loginResult(c, result)
+}
+
+// OIDCAuthorize memulai login SSO: client mengarahkan user ke authorization_url milik IdP
+func (h *AuthHandler) OIDCAuthorize(c *fiber.Ctx) error {
+	authorization, err := h.authService.StartOIDCLogin()
+	if err != nil {
+		return loginError(c, err)
+	}
+	return c.JSON(authorization)
+}
+
+// OIDCCallback menukar code & state dari redirect IdP dengan token; responsnya sama seperti login
+func (h *AuthHandler) OIDCCallback(c *fiber.Ctx) error {
+	var body oidcCallbackRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid request"})
+	}
+	if body.Code == "" || body.State == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "code and state are required"})
+	}
+
+	result, err := h.authService.LoginWithOIDC(body.Code, body.State, clientInfo(c, body.DeviceName))
+	if err != nil {
+		return loginError(c, err)
+	}
+	return loginResult(c, result)
+}
+
+// loginResult mengirim token, atau challenge untuk akun dengan 2FA: belum ada token dan client
+// melanjutkan ke POST /api/auth/login/verify
+func loginResult(c *fiber.Ctx, result *service.LoginResult) error {
+	if result.Challenge != nil {
+		return c.JSON(fiber.Map{
+			"two_factor_required": true,
+			"challenge_token":     result.Challenge.Token,
+			"expires_at":          result.Challenge.ExpiresAt,
+			"methods":             result.Challenge.Methods,
+		})
+	}
+	return loginSuccess(c, result.Tokens, result.User)
+}
+
+// VerifyTwoFactor adalah langkah kedua login untuk akun dengan 2FA
+func (h *AuthHandler) VerifyTwoFactor(c *fiber.Ctx) error {
+	var body verifyTwoFactorRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid request"})
+	}
+	if body.ChallengeToken == "" || body.Code == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "challenge_token and code are required"})
+	}
+
+	tokens, user, err := h.authService.VerifyTwoFactor(body.ChallengeToken, body.Code)
+	if err != nil {
+		return loginError(c, err)
+	}
+	return loginSuccess(c, tokens, user)
+}
+
+func loginSuccess(c *fiber.Ctx, tokens *service.TokenPair, user *models.User) error {
+	return c.JSON(fiber.Map{
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"session_id":    tokens.SessionID,
+		"user":          user,
+	})
+}
+
+// loginError memetakan error login: 429 + Retry-After untuk percobaan yang dibatasi, 401 untuk kredensial salah,
+// 403 untuk akun yang tidak boleh login dengan cara tersebut
+func loginError(c *fiber.Ctx, err error) error {
+	var throttled *service.LoginThrottledError
+	if errors.As(err, &throttled) {
+		retryAfter := int(math.Ceil(throttled.RetryAfter.Seconds()))
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		return c.Status(http.StatusTooManyRequests).JSON(fiber.Map{
+			"error":       err.Error(),
+			"retry_after": retryAfter,
+		})
+	}
+	if errors.Is(err, service.ErrInvalidCredentials) || errors.Is(err, service.ErrAccountDeactivated) ||
+		errors.Is(err, service.ErrInvalidLoginChallenge) || errors.Is(err, service.ErrInvalidTwoFactorCode) ||
+		errors.Is(err, service.ErrInvalidOIDCState) || errors.Is(err, service.ErrOIDCLoginFailed) {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	switch {
+	case errors.Is(err, service.ErrPasswordLoginDisabled), errors.Is(err, service.ErrOIDCAccountNotFound),
+		errors.Is(err, service.ErrOIDCAccountConflict):
+		return c.Status(http.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrOIDCDisabled):
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrOIDCProviderUnavailable):
+		return c.Status(http.StatusBadGateway).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
+
+// Refresh menukar refresh token dengan access token baru; refresh token lama tidak berlaku lagi
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var body refreshRequest
+	if err := c.BodyParser(&body); err != nil || body.RefreshToken == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "refresh_token is required"})
+	}
+
+	tokens, err := h.authService.Refresh(body.RefreshToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRefreshToken) {
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(tokens)
+}
+
+// Logout mencabut sesi token yang dipakai; access token & refresh token sesi ini langsung ditolak
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	sessionID, err := getSessionIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err := h.authService.Logout(sessionID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// ListSessions menampilkan sesi aktif user per perangkat; sesi token ini ditandai current
+func (h *AuthHandler) ListSessions(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	currentID, _ := getSessionIDFromContext(c)
+
+	sessions, err := h.authService.ListSessions(userID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	data := make([]fiber.Map, 0, len(sessions))
+	for _, session := range sessions {
+		data = append(data, fiber.Map{
+			"id":           session.ID,
+			"device_name":  session.DeviceName,
+			"user_agent":   session.UserAgent,
+			"ip_address":   session.IPAddress,
+			"created_at":   session.CreatedAt,
+			"last_used_at": session.LastUsedAt,
+			"expires_at":   session.ExpiresAt,
+			"current":      session.ID == currentID,
+		})
+	}
+	return c.JSON(data)
+}
+
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	sessionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid session id"})
+	}
+
+	if err := h.authService.RevokeSession(userID, sessionID); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// RevokeOtherSessions mencabut semua sesi user selain sesi token ini
+func (h *AuthHandler) RevokeOtherSessions(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	sessionID, err := getSessionIDFromContext(c)
 	if err != nil {
 		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	revoked, err := h.authService.RevokeOtherSessions(userID, sessionID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
 	return c.JSON(fiber.Map{
-		"token": token,
-		"user":  user,
+		"message": "Other sessions revoked successfully",
+		"revoked": revoked,
 	})
 }
+
+// LoginHistory menampilkan riwayat login (berhasil & gagal) akun sendiri, terbaru dulu
+func (h *AuthHandler) LoginHistory(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	attempts, err := h.authService.LoginHistory(userID, c.QueryInt("limit"))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(attempts)
+}
+
+// SetPasswordLogin mengaktifkan / menonaktifkan login dengan password untuk akun sendiri
+// (akun yang terhubung ke SSO bisa memilih hanya login lewat IdP kampus)
+func (h *AuthHandler) SetPasswordLogin(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var body passwordLoginRequest
+	if err := c.BodyParser(&body); err != nil || body.Enabled == nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "enabled is required"})
+	}
+
+	user, err := h.authService.SetPasswordLogin(userID, *body.Enabled)
+	if err != nil {
+		if errors.Is(err, service.ErrOIDCNotLinked) || errors.Is(err, service.ErrPasswordNotSet) {
+			return c.Status(http.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"password_login_enabled": !user.PasswordLoginDisabled})
+}
+
+// PasswordLoginAuditSnapshot memuat status login password user yang sedang login untuk audit log
+func (h *AuthHandler) PasswordLoginAuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return "", nil
+	}
+	user, err := h.authService.GetAccount(userID)
+	if err != nil {
+		return userID.String(), nil
+	}
+	return userID.String(), fiber.Map{
+		"password_login_enabled": !user.PasswordLoginDisabled,
+		"sso_linked":             user.OIDCSubject != nil,
+	}
+}
+
+// UnlockAccount membuka akun yang terkunci karena gagal login berulang. Izin mengikuti jenis akun:
+// mahasiswa butuh mahasiswa:write, dosen butuh dosen:write, admin butuh role:manage.
+func (h *AuthHandler) UnlockAccount(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid user id"})
+	}
+
+	user, err := h.authService.GetAccount(userID)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if permission := accountPermission(user.Role); !hasPermission(c, permission) {
+		return c.Status(http.StatusForbidden).JSON(fiber.Map{"error": "forbidden - required permission: " + permission})
+	}
+	if !inProgramScope(c, user.StudyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	if err := h.authService.UnlockAccount(userID); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(fiber.Map{"message": "Account unlocked successfully"})
+}
+
+// accountPermission adalah permission untuk mengelola keamanan akun (buka kunci, reset 2FA) sesuai jenis akun
+func accountPermission(accountRole string) string {
+	switch accountRole {
+	case service.ROLE_MAHASISWA:
+		return service.PERM_MAHASISWA_WRITE
+	case service.ROLE_DOSEN:
+		return service.PERM_DOSEN_WRITE
+	default:
+		return service.PERM_ROLE_MANAGE
+	}
+}
+
+// UnlockAuditSnapshot memuat status kunci akun dari param :id untuk audit log
+func (h *AuthHandler) UnlockAuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		user, err := h.authService.GetAccount(id)
+		if err != nil {
+			return nil, err
+		}
+		return fiber.Map{
+			"failed_login_count":   user.FailedLoginCount,
+			"last_failed_login_at": user.LastFailedLoginAt,
+			"locked_until":         user.LockedUntil,
+		}, nil
+	})
+}
+
+// RequireActiveSession dipasang setelah verifikasi JWT: menolak token tanpa sesi atau dari sesi yang sudah dicabut
+func (h *AuthHandler) RequireActiveSession(c *fiber.Ctx) error {
+	sessionID, err := getSessionIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid or expired JWT"})
+	}
+
+	active, err := h.authService.IsSessionActive(sessionID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	if !active {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "session has been revoked"})
+	}
+	return c.Next()
+}
+
+// clientInfo mengambil info perangkat untuk sesi login
+func clientInfo(c *fiber.Ctx, deviceName string) service.ClientInfo {
+	return service.ClientInfo{
+		DeviceName: deviceName,
+		UserAgent:  c.Get(fiber.HeaderUserAgent),
+		IPAddress:  c.IP(),
+	}
+}
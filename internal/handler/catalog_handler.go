@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"course-planner-api/internal/repository"
+	"course-planner-api/internal/service"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// CatalogHandler melayani katalog publik (tanpa autentikasi)
+type CatalogHandler struct {
+	service service.CatalogService
+}
+
+func NewCatalogHandler(s service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{service: s}
+}
+
+// ListCourses mendukung filter ?study_program_id=, ?sks=, ?search= (kode / nama); sort: kode, nama, sks
+func (h *CatalogHandler) ListCourses(c *fiber.Ctx) error {
+	filter := repository.CourseFilter{Search: c.Query("search")}
+
+	var err error
+	if filter.StudyProgramID, err = parseQueryUUID(c, "study_program_id"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if v := c.Query("sks"); v != "" {
+		sks, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid sks"})
+		}
+		filter.SKS = &sks
+	}
+
+	courses, err := h.service.ListCourses(filter, pageQuery(c))
+	if err != nil {
+		return listError(c, err)
+	}
+	return c.JSON(courses)
+}
+
+// GetCourse menampilkan detail course berdasarkan kode beserta silabus yang berlaku dan kelas
+// di ?tahun_akademik= & ?semester= (default semester berjalan)
+func (h *CatalogHandler) GetCourse(c *fiber.Ctx) error {
+	tahunAkademik, semester, err := semesterQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	course, err := h.service.GetCourse(c.Params("kode"), tahunAkademik, semester)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "course not found"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(course)
+}
+
+// ListSchedule menampilkan jadwal kelas di ?semester= (default semester berjalan).
+// Filter lain sama dengan classFilter, ditambah ?study_program_id=
+func (h *CatalogHandler) ListSchedule(c *fiber.Ctx) error {
+	filter, err := classFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	filter.Semester = c.Query("semester", service.GetCurrentSemester())
+	if filter.StudyProgramID, err = parseQueryUUID(c, "study_program_id"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	classes, err := h.service.ListSchedule(filter, pageQuery(c))
+	if err != nil {
+		return listError(c, err)
+	}
+	return c.JSON(fiber.Map{
+		"data":     classes.Data,
+		"total":    classes.Total,
+		"limit":    classes.Limit,
+		"offset":   classes.Offset,
+		"semester": filter.Semester,
+	})
+}
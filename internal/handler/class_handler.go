@@ -1,50 +1,97 @@
 package handler
 
 import (
+	"course-planner-api/internal/repository"
 	"course-planner-api/internal/service"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type ClassHandler struct {
-	classService service.ClassService
+	classService  service.ClassService
+	courseService service.CourseService
 }
 
-func NewClassHandler(classService service.ClassService) *ClassHandler {
-	return &ClassHandler{classService: classService}
+func NewClassHandler(classService service.ClassService, courseService service.CourseService) *ClassHandler {
+	return &ClassHandler{classService: classService, courseService: courseService}
+}
+
+type classDosenRequest struct {
+	DosenID  string   `json:"dosen_id"`
+	Role     string   `json:"role"`      // "coordinator" / "member"
+	SKSShare *float64 `json:"sks_share"` // opsional
 }
 
 type createClassRequest struct {
-	CourseID          string `json:"course_id"`
-	DosenID           string `json:"dosen_id"`
-	NamaKelas         string `json:"nama_kelas"`
-	Hari              string `json:"hari"`
-	JamMulai          string `json:"jam_mulai"`          // format "15:04"
-	JamSelesai        string `json:"jam_selesai"`        // format "15:04"
-	RoomID            string `json:"room_id"`
-	Kuota             int    `json:"kuota"`
-	SemesterPenawaran string `json:"semester_penawaran"` // "ganjil" / "genap"
+	CourseID          string              `json:"course_id"`
+	DosenID           string              `json:"dosen_id"`
+	Dosens            []classDosenRequest `json:"dosens"`
+	NamaKelas         string              `json:"nama_kelas"`
+	Hari              string              `json:"hari"`
+	JamMulai          string              `json:"jam_mulai"`   // format "15:04"
+	JamSelesai        string              `json:"jam_selesai"` // format "15:04"
+	RoomID            string              `json:"room_id"`
+	Kuota             int                 `json:"kuota"`
+	SemesterPenawaran string              `json:"semester_penawaran"` // "ganjil" / "genap"
 }
 
 type updateClassRequest struct {
-	CourseID          *string `json:"course_id"`
-	DosenID           *string `json:"dosen_id"`
-	NamaKelas         *string `json:"nama_kelas"`
-	Hari              *string `json:"hari"`
-	JamMulai          *string `json:"jam_mulai"`          // format "15:04"
-	JamSelesai        *string `json:"jam_selesai"`        // format "15:04"
-	RoomID            *string `json:"room_id"`
-	Kuota             *int    `json:"kuota"`
-	SemesterPenawaran *string `json:"semester_penawaran"` // "ganjil" / "genap"
+	CourseID          *string              `json:"course_id"`
+	DosenID           *string              `json:"dosen_id"`
+	Dosens            *[]classDosenRequest `json:"dosens"`
+	NamaKelas         *string              `json:"nama_kelas"`
+	Hari              *string              `json:"hari"`
+	JamMulai          *string              `json:"jam_mulai"`   // format "15:04"
+	JamSelesai        *string              `json:"jam_selesai"` // format "15:04"
+	RoomID            *string              `json:"room_id"`
+	Kuota             *int                 `json:"kuota"`
+	SemesterPenawaran *string              `json:"semester_penawaran"` // "ganjil" / "genap"
 }
 
 func parseTimeHM(value string) (time.Time, error) {
 	return time.Parse("15:04", value)
 }
 
+func parseClassDosens(body []classDosenRequest) ([]service.ClassDosenInput, error) {
+	dosens := make([]service.ClassDosenInput, 0, len(body))
+	for _, d := range body {
+		dosenID, err := uuid.Parse(d.DosenID)
+		if err != nil {
+			return nil, errors.New("invalid dosens.dosen_id")
+		}
+		dosens = append(dosens, service.ClassDosenInput{
+			DosenID:  dosenID,
+			Role:     d.Role,
+			SKSShare: d.SKSShare,
+		})
+	}
+	return dosens, nil
+}
+
+// classErrorResponse memetakan error dari ClassService ke response HTTP
+func classErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrClassTimeConflict):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "room already used at this time"})
+	case errors.Is(err, service.ErrDosenTimeConflict):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "dosen already teaching at this time"})
+	case errors.Is(err, service.ErrInvalidClassDosens):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": "class or course not found"})
+	}
+	if handled, resp := dependencyConflict(c, err); handled {
+		return resp
+	}
+	return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
+
 func (h *ClassHandler) CreateClass(c *fiber.Ctx) error {
 	var body createClassRequest
 	if err := c.BodyParser(&body); err != nil {
@@ -55,9 +102,16 @@ func (h *ClassHandler) CreateClass(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid course_id"})
 	}
-	dosenID, err := uuid.Parse(body.DosenID)
+	var dosenID uuid.UUID
+	if len(body.Dosens) == 0 || body.DosenID != "" {
+		dosenID, err = uuid.Parse(body.DosenID)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid dosen_id"})
+		}
+	}
+	dosens, err := parseClassDosens(body.Dosens)
 	if err != nil {
-		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid dosen_id"})
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 	roomID, err := uuid.Parse(body.RoomID)
 	if err != nil {
@@ -73,9 +127,14 @@ func (h *ClassHandler) CreateClass(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid jam_selesai, expected HH:MM"})
 	}
 
+	if allowed, resp := h.checkCourseScope(c, courseID); !allowed {
+		return resp
+	}
+
 	input := service.CreateClassInput{
 		CourseID:          courseID,
 		DosenID:           dosenID,
+		Dosens:            dosens,
 		NamaKelas:         body.NamaKelas,
 		Hari:              body.Hari,
 		JamMulai:          jamMulai,
@@ -87,23 +146,74 @@ func (h *ClassHandler) CreateClass(c *fiber.Ctx) error {
 
 	class, err := h.classService.CreateClass(input)
 	if err != nil {
-		if err == service.ErrClassTimeConflict {
-			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "room already used at this time"})
-		}
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return classErrorResponse(c, err)
 	}
 
 	return c.Status(http.StatusCreated).JSON(class)
 }
 
+// ListClasses mendukung filter ?include_deleted=, ?study_program_id=, ?semester= dan filter di classFilter.
+// Sort: kode, nama, sks, nama_kelas, hari, jam_mulai, kuota, semester
 func (h *ClassHandler) ListClasses(c *fiber.Ctx) error {
-	classes, err := h.classService.ListClasses()
+	studyProgramID, err := programFilter(c)
+	if err != nil {
+		return programFilterError(c, err)
+	}
+
+	filter, err := classFilter(c)
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	filter.IncludeDeleted = c.QueryBool("include_deleted")
+	filter.StudyProgramID = studyProgramID
+	filter.Semester = c.Query("semester")
+
+	classes, err := h.classService.ListClasses(filter, pageQuery(c))
+	if err != nil {
+		return listError(c, err)
 	}
 	return c.JSON(classes)
 }
 
+// classFilter membaca filter kelas yang dipakai bersama list admin dan kelas tersedia mahasiswa:
+// ?hari=, ?dosen_id=, ?room_id=, ?course_kode=, ?search= (kode / nama matakuliah / nama kelas)
+// serta rentang waktu ?jam_mulai= & ?jam_selesai= (format "15:04")
+func classFilter(c *fiber.Ctx) (repository.ClassFilter, error) {
+	filter := repository.ClassFilter{
+		Hari:       c.Query("hari"),
+		CourseKode: c.Query("course_kode"),
+		Search:     c.Query("search"),
+	}
+
+	var err error
+	if filter.DosenID, err = parseQueryUUID(c, "dosen_id"); err != nil {
+		return filter, err
+	}
+	if filter.RoomID, err = parseQueryUUID(c, "room_id"); err != nil {
+		return filter, err
+	}
+	if filter.JamMulai, err = queryTimeHM(c, "jam_mulai"); err != nil {
+		return filter, err
+	}
+	if filter.JamSelesai, err = queryTimeHM(c, "jam_selesai"); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// queryTimeHM memvalidasi query jam berformat "15:04"
+func queryTimeHM(c *fiber.Ctx, key string) (string, error) {
+	value := c.Query(key)
+	if value == "" {
+		return "", nil
+	}
+	t, err := parseTimeHM(value)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s, expected HH:MM", key)
+	}
+	return t.Format("15:04"), nil
+}
+
 func (h *ClassHandler) GetClass(c *fiber.Ctx) error {
 	idStr := c.Params("id")
 	id, err := uuid.Parse(idStr)
@@ -115,6 +225,9 @@ func (h *ClassHandler) GetClass(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": "class not found"})
 	}
+	if !inProgramScope(c, class.StudyProgramID) {
+		return outOfProgramScope(c)
+	}
 
 	return c.JSON(class)
 }
@@ -131,6 +244,10 @@ func (h *ClassHandler) UpdateClass(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid request"})
 	}
 
+	if allowed, resp := h.checkClassScope(c, id); !allowed {
+		return resp
+	}
+
 	var input service.UpdateClassInput
 
 	if body.CourseID != nil {
@@ -138,6 +255,9 @@ func (h *ClassHandler) UpdateClass(c *fiber.Ctx) error {
 		if err != nil {
 			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid course_id"})
 		}
+		if allowed, resp := h.checkCourseScope(c, courseID); !allowed {
+			return resp
+		}
 		input.CourseID = &courseID
 	}
 
@@ -149,6 +269,14 @@ func (h *ClassHandler) UpdateClass(c *fiber.Ctx) error {
 		input.DosenID = &dosenID
 	}
 
+	if body.Dosens != nil {
+		dosens, err := parseClassDosens(*body.Dosens)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		input.Dosens = &dosens
+	}
+
 	if body.NamaKelas != nil {
 		input.NamaKelas = body.NamaKelas
 	}
@@ -189,12 +317,17 @@ func (h *ClassHandler) UpdateClass(c *fiber.Ctx) error {
 		input.SemesterPenawaran = body.SemesterPenawaran
 	}
 
+	if c.QueryBool("preview") {
+		impact, err := h.classService.PreviewUpdateClass(id, input)
+		if err != nil {
+			return classErrorResponse(c, err)
+		}
+		return c.JSON(fiber.Map{"preview": true, "impact": impact})
+	}
+
 	class, err := h.classService.UpdateClass(id, input)
 	if err != nil {
-		if err == service.ErrClassTimeConflict {
-			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "room already used at this time"})
-		}
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return classErrorResponse(c, err)
 	}
 
 	return c.JSON(class)
@@ -207,9 +340,75 @@ func (h *ClassHandler) DeleteClass(c *fiber.Ctx) error {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
 	}
 
-	if err := h.classService.DeleteClass(id); err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	if allowed, resp := h.checkClassScope(c, id); !allowed {
+		return resp
+	}
+
+	if c.QueryBool("preview") {
+		impact, err := h.classService.PreviewDeleteClass(id)
+		if err != nil {
+			return classErrorResponse(c, err)
+		}
+		return c.JSON(fiber.Map{"preview": true, "impact": impact})
+	}
+
+	if err := h.classService.DeleteClass(id, c.QueryBool("force")); err != nil {
+		return classErrorResponse(c, err)
 	}
 
 	return c.SendStatus(http.StatusNoContent)
 }
+
+func (h *ClassHandler) RestoreClass(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid id"})
+	}
+
+	class, err := h.classService.RestoreClass(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": "deleted class not found"})
+		}
+		if errors.Is(err, service.ErrClassTimeConflict) || errors.Is(err, service.ErrDosenTimeConflict) {
+			return classErrorResponse(c, err)
+		}
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(class)
+}
+
+// checkClassScope memastikan kelas ada dan berada dalam prodi admin yang login
+func (h *ClassHandler) checkClassScope(c *fiber.Ctx, id uuid.UUID) (allowed bool, resp error) {
+	class, err := h.classService.GetClass(id)
+	if err != nil {
+		return false, c.Status(http.StatusNotFound).JSON(fiber.Map{"error": "class not found"})
+	}
+	if !inProgramScope(c, class.StudyProgramID) {
+		return false, outOfProgramScope(c)
+	}
+	return true, nil
+}
+
+// checkCourseScope memastikan course yang dipakai kelas berada dalam prodi admin yang login
+func (h *ClassHandler) checkCourseScope(c *fiber.Ctx, courseID uuid.UUID) (allowed bool, resp error) {
+	if getStudyProgramScope(c) == nil {
+		return true, nil
+	}
+	course, err := h.courseService.GetCourseByID(courseID)
+	if err != nil {
+		return false, c.Status(http.StatusNotFound).JSON(fiber.Map{"error": "course not found"})
+	}
+	if !inProgramScope(c, course.StudyProgramID) {
+		return false, outOfProgramScope(c)
+	}
+	return true, nil
+}
+
+// AuditSnapshot memuat state kelas dari param :id untuk audit log
+func (h *ClassHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.classService.GetClass(id)
+	})
+}
@@ -4,7 +4,7 @@ import (
 	"errors"
 
 	"github.com/gofiber/fiber/v2"
-	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
@@ -45,4 +45,55 @@ func getUserIDFromContext(c *fiber.Ctx) (uuid.UUID, error) {
 	}
 
 	return userID, nil
-}
\ No newline at end of file
+}
+
+// getRoleFromContext mengambil role dari JWT claims; string kosong jika tidak ada token
+func getRoleFromContext(c *fiber.Ctx) string {
+	userToken, ok := c.Locals("user").(*jwt.Token)
+	if !ok || userToken == nil {
+		return ""
+	}
+	claims, ok := userToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+	role, _ := claims["role"].(string)
+	return role
+}
+
+// hasPermission memeriksa apakah claim permissions di JWT memuat permission tertentu
+func hasPermission(c *fiber.Ctx, permission string) bool {
+	userToken, ok := c.Locals("user").(*jwt.Token)
+	if !ok || userToken == nil {
+		return false
+	}
+	claims, ok := userToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return false
+	}
+	permissions, _ := claims["permissions"].([]interface{})
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// getSessionIDFromContext mengambil claim sid (sesi login) dari JWT
+func getSessionIDFromContext(c *fiber.Ctx) (uuid.UUID, error) {
+	userToken, ok := c.Locals("user").(*jwt.Token)
+	if !ok || userToken == nil {
+		return uuid.Nil, errors.New("Token tidak ditemukan")
+	}
+	claims, ok := userToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return uuid.Nil, errors.New("Claims token tidak valid")
+	}
+	sid, _ := claims["sid"].(string)
+	sessionID, err := uuid.Parse(sid)
+	if err != nil {
+		return uuid.Nil, errors.New("Sesi ('sid') tidak ditemukan di token")
+	}
+	return sessionID, nil
+}
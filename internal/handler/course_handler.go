@@ -1,42 +1,84 @@
 package handler
 
 import (
+	"course-planner-api/internal/repository"
 	"course-planner-api/internal/service"
+	"errors"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type CourseHandler struct {
-	service service.CourseService
+	service         service.CourseService
+	syllabusService service.SyllabusService
 }
 
-func NewCourseHandler(s service.CourseService) *CourseHandler {
-	return &CourseHandler{service: s}
+func NewCourseHandler(s service.CourseService, syllabusService service.SyllabusService) *CourseHandler {
+	return &CourseHandler{service: s, syllabusService: syllabusService}
 }
 
 type CreateCourseRequest struct {
-	Kode string `json:"kode"`
-	Nama string `json:"nama"`
-	SKS  int    `json:"sks"`
+	Kode            string   `json:"kode"`
+	Nama            string   `json:"nama"`
+	SKS             int      `json:"sks"`
+	Deskripsi       string   `json:"deskripsi"`
+	StudyProgramID  *string  `json:"study_program_id,omitempty"`
+	PrerequisiteIDs []string `json:"prerequisite_ids,omitempty"`
 }
 
 type UpdateCourseRequest struct {
-	Kode *string `json:"kode,omitempty"`
-	Nama *string `json:"nama,omitempty"`
-	SKS  *int    `json:"sks,omitempty"`
+	Kode            *string   `json:"kode,omitempty"`
+	Nama            *string   `json:"nama,omitempty"`
+	SKS             *int      `json:"sks,omitempty"`
+	Deskripsi       *string   `json:"deskripsi,omitempty"`
+	StudyProgramID  *string   `json:"study_program_id,omitempty"`
+	PrerequisiteIDs *[]string `json:"prerequisite_ids,omitempty"` // [] menghapus seluruh prasyarat
+}
+
+func parsePrerequisiteIDs(values []string) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, v := range values {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, errors.New("invalid prerequisite_ids")
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
 
 // ListCourses godoc
 // @Summary List all courses
+// @Description Filter: ?include_deleted=, ?study_program_id=, ?sks=, ?search= (kode / nama); sort: kode, nama, sks
 // @Tags Admin - Courses
 // @Security BearerAuth
-// @Success 200 {array} models.Course
+// @Success 200 {object} repository.Page[models.Course]
 // @Router /api/admin/courses [get]
 func (h *CourseHandler) ListCourses(c *fiber.Ctx) error {
-	courses, err := h.service.GetAllCourses()
+	studyProgramID, err := programFilter(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return programFilterError(c, err)
+	}
+
+	filter := repository.CourseFilter{
+		IncludeDeleted: c.QueryBool("include_deleted"),
+		StudyProgramID: studyProgramID,
+		Search:         c.Query("search"),
+	}
+	if v := c.Query("sks"); v != "" {
+		sks, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid sks"})
+		}
+		filter.SKS = &sks
+	}
+
+	courses, err := h.service.GetAllCourses(filter, pageQuery(c))
+	if err != nil {
+		return listError(c, err)
 	}
 	return c.JSON(courses)
 }
@@ -51,7 +93,31 @@ func (h *CourseHandler) CreateCourse(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "kode, nama, and sks are required (sks > 0)"})
 	}
 
-	course, err := h.service.CreateCourse(req.Kode, req.Nama, req.SKS)
+	studyProgramID, err := parseOptionalUUID(req.StudyProgramID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study_program_id"})
+	}
+	// Admin prodi hanya bisa membuat course untuk prodinya sendiri
+	if studyProgramID == nil {
+		studyProgramID = getStudyProgramScope(c)
+	}
+	if !inProgramScope(c, studyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	prerequisiteIDs, err := parsePrerequisiteIDs(req.PrerequisiteIDs)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	course, err := h.service.CreateCourse(service.CreateCourseInput{
+		Kode:            req.Kode,
+		Nama:            req.Nama,
+		SKS:             req.SKS,
+		Deskripsi:       req.Deskripsi,
+		StudyProgramID:  studyProgramID,
+		PrerequisiteIDs: prerequisiteIDs,
+	})
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -62,16 +128,31 @@ func (h *CourseHandler) CreateCourse(c *fiber.Ctx) error {
 	})
 }
 
+// GetCourse menampilkan detail course beserta silabus yang berlaku di ?tahun_akademik= & ?semester=
+// (default semester berjalan)
 func (h *CourseHandler) GetCourse(c *fiber.Ctx) error {
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid course id"})
 	}
+	tahunAkademik, semester, err := semesterQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
 
 	course, err := h.service.GetCourseByID(id)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "course not found"})
 	}
+	if !inProgramScope(c, course.StudyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	syllabus, err := h.syllabusService.GetEffectiveSyllabus(id, tahunAkademik, semester)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	course.Syllabus = syllabus
 
 	return c.JSON(course)
 }
@@ -87,7 +168,33 @@ func (h *CourseHandler) UpdateCourse(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
 	}
 
-	course, err := h.service.UpdateCourse(id, req.Kode, req.Nama, req.SKS)
+	studyProgramID, err := parseOptionalUUID(req.StudyProgramID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study_program_id"})
+	}
+	if allowed, resp := h.checkCourseScope(c, id); !allowed {
+		return resp
+	}
+	if studyProgramID != nil && !inProgramScope(c, studyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	input := service.UpdateCourseInput{
+		Kode:           req.Kode,
+		Nama:           req.Nama,
+		SKS:            req.SKS,
+		Deskripsi:      req.Deskripsi,
+		StudyProgramID: studyProgramID,
+	}
+	if req.PrerequisiteIDs != nil {
+		prerequisiteIDs, err := parsePrerequisiteIDs(*req.PrerequisiteIDs)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		input.PrerequisiteIDs = &prerequisiteIDs
+	}
+
+	course, err := h.service.UpdateCourse(id, input)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -104,9 +211,52 @@ func (h *CourseHandler) DeleteCourse(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid course id"})
 	}
 
+	if allowed, resp := h.checkCourseScope(c, id); !allowed {
+		return resp
+	}
+
 	if err := h.service.DeleteCourse(id); err != nil {
+		if handled, resp := dependencyConflict(c, err); handled {
+			return resp
+		}
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+func (h *CourseHandler) RestoreCourse(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid course id"})
+	}
+
+	course, err := h.service.RestoreCourse(id)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Course restored successfully",
+		"course":  course,
+	})
+}
+
+// checkCourseScope memastikan course ada dan berada dalam prodi admin yang login
+func (h *CourseHandler) checkCourseScope(c *fiber.Ctx, id uuid.UUID) (allowed bool, resp error) {
+	course, err := h.service.GetCourseByID(id)
+	if err != nil {
+		return false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "course not found"})
+	}
+	if !inProgramScope(c, course.StudyProgramID) {
+		return false, outOfProgramScope(c)
+	}
+	return true, nil
+}
+
+// AuditSnapshot memuat state course dari param :id untuk audit log
+func (h *CourseHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.service.GetCourseByID(id)
+	})
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CurriculumHandler struct {
+	service service.CurriculumService
+}
+
+func NewCurriculumHandler(s service.CurriculumService) *CurriculumHandler {
+	return &CurriculumHandler{service: s}
+}
+
+type CreateCurriculumRequest struct {
+	StudyProgramID *string `json:"study_program_id,omitempty"`
+	Tahun          int     `json:"tahun"`
+	Nama           string  `json:"nama"`
+	MinSKSLulus    int     `json:"min_sks_lulus"`
+	MinIPK         float64 `json:"min_ipk"`
+}
+
+type UpdateCurriculumRequest struct {
+	Tahun       *int     `json:"tahun,omitempty"`
+	Nama        *string  `json:"nama,omitempty"`
+	MinSKSLulus *int     `json:"min_sks_lulus,omitempty"`
+	MinIPK      *float64 `json:"min_ipk,omitempty"`
+}
+
+// validGraduationRule memeriksa syarat lulus kurikulum (min_sks_lulus >= 0, 0 <= min_ipk <= 4)
+func validGraduationRule(minSKSLulus *int, minIPK *float64) bool {
+	if minSKSLulus != nil && *minSKSLulus < 0 {
+		return false
+	}
+	if minIPK != nil && (*minIPK < 0 || *minIPK > service.MAX_IPK) {
+		return false
+	}
+	return true
+}
+
+type ElectiveGroupRequest struct {
+	Nama   string `json:"nama"`
+	MinSKS int    `json:"min_sks"`
+}
+
+type CurriculumCourseRequest struct {
+	CourseID            string `json:"course_id"`
+	Jenis               string `json:"jenis"`
+	SemesterRekomendasi int    `json:"semester_rekomendasi"`
+	ElectiveGroup       string `json:"elective_group,omitempty"` // nama kelompok di elective_groups
+}
+
+type ReplaceCurriculumCoursesRequest struct {
+	ElectiveGroups []ElectiveGroupRequest    `json:"elective_groups"`
+	Courses        []CurriculumCourseRequest `json:"courses"`
+}
+
+// ListCurricula mendukung filter ?study_program_id=
+func (h *CurriculumHandler) ListCurricula(c *fiber.Ctx) error {
+	studyProgramID, err := programFilter(c)
+	if err != nil {
+		return programFilterError(c, err)
+	}
+
+	curricula, err := h.service.ListCurricula(studyProgramID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(curricula)
+}
+
+func (h *CurriculumHandler) CreateCurriculum(c *fiber.Ctx) error {
+	var req CreateCurriculumRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	studyProgramID, err := parseOptionalUUID(req.StudyProgramID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study_program_id"})
+	}
+	// Admin prodi hanya bisa membuat kurikulum untuk prodinya sendiri
+	if studyProgramID == nil {
+		studyProgramID = getStudyProgramScope(c)
+	}
+	if studyProgramID == nil || req.Tahun <= 0 || req.Nama == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "study_program_id, tahun, and nama are required"})
+	}
+	if !validGraduationRule(&req.MinSKSLulus, &req.MinIPK) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "min_sks_lulus must not be negative and min_ipk must be between 0 and 4"})
+	}
+	if !inProgramScope(c, studyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	curriculum, err := h.service.CreateCurriculum(service.CreateCurriculumInput{
+		StudyProgramID: *studyProgramID,
+		Tahun:          req.Tahun,
+		Nama:           req.Nama,
+		MinSKSLulus:    req.MinSKSLulus,
+		MinIPK:         req.MinIPK,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":    "Curriculum created successfully",
+		"curriculum": curriculum,
+	})
+}
+
+func (h *CurriculumHandler) GetCurriculum(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid curriculum id"})
+	}
+
+	curriculum, err := h.service.GetCurriculumByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "curriculum not found"})
+	}
+	if !inProgramScope(c, &curriculum.StudyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	return c.JSON(curriculum)
+}
+
+func (h *CurriculumHandler) UpdateCurriculum(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid curriculum id"})
+	}
+
+	var req UpdateCurriculumRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Tahun != nil && *req.Tahun <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid tahun"})
+	}
+	if !validGraduationRule(req.MinSKSLulus, req.MinIPK) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "min_sks_lulus must not be negative and min_ipk must be between 0 and 4"})
+	}
+
+	if allowed, resp := h.checkCurriculumScope(c, id); !allowed {
+		return resp
+	}
+
+	curriculum, err := h.service.UpdateCurriculum(id, service.UpdateCurriculumInput{
+		Tahun:       req.Tahun,
+		Nama:        req.Nama,
+		MinSKSLulus: req.MinSKSLulus,
+		MinIPK:      req.MinIPK,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":    "Curriculum updated successfully",
+		"curriculum": curriculum,
+	})
+}
+
+func (h *CurriculumHandler) DeleteCurriculum(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid curriculum id"})
+	}
+
+	if allowed, resp := h.checkCurriculumScope(c, id); !allowed {
+		return resp
+	}
+
+	if err := h.service.DeleteCurriculum(id); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ReplaceCourses mengganti seluruh kelompok pilihan dan course kurikulum
+func (h *CurriculumHandler) ReplaceCourses(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid curriculum id"})
+	}
+
+	var req ReplaceCurriculumCoursesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	if allowed, resp := h.checkCurriculumScope(c, id); !allowed {
+		return resp
+	}
+
+	groups := make([]service.ElectiveGroupInput, 0, len(req.ElectiveGroups))
+	for _, g := range req.ElectiveGroups {
+		groups = append(groups, service.ElectiveGroupInput{Nama: g.Nama, MinSKS: g.MinSKS})
+	}
+	courses := make([]service.CurriculumCourseInput, 0, len(req.Courses))
+	for _, cc := range req.Courses {
+		courseID, err := uuid.Parse(cc.CourseID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid course_id"})
+		}
+		courses = append(courses, service.CurriculumCourseInput{
+			CourseID:            courseID,
+			Jenis:               cc.Jenis,
+			SemesterRekomendasi: cc.SemesterRekomendasi,
+			ElectiveGroup:       cc.ElectiveGroup,
+		})
+	}
+
+	curriculum, err := h.service.ReplaceStructure(id, groups, courses)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":    "Curriculum courses updated successfully",
+		"curriculum": curriculum,
+	})
+}
+
+// GetMyCurriculum menampilkan kurikulum mahasiswa yang login beserta rencana per semester
+func (h *CurriculumHandler) GetMyCurriculum(c *fiber.Ctx) error {
+	mahasiswaID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	curriculum, err := h.service.GetStudentCurriculum(mahasiswaID)
+	if err != nil {
+		if errors.Is(err, service.ErrCurriculumNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(curriculum)
+}
+
+// checkCurriculumScope memastikan kurikulum ada dan berada dalam prodi admin yang login
+func (h *CurriculumHandler) checkCurriculumScope(c *fiber.Ctx, id uuid.UUID) (allowed bool, resp error) {
+	curriculum, err := h.service.GetCurriculumByID(id)
+	if err != nil {
+		return false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "curriculum not found"})
+	}
+	if !inProgramScope(c, &curriculum.StudyProgramID) {
+		return false, outOfProgramScope(c)
+	}
+	return true, nil
+}
+
+// AuditSnapshot memuat state kurikulum dari param :id untuk audit log
+func (h *CurriculumHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.service.GetCurriculumByID(id)
+	})
+}
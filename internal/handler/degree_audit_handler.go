@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DegreeAuditHandler menampilkan audit kelulusan mahasiswa terhadap kurikulumnya
+type DegreeAuditHandler struct {
+	service          service.DegreeAuditService
+	mahasiswaService service.MahasiswaManagementService
+}
+
+func NewDegreeAuditHandler(s service.DegreeAuditService, mahasiswaService service.MahasiswaManagementService) *DegreeAuditHandler {
+	return &DegreeAuditHandler{service: s, mahasiswaService: mahasiswaService}
+}
+
+// GetMyDegreeAudit menampilkan audit kelulusan mahasiswa yang login
+func (h *DegreeAuditHandler) GetMyDegreeAudit(c *fiber.Ctx) error {
+	mahasiswaID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	audit, err := h.service.GetDegreeAudit(mahasiswaID)
+	if err != nil {
+		return degreeAuditError(c, err)
+	}
+	return c.JSON(audit)
+}
+
+// GetAdviseeDegreeAudit menampilkan profil dan audit kelulusan mahasiswa bimbingan dosen PA
+func (h *DegreeAuditHandler) GetAdviseeDegreeAudit(c *fiber.Ctx) error {
+	dosenID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized", "details": err.Error()})
+	}
+
+	mahasiswaID, err := uuid.Parse(c.Params("mahasiswaId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Mahasiswa ID tidak valid"})
+	}
+
+	mahasiswa, audit, err := h.service.GetAdviseeDegreeAudit(dosenID, mahasiswaID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotAdvisee) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+		}
+		return degreeAuditError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"mahasiswa":    mahasiswa,
+		"degree_audit": audit,
+	})
+}
+
+// GetMahasiswaDegreeAudit menampilkan audit kelulusan satu mahasiswa untuk admin
+func (h *DegreeAuditHandler) GetMahasiswaDegreeAudit(c *fiber.Ctx) error {
+	mahasiswaID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid mahasiswa id"})
+	}
+
+	// scope prodi dicek sebelum audit dibuat agar error audit (misal kurikulum tidak ada)
+	// tidak membocorkan data mahasiswa prodi lain
+	mahasiswa, err := h.mahasiswaService.GetMahasiswaByID(mahasiswaID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "mahasiswa not found"})
+	}
+	if !inProgramScope(c, mahasiswa.StudyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	audit, err := h.service.GetDegreeAudit(mahasiswaID)
+	if err != nil {
+		return degreeAuditError(c, err)
+	}
+	return c.JSON(audit)
+}
+
+// ListEligibleStudents adalah laporan batch mahasiswa yang memenuhi syarat lulus.
+// Mendukung ?study_program_id=, ?angkatan=, ?search= dan ?is_active= (default hanya mahasiswa aktif).
+func (h *DegreeAuditHandler) ListEligibleStudents(c *fiber.Ctx) error {
+	studyProgramID, err := programFilter(c)
+	if err != nil {
+		return programFilterError(c, err)
+	}
+
+	filter, err := mahasiswaFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	filter.StudyProgramID = studyProgramID
+	if filter.IsActive == nil {
+		active := true
+		filter.IsActive = &active
+	}
+
+	students, err := h.service.ListEligibleStudents(filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  students,
+		"total": len(students),
+	})
+}
+
+// degreeAuditError memetakan error audit kelulusan ke response HTTP
+func degreeAuditError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, service.ErrCurriculumNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "mahasiswa not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"course-planner-api/internal/repository"
 	"course-planner-api/internal/service"
 	"errors"
 	"strings"
@@ -18,18 +19,27 @@ func NewDosenHandler(service *service.DosenPAService) *DosenHandler {
 	return &DosenHandler{Service: service}
 }
 
+// ListStudents mendukung filter yang sama dengan list mahasiswa admin (lihat mahasiswaFilter)
 func (h *DosenHandler) ListStudents(c *fiber.Ctx) error {
 	dosenID, err := getUserIDFromContext(c)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized", "details": err.Error()})
 	}
 
-	students, err := h.Service.ListMahasiswa(dosenID)
+	filter, err := mahasiswaFilter(c)
 	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	students, err := h.Service.ListMahasiswa(dosenID, filter, pageQuery(c))
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidSort) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengambil daftar mahasiswa", "details": err.Error()})
 	}
 
-	return c.JSON(fiber.Map{"data": students})
+	return c.JSON(students)
 }
 
 func (h *DosenHandler) GetMahasiswaKRS(c *fiber.Ctx) error {
@@ -175,4 +185,15 @@ func (h *DosenHandler) updateStatus(c *fiber.Ctx, approve bool) error {
 		return c.JSON(fiber.Map{"message": "Matakuliah disetujui."})
 	}
 	return c.JSON(fiber.Map{"message": "Matakuliah ditolak."})
-}
\ No newline at end of file
+}
+
+// AuditSnapshot memuat KRS mahasiswa bimbingan dari param :mahasiswaId untuk audit log
+func (h *DosenHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	dosenID, err := getUserIDFromContext(c)
+	if err != nil {
+		return "", nil
+	}
+	return snapshotByParam(c, "mahasiswaId", func(mahasiswaID uuid.UUID) (interface{}, error) {
+		return h.Service.GetMahasiswaKRS(dosenID, mahasiswaID)
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"course-planner-api/internal/repository"
 	"course-planner-api/internal/service"
 
 	"github.com/gofiber/fiber/v2"
@@ -8,26 +9,90 @@ import (
 )
 
 type DosenManagementHandler struct {
-	service service.DosenManagementService
+	service      service.DosenManagementService
+	classService service.ClassService
 }
 
-func NewDosenManagementHandler(s service.DosenManagementService) *DosenManagementHandler {
-	return &DosenManagementHandler{service: s}
+func NewDosenManagementHandler(s service.DosenManagementService, classService service.ClassService) *DosenManagementHandler {
+	return &DosenManagementHandler{service: s, classService: classService}
+}
+
+type CreateDosenRequest struct {
+	Name           string  `json:"name"`
+	Email          string  `json:"email"`
+	Password       string  `json:"password"`
+	NIDN           string  `json:"nidn"`
+	KapasitasPA    int     `json:"kapasitas_pa"`
+	StudyProgramID *string `json:"study_program_id,omitempty"`
 }
 
 type UpdateDosenRequest struct {
-	Name *string `json:"name,omitempty"`
-	NIDN *string `json:"nidn,omitempty"`
+	Name           *string `json:"name,omitempty"`
+	NIDN           *string `json:"nidn,omitempty"`
+	KapasitasPA    *int    `json:"kapasitas_pa,omitempty"`
+	StudyProgramID *string `json:"study_program_id,omitempty"`
 }
 
+// ListDosen mendukung filter ?study_program_id= dan ?search= (nama / NIDN).
+// Sort: name, nidn, kapasitas_pa, created_at
 func (h *DosenManagementHandler) ListDosen(c *fiber.Ctx) error {
-	dosens, err := h.service.GetAllDosen()
+	studyProgramID, err := programFilter(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return programFilterError(c, err)
+	}
+
+	filter := repository.DosenFilter{
+		StudyProgramID: studyProgramID,
+		Search:         c.Query("search"),
+	}
+
+	dosens, err := h.service.GetAllDosen(filter, pageQuery(c))
+	if err != nil {
+		return listError(c, err)
 	}
 	return c.JSON(dosens)
 }
 
+func (h *DosenManagementHandler) CreateDosen(c *fiber.Ctx) error {
+	var req CreateDosenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	if req.Name == "" || req.Email == "" || req.Password == "" || req.NIDN == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name, email, password, and nidn are required"})
+	}
+
+	studyProgramID, err := parseOptionalUUID(req.StudyProgramID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study_program_id"})
+	}
+	// Admin prodi hanya bisa menambahkan dosen ke prodinya sendiri
+	if studyProgramID == nil {
+		studyProgramID = getStudyProgramScope(c)
+	}
+	if !inProgramScope(c, studyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	dosen, err := h.service.CreateDosen(service.CreateDosenInput{
+		Name:           req.Name,
+		Email:          req.Email,
+		Password:       req.Password,
+		NIDN:           req.NIDN,
+		KapasitasPA:    req.KapasitasPA,
+		StudyProgramID: studyProgramID,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Dosen created successfully",
+		"dosen":   dosen,
+	})
+}
+
 func (h *DosenManagementHandler) GetDosen(c *fiber.Ctx) error {
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
@@ -38,10 +103,36 @@ func (h *DosenManagementHandler) GetDosen(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "dosen not found"})
 	}
+	if !inProgramScope(c, dosen.StudyProgramID) {
+		return outOfProgramScope(c)
+	}
 
 	return c.JSON(dosen)
 }
 
+// GetTeachingLoad menampilkan kelas yang diampu dosen beserta total porsi SKS-nya
+func (h *DosenManagementHandler) GetTeachingLoad(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid dosen id"})
+	}
+
+	dosen, err := h.service.GetDosenByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "dosen not found"})
+	}
+	if !inProgramScope(c, dosen.StudyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	load, err := h.classService.GetTeachingLoad(dosen.ID, c.Query("semester"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(load)
+}
+
 func (h *DosenManagementHandler) UpdateDosen(c *fiber.Ctx) error {
 	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
@@ -53,7 +144,25 @@ func (h *DosenManagementHandler) UpdateDosen(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
 	}
 
-	dosen, err := h.service.UpdateDosen(id, req.Name, req.NIDN)
+	studyProgramID, err := parseOptionalUUID(req.StudyProgramID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study_program_id"})
+	}
+
+	existing, err := h.service.GetDosenByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "dosen not found"})
+	}
+	if !inProgramScope(c, existing.StudyProgramID) || (studyProgramID != nil && !inProgramScope(c, studyProgramID)) {
+		return outOfProgramScope(c)
+	}
+
+	dosen, err := h.service.UpdateDosen(id, service.UpdateDosenInput{
+		Name:           req.Name,
+		NIDN:           req.NIDN,
+		KapasitasPA:    req.KapasitasPA,
+		StudyProgramID: studyProgramID,
+	})
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
@@ -63,3 +172,10 @@ func (h *DosenManagementHandler) UpdateDosen(c *fiber.Ctx) error {
 		"dosen":   dosen,
 	})
 }
+
+// AuditSnapshot memuat state dosen dari param :id untuk audit log
+func (h *DosenManagementHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.service.GetDosenByID(id)
+	})
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type DosenPAAssignmentHandler struct {
+	service service.DosenPAAssignmentService
+}
+
+func NewDosenPAAssignmentHandler(s service.DosenPAAssignmentService) *DosenPAAssignmentHandler {
+	return &DosenPAAssignmentHandler{service: s}
+}
+
+type AssignDosenPARequest struct {
+	DosenPAID string `json:"dosen_pa_id"`
+}
+
+type BulkAssignDosenPARequest struct {
+	DosenPAID    string   `json:"dosen_pa_id"`
+	MahasiswaIDs []string `json:"mahasiswa_ids"`
+}
+
+// AssignAdvisee menugaskan / memindahkan satu mahasiswa ke dosen PA
+func (h *DosenPAAssignmentHandler) AssignAdvisee(c *fiber.Ctx) error {
+	mahasiswaID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid mahasiswa id"})
+	}
+
+	var req AssignDosenPARequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	dosenID, err := uuid.Parse(req.DosenPAID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid dosen_pa_id"})
+	}
+
+	result, err := h.service.AssignAdvisees(dosenID, []uuid.UUID{mahasiswaID})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Dosen PA assigned successfully",
+		"result":  result,
+	})
+}
+
+// BulkAssignAdvisees menugaskan banyak mahasiswa sekaligus ke satu dosen PA
+func (h *DosenPAAssignmentHandler) BulkAssignAdvisees(c *fiber.Ctx) error {
+	var req BulkAssignDosenPARequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	dosenID, err := uuid.Parse(req.DosenPAID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid dosen_pa_id"})
+	}
+
+	if len(req.MahasiswaIDs) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "mahasiswa_ids is required"})
+	}
+
+	mahasiswaIDs := make([]uuid.UUID, 0, len(req.MahasiswaIDs))
+	for _, idStr := range req.MahasiswaIDs {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid mahasiswa id", "details": idStr})
+		}
+		mahasiswaIDs = append(mahasiswaIDs, id)
+	}
+
+	result, err := h.service.AssignAdvisees(dosenID, mahasiswaIDs)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Dosen PA assigned successfully",
+		"result":  result,
+	})
+}
+
+func (h *DosenPAAssignmentHandler) GetWorkload(c *fiber.Ctx) error {
+	counts, unassigned, err := h.service.GetWorkload()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":       counts,
+		"unassigned": unassigned,
+	})
+}
+
+func (h *DosenPAAssignmentHandler) AutoBalance(c *fiber.Ctx) error {
+	result, err := h.service.AutoBalance()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(result)
+}
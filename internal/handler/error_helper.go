@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// dependencyConflict mengembalikan response 409 beserta jumlah data yang masih mereferensikan entity
+// jika err adalah service.DependencyError. handled bernilai false untuk error lain.
+func dependencyConflict(c *fiber.Ctx, err error) (handled bool, resp error) {
+	var depErr *service.DependencyError
+	if !errors.As(err, &depErr) {
+		return false, nil
+	}
+	return true, c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error":      depErr.Error(),
+		"dependents": depErr.Dependents,
+	})
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"bufio"
+	"course-planner-api/internal/export"
+	"course-planner-api/internal/repository"
+	"course-planner-api/internal/service"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ExportHandler struct {
+	service      service.ExportService
+	classService service.ClassService
+}
+
+func NewExportHandler(s service.ExportService, classService service.ClassService) *ExportHandler {
+	return &ExportHandler{service: s, classService: classService}
+}
+
+// ExportClassRoster mengekspor mahasiswa yang terdaftar di sebuah kelas
+func (h *ExportHandler) ExportClassRoster(c *fiber.Ctx) error {
+	format, ok := exportFormat(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": export.ErrUnsupportedFormat.Error()})
+	}
+
+	classID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid class id"})
+	}
+
+	class, err := h.classService.GetClass(classID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "class not found"})
+	}
+	if !inProgramScope(c, class.StudyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	filename := fmt.Sprintf("roster-%s-%s", class.Course.Kode, class.NamaKelas)
+	return streamExport(c, format, filename, func(w io.Writer) error {
+		return h.service.ExportClassRoster(w, format, classID)
+	})
+}
+
+// ExportKRSItems mengekspor seluruh item KRS satu semester.
+// Filter: ?semester= (default semester berjalan), ?status=, ?study_program_id=, ?dosen_pa_id=, ?class_id=
+func (h *ExportHandler) ExportKRSItems(c *fiber.Ctx) error {
+	format, ok := exportFormat(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": export.ErrUnsupportedFormat.Error()})
+	}
+
+	studyProgramID, err := programFilter(c)
+	if err != nil {
+		return programFilterError(c, err)
+	}
+
+	filter := repository.KRSItemExportFilter{
+		Semester:       c.Query("semester", service.GetCurrentSemester()),
+		Status:         c.Query("status"),
+		StudyProgramID: studyProgramID,
+	}
+	if filter.DosenPAID, err = parseQueryUUID(c, "dosen_pa_id"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if filter.ClassID, err = parseQueryUUID(c, "class_id"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return streamExport(c, format, "krs-items-"+filter.Semester, func(w io.Writer) error {
+		return h.service.ExportKRSItems(w, format, filter)
+	})
+}
+
+// ExportRoomSchedules mengekspor jadwal pemakaian ruangan.
+// Filter: ?semester=, ?room_id=, ?dosen_id=, ?study_program_id=, ?include_deleted=
+func (h *ExportHandler) ExportRoomSchedules(c *fiber.Ctx) error {
+	format, ok := exportFormat(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": export.ErrUnsupportedFormat.Error()})
+	}
+
+	filter, err := scheduleExportFilter(c)
+	if err != nil {
+		return programFilterError(c, err)
+	}
+
+	return streamExport(c, format, "room-schedules", func(w io.Writer) error {
+		return h.service.ExportRoomSchedules(w, format, *filter)
+	})
+}
+
+// ExportTeachingList mengekspor daftar kelas yang diampu setiap dosen (termasuk team teaching).
+// Filter sama dengan ExportRoomSchedules.
+func (h *ExportHandler) ExportTeachingList(c *fiber.Ctx) error {
+	format, ok := exportFormat(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": export.ErrUnsupportedFormat.Error()})
+	}
+
+	filter, err := scheduleExportFilter(c)
+	if err != nil {
+		return programFilterError(c, err)
+	}
+
+	return streamExport(c, format, "teaching-lists", func(w io.Writer) error {
+		return h.service.ExportTeachingList(w, format, *filter)
+	})
+}
+
+// scheduleExportFilter membaca filter jadwal dari query
+func scheduleExportFilter(c *fiber.Ctx) (*repository.ScheduleExportFilter, error) {
+	studyProgramID, err := programFilter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &repository.ScheduleExportFilter{
+		Semester:       c.Query("semester"),
+		StudyProgramID: studyProgramID,
+		IncludeDeleted: c.QueryBool("include_deleted"),
+	}
+	if filter.RoomID, err = parseQueryUUID(c, "room_id"); err != nil {
+		return nil, err
+	}
+	if filter.DosenID, err = parseQueryUUID(c, "dosen_id"); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+func exportFormat(c *fiber.Ctx) (string, bool) {
+	format := c.Query("format", export.FORMAT_CSV)
+	return format, export.IsSupported(format)
+}
+
+func parseQueryUUID(c *fiber.Ctx, key string) (*uuid.UUID, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &id, nil
+}
+
+// streamExport mengirim file export sebagai response streaming.
+// Data ditulis setelah handler selesai, jadi error di tengah stream hanya bisa dicatat di log.
+func streamExport(c *fiber.Ctx, format, filename string, write func(w io.Writer) error) error {
+	c.Set(fiber.HeaderContentType, export.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := write(w); err != nil {
+			log.Printf("export %s failed: %v", filename, err)
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("export %s flush failed: %v", filename, err)
+		}
+	})
+	return nil
+}
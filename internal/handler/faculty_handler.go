@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type FacultyHandler struct {
+	service service.FacultyService
+}
+
+func NewFacultyHandler(s service.FacultyService) *FacultyHandler {
+	return &FacultyHandler{service: s}
+}
+
+type CreateFacultyRequest struct {
+	Kode string `json:"kode"`
+	Nama string `json:"nama"`
+}
+
+type UpdateFacultyRequest struct {
+	Kode *string `json:"kode,omitempty"`
+	Nama *string `json:"nama,omitempty"`
+}
+
+func (h *FacultyHandler) ListFaculties(c *fiber.Ctx) error {
+	faculties, err := h.service.GetAllFaculties()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(faculties)
+}
+
+func (h *FacultyHandler) CreateFaculty(c *fiber.Ctx) error {
+	var req CreateFacultyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	if req.Kode == "" || req.Nama == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "kode and nama are required"})
+	}
+
+	faculty, err := h.service.CreateFaculty(req.Kode, req.Nama)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Faculty created successfully",
+		"faculty": faculty,
+	})
+}
+
+func (h *FacultyHandler) GetFaculty(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid faculty id"})
+	}
+
+	faculty, err := h.service.GetFacultyByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "faculty not found"})
+	}
+
+	return c.JSON(faculty)
+}
+
+func (h *FacultyHandler) UpdateFaculty(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid faculty id"})
+	}
+
+	var req UpdateFacultyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	faculty, err := h.service.UpdateFaculty(id, req.Kode, req.Nama)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Faculty updated successfully",
+		"faculty": faculty,
+	})
+}
+
+func (h *FacultyHandler) DeleteFaculty(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid faculty id"})
+	}
+
+	if err := h.service.DeleteFaculty(id); err != nil {
+		if handled, resp := dependencyConflict(c, err); handled {
+			return resp
+		}
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// AuditSnapshot memuat state fakultas dari param :id untuk audit log
+func (h *FacultyHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.service.GetFacultyByID(id)
+	})
+}
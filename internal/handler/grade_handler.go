@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GradeHandler mengelola input nilai peserta kelas di /classes/:id/grades
+type GradeHandler struct {
+	service      service.GradeService
+	classService service.ClassService
+}
+
+func NewGradeHandler(s service.GradeService, classService service.ClassService) *GradeHandler {
+	return &GradeHandler{service: s, classService: classService}
+}
+
+type GradeEntryRequest struct {
+	MahasiswaID string `json:"mahasiswa_id"`
+	Nilai       string `json:"nilai"`
+}
+
+type SubmitGradesRequest struct {
+	Grades []GradeEntryRequest `json:"grades"`
+}
+
+func (h *GradeHandler) ListClassGrades(c *fiber.Ctx) error {
+	classID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid class id"})
+	}
+	if allowed, resp := h.checkGradeAccess(c, classID); !allowed {
+		return resp
+	}
+
+	grades, err := h.service.ListClassGrades(classID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(grades)
+}
+
+// SubmitGrades menyimpan nilai huruf beberapa mahasiswa sekaligus (semua atau tidak sama sekali)
+func (h *GradeHandler) SubmitGrades(c *fiber.Ctx) error {
+	classID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid class id"})
+	}
+
+	var req SubmitGradesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	if allowed, resp := h.checkGradeAccess(c, classID); !allowed {
+		return resp
+	}
+
+	inputs := make([]service.GradeInput, 0, len(req.Grades))
+	for _, g := range req.Grades {
+		mahasiswaID, err := uuid.Parse(g.MahasiswaID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid mahasiswa_id"})
+		}
+		inputs = append(inputs, service.GradeInput{MahasiswaID: mahasiswaID, Nilai: g.Nilai})
+	}
+
+	grades, err := h.service.SubmitGrades(classID, inputs)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Grades saved successfully",
+		"grades":  grades,
+	})
+}
+
+// checkGradeAccess: grade:manage dibatasi prodi kelas, grade:write harus koordinator / tim pengajar kelas
+func (h *GradeHandler) checkGradeAccess(c *fiber.Ctx, classID uuid.UUID) (allowed bool, resp error) {
+	class, err := h.classService.GetClass(classID)
+	if err != nil {
+		return false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "class not found"})
+	}
+
+	switch {
+	case hasPermission(c, service.PERM_GRADE_MANAGE):
+		if !inProgramScope(c, class.StudyProgramID) {
+			return false, outOfProgramScope(c)
+		}
+		return true, nil
+	case hasPermission(c, service.PERM_GRADE_WRITE):
+		dosenID, err := getUserIDFromContext(c)
+		if err != nil {
+			return false, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+		lecturer, err := h.service.IsClassLecturer(classID, dosenID)
+		if err != nil {
+			return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		if !lecturer {
+			return false, c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden - not a lecturer of this class"})
+		}
+		return true, nil
+	default:
+		return false, c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+	}
+}
+
+// AuditSnapshot memuat nilai peserta kelas dari param :id untuk audit log
+func (h *GradeHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.service.ListClassGrades(id)
+	})
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"bytes"
+	"course-planner-api/internal/service"
+	"errors"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ImportHandler struct {
+	service service.ImportService
+}
+
+func NewImportHandler(s service.ImportService) *ImportHandler {
+	return &ImportHandler{service: s}
+}
+
+// Import menerima CSV lewat multipart field "file" atau langsung sebagai body (text/csv).
+// Gunakan ?dry_run=true untuk hanya memvalidasi tanpa menyimpan.
+func (h *ImportHandler) Import(c *fiber.Ctx) error {
+	var file io.Reader
+	if fh, err := c.FormFile("file"); err == nil {
+		f, err := fh.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot read uploaded file"})
+		}
+		defer f.Close()
+		file = f
+	} else {
+		if len(c.Body()) == 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "csv file is required"})
+		}
+		file = bytes.NewReader(c.Body())
+	}
+
+	report, err := h.service.Import(c.Params("entity"), file, c.QueryBool("dry_run"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownImportEntity):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrInvalidImportFile):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	switch {
+	case report.Applied:
+		return c.Status(fiber.StatusCreated).JSON(report)
+	case len(report.Errors) > 0 && !report.DryRun:
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(report)
+	}
+	return c.JSON(report)
+}
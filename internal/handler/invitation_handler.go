@@ -0,0 +1,238 @@
+package handler
+
+import (
+	"course-planner-api/internal/repository"
+	"course-planner-api/internal/service"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// InvitationHandler mengelola undangan akun (admin) dan aktivasi akun lewat token undangan (publik)
+type InvitationHandler struct {
+	service service.InvitationService
+}
+
+func NewInvitationHandler(s service.InvitationService) *InvitationHandler {
+	return &InvitationHandler{service: s}
+}
+
+type CreateInvitationRequest struct {
+	UserID         *string `json:"user_id,omitempty"` // undang akun pending yang sudah ada
+	Role           string  `json:"role"`
+	Name           string  `json:"name"`
+	Email          string  `json:"email"`
+	NIM            string  `json:"nim"`
+	NIDN           string  `json:"nidn"`
+	Angkatan       int     `json:"angkatan"`
+	KapasitasPA    int     `json:"kapasitas_pa"`
+	StudyProgramID *string `json:"study_program_id,omitempty"`
+	DosenPAID      *string `json:"dosen_pa_id,omitempty"`
+}
+
+type AcceptInvitationRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+// ListInvitations mendukung ?study_program_id= dan ?pending=true
+func (h *InvitationHandler) ListInvitations(c *fiber.Ctx) error {
+	studyProgramID, err := programFilter(c)
+	if err != nil {
+		return programFilterError(c, err)
+	}
+
+	invitations, err := h.service.ListInvitations(repository.InvitationFilter{
+		StudyProgramID: studyProgramID,
+		PendingOnly:    c.QueryBool("pending"),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(invitations)
+}
+
+// CreateInvitation membuat akun pending dengan role tertentu beserta token aktivasi sekali pakai.
+// Admin prodi hanya bisa mengundang dosen / mahasiswa prodinya; undangan admin hanya oleh admin global.
+func (h *InvitationHandler) CreateInvitation(c *fiber.Ctx) error {
+	var req CreateInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	userID, err := parseOptionalUUID(req.UserID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid user_id"})
+	}
+	studyProgramID, err := parseOptionalUUID(req.StudyProgramID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study_program_id"})
+	}
+	dosenPAID, err := parseOptionalUUID(req.DosenPAID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid dosen_pa_id"})
+	}
+
+	// akun admin otomatis memegang semua permission role admin, jadi hanya boleh diundang pengelola role
+	if req.Role == service.ROLE_ADMIN && !hasPermission(c, service.PERM_ROLE_MANAGE) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden - required permission: " + service.PERM_ROLE_MANAGE})
+	}
+
+	scope := getStudyProgramScope(c)
+	if scope != nil {
+		if req.Role == service.ROLE_ADMIN {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden - global admin only"})
+		}
+		if studyProgramID == nil {
+			studyProgramID = scope
+		}
+		if !inProgramScope(c, studyProgramID) {
+			return outOfProgramScope(c)
+		}
+	}
+
+	createdByID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	input := service.CreateInvitationInput{
+		UserID:         userID,
+		Role:           req.Role,
+		Name:           req.Name,
+		Email:          req.Email,
+		NIM:            req.NIM,
+		NIDN:           req.NIDN,
+		Angkatan:       req.Angkatan,
+		KapasitasPA:    req.KapasitasPA,
+		StudyProgramID: studyProgramID,
+		DosenPAID:      dosenPAID,
+		CreatedByID:    &createdByID,
+	}
+	// untuk akun yang sudah ada, StudyProgramID membatasi akun yang boleh diundang (admin prodi)
+	if userID != nil {
+		input.StudyProgramID = scope
+	}
+
+	issued, err := h.service.CreateInvitation(input)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":    "Invitation created successfully",
+		"invitation": issued.Invitation,
+		"token":      issued.Token,
+	})
+}
+
+func (h *InvitationHandler) GetInvitation(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid invitation id"})
+	}
+
+	invitation, err := h.service.GetInvitationByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "invitation not found"})
+	}
+	if !inProgramScope(c, invitation.User.StudyProgramID) {
+		return outOfProgramScope(c)
+	}
+	return c.JSON(invitation)
+}
+
+// ResendInvitation membuat token baru untuk undangan yang belum dipakai
+func (h *InvitationHandler) ResendInvitation(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid invitation id"})
+	}
+	if allowed, resp := h.checkInvitationScope(c, id); !allowed {
+		return resp
+	}
+
+	issued, err := h.service.ResendInvitation(id)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":    "Invitation renewed successfully",
+		"invitation": issued.Invitation,
+		"token":      issued.Token,
+	})
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid invitation id"})
+	}
+	if allowed, resp := h.checkInvitationScope(c, id); !allowed {
+		return resp
+	}
+
+	if err := h.service.RevokeInvitation(id); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// AcceptInvitation (publik) mengaktifkan akun dengan token undangan dan mengatur password
+func (h *InvitationHandler) AcceptInvitation(c *fiber.Ctx) error {
+	var req AcceptInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Token == "" || req.Password == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "token and password are required"})
+	}
+
+	user, err := h.service.AcceptInvitation(req.Token, req.Password)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInvitation) {
+			return c.Status(fiber.StatusGone).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Account activated successfully, please login",
+		"user":    user,
+	})
+}
+
+// checkInvitationScope memastikan undangan ada dan akunnya berada dalam prodi admin yang login
+func (h *InvitationHandler) checkInvitationScope(c *fiber.Ctx, id uuid.UUID) (allowed bool, resp error) {
+	invitation, err := h.service.GetInvitationByID(id)
+	if err != nil {
+		return false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "invitation not found"})
+	}
+	if !inProgramScope(c, invitation.User.StudyProgramID) {
+		return false, outOfProgramScope(c)
+	}
+	return true, nil
+}
+
+// AuditSnapshot memuat undangan dari param :id, atau dari id di response create.
+// Token asli tidak pernah ikut tercatat karena undangan hanya menyimpan hash-nya.
+func (h *InvitationHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	if c.Params("id") != "" {
+		return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+			return h.service.GetInvitationByID(id)
+		})
+	}
+
+	id, err := uuid.Parse(entityIDFromBody(c.Response().Body()))
+	if err != nil {
+		return "", nil
+	}
+	invitation, err := h.service.GetInvitationByID(id)
+	if err != nil {
+		// jangan sampai after jatuh ke body response yang berisi token
+		return id.String(), fiber.Map{"id": id}
+	}
+	return id.String(), invitation
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"course-planner-api/internal/repository"
 	"course-planner-api/internal/service"
 	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -24,18 +26,44 @@ func getMahasiswaID(c *fiber.Ctx) (uuid.UUID, error) {
 }
 
 // ListAvailableClasses (Req. 1)
+// Filter dan sort sama dengan list kelas admin (lihat classFilter), selalu untuk semester berjalan.
+// ?min_seats= menyaring kelas dengan sisa kuota minimal (gunakan 1 untuk kelas yang belum penuh).
+// ?prioritize_recommended=true menampilkan kelas rekomendasi kurikulum semester ini lebih dulu.
 func (h *KRSHandler) ListAvailableClasses(c *fiber.Ctx) error {
 	mahasiswaID, err := getMahasiswaID(c)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized", "details": err.Error()})
 	}
 
-	classes, err := h.Service.ListAvailableClasses(mahasiswaID)
+	filter, err := classFilter(c)
 	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var minSeats *int
+	if v := c.Query("min_seats"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid min_seats"})
+		}
+		minSeats = &n
+	}
+
+	classes, err := h.Service.ListAvailableClasses(mahasiswaID, filter, minSeats, c.QueryBool("prioritize_recommended"), pageQuery(c))
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidSort) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengambil daftar matakuliah", "details": err.Error()})
 	}
 
-	return c.JSON(fiber.Map{"data": classes, "semester": service.GetCurrentSemester()})
+	return c.JSON(fiber.Map{
+		"data":     classes.Data,
+		"total":    classes.Total,
+		"limit":    classes.Limit,
+		"offset":   classes.Offset,
+		"semester": service.GetCurrentSemester(),
+	})
 }
 
 // TakeClass (Req. 2)
@@ -154,4 +182,17 @@ func (h *KRSHandler) RequestCancellation(c *fiber.Ctx) error {
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"message": "Pengajuan pembatalan matakuliah berhasil. Menunggu persetujuan Dosen PA."})
-}
\ No newline at end of file
+}
+
+// AuditSnapshot memuat KRS semester berjalan milik mahasiswa yang login untuk audit log
+func (h *KRSHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	mahasiswaID, err := getMahasiswaID(c)
+	if err != nil {
+		return "", nil
+	}
+	krs, err := h.Service.GetTakenClasses(mahasiswaID)
+	if err != nil {
+		return mahasiswaID.String(), nil
+	}
+	return mahasiswaID.String(), krs
+}
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"course-planner-api/internal/repository"
+	"course-planner-api/internal/service"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type MahasiswaManagementHandler struct {
+	service service.MahasiswaManagementService
+}
+
+func NewMahasiswaManagementHandler(s service.MahasiswaManagementService) *MahasiswaManagementHandler {
+	return &MahasiswaManagementHandler{service: s}
+}
+
+type CreateMahasiswaRequest struct {
+	Name           string  `json:"name"`
+	Email          string  `json:"email"`
+	Password       string  `json:"password"` // opsional; kosong = akun pending, diaktivasi lewat undangan / registrasi NIM
+	NIM            string  `json:"nim"`
+	Angkatan       int     `json:"angkatan"`
+	StudyProgramID *string `json:"study_program_id,omitempty"`
+	DosenPAID      *string `json:"dosen_pa_id,omitempty"`
+}
+
+type UpdateMahasiswaRequest struct {
+	Name           *string `json:"name,omitempty"`
+	Email          *string `json:"email,omitempty"`
+	NIM            *string `json:"nim,omitempty"`
+	Angkatan       *int    `json:"angkatan,omitempty"`
+	StudyProgramID *string `json:"study_program_id,omitempty"`
+	DosenPAID      *string `json:"dosen_pa_id,omitempty"` // ditolak; dosen PA diubah lewat PUT /:id/dosen-pa
+}
+
+// ListMahasiswa mendukung filter ?study_program_id=, ?dosen_pa_id= dan filter di mahasiswaFilter.
+// Sort: nim, name, angkatan, created_at
+func (h *MahasiswaManagementHandler) ListMahasiswa(c *fiber.Ctx) error {
+	studyProgramID, err := programFilter(c)
+	if err != nil {
+		return programFilterError(c, err)
+	}
+
+	filter, err := mahasiswaFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	filter.StudyProgramID = studyProgramID
+	if filter.DosenPAID, err = parseQueryUUID(c, "dosen_pa_id"); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	mahasiswa, err := h.service.GetAllMahasiswa(filter, pageQuery(c))
+	if err != nil {
+		return listError(c, err)
+	}
+	return c.JSON(mahasiswa)
+}
+
+// mahasiswaFilter membaca ?angkatan=, ?is_active= dan ?search= (nama / NIM)
+func mahasiswaFilter(c *fiber.Ctx) (repository.MahasiswaFilter, error) {
+	filter := repository.MahasiswaFilter{Search: c.Query("search")}
+
+	if v := c.Query("angkatan"); v != "" {
+		angkatan, err := strconv.Atoi(v)
+		if err != nil {
+			return filter, errors.New("invalid angkatan")
+		}
+		filter.Angkatan = &angkatan
+	}
+
+	if v := c.Query("is_active"); v != "" {
+		isActive, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, errors.New("invalid is_active")
+		}
+		filter.IsActive = &isActive
+	}
+	return filter, nil
+}
+
+func (h *MahasiswaManagementHandler) GetMahasiswa(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid mahasiswa id"})
+	}
+
+	mahasiswa, err := h.service.GetMahasiswaByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "mahasiswa not found"})
+	}
+	if !inProgramScope(c, mahasiswa.StudyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	return c.JSON(mahasiswa)
+}
+
+// CreateMahasiswa membuat akun mahasiswa; tanpa password akun dibuat pending seperti hasil import
+func (h *MahasiswaManagementHandler) CreateMahasiswa(c *fiber.Ctx) error {
+	var req CreateMahasiswaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	if req.Name == "" || req.Email == "" || req.NIM == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name, email, and nim are required"})
+	}
+
+	studyProgramID, err := parseOptionalUUID(req.StudyProgramID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study_program_id"})
+	}
+	// Admin prodi hanya bisa menambahkan mahasiswa ke prodinya sendiri
+	if studyProgramID == nil {
+		studyProgramID = getStudyProgramScope(c)
+	}
+	if !inProgramScope(c, studyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	input := service.CreateMahasiswaInput{
+		Name:           req.Name,
+		Email:          req.Email,
+		Password:       req.Password,
+		NIM:            req.NIM,
+		Angkatan:       req.Angkatan,
+		StudyProgramID: studyProgramID,
+	}
+
+	if req.DosenPAID != nil && *req.DosenPAID != "" {
+		dosenPAID, err := uuid.Parse(*req.DosenPAID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid dosen_pa_id"})
+		}
+		input.DosenPAID = &dosenPAID
+	}
+
+	mahasiswa, err := h.service.CreateMahasiswa(input)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":   "Mahasiswa created successfully",
+		"mahasiswa": mahasiswa,
+	})
+}
+
+func (h *MahasiswaManagementHandler) UpdateMahasiswa(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid mahasiswa id"})
+	}
+
+	var req UpdateMahasiswaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	// penugasan dosen PA butuh permission dosen_pa:assign dan melewati pengecekan kapasitas & notifikasi
+	if req.DosenPAID != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "dosen_pa_id cannot be updated here, use PUT /api/admin/mahasiswa/:id/dosen-pa"})
+	}
+
+	studyProgramID, err := parseOptionalUUID(req.StudyProgramID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study_program_id"})
+	}
+	if allowed, resp := h.checkMahasiswaScope(c, id); !allowed {
+		return resp
+	}
+	if studyProgramID != nil && !inProgramScope(c, studyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	input := service.UpdateMahasiswaInput{
+		Name:           req.Name,
+		Email:          req.Email,
+		NIM:            req.NIM,
+		Angkatan:       req.Angkatan,
+		StudyProgramID: studyProgramID,
+	}
+
+	mahasiswa, err := h.service.UpdateMahasiswa(id, input)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":   "Mahasiswa updated successfully",
+		"mahasiswa": mahasiswa,
+	})
+}
+
+func (h *MahasiswaManagementHandler) DeactivateMahasiswa(c *fiber.Ctx) error {
+	return h.setActive(c, false)
+}
+
+func (h *MahasiswaManagementHandler) ActivateMahasiswa(c *fiber.Ctx) error {
+	return h.setActive(c, true)
+}
+
+func (h *MahasiswaManagementHandler) setActive(c *fiber.Ctx, active bool) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid mahasiswa id"})
+	}
+
+	if allowed, resp := h.checkMahasiswaScope(c, id); !allowed {
+		return resp
+	}
+
+	mahasiswa, err := h.service.SetMahasiswaActive(id, active)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	message := "Mahasiswa deactivated successfully"
+	if active {
+		message = "Mahasiswa activated successfully"
+	}
+
+	return c.JSON(fiber.Map{
+		"message":   message,
+		"mahasiswa": mahasiswa,
+	})
+}
+
+// checkMahasiswaScope memastikan mahasiswa ada dan berada dalam prodi admin yang login
+func (h *MahasiswaManagementHandler) checkMahasiswaScope(c *fiber.Ctx, id uuid.UUID) (allowed bool, resp error) {
+	mahasiswa, err := h.service.GetMahasiswaByID(id)
+	if err != nil {
+		return false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "mahasiswa not found"})
+	}
+	if !inProgramScope(c, mahasiswa.StudyProgramID) {
+		return false, outOfProgramScope(c)
+	}
+	return true, nil
+}
+
+// AuditSnapshot memuat state mahasiswa dari param :id untuk audit log
+func (h *MahasiswaManagementHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.service.GetMahasiswaByID(id)
+	})
+}
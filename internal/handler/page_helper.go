@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"course-planner-api/internal/repository"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// pageQuery membaca ?limit=, ?offset= dan ?sort= yang berlaku di semua endpoint list
+func pageQuery(c *fiber.Ctx) repository.PageQuery {
+	return repository.PageQuery{
+		Limit:  c.QueryInt("limit"),
+		Offset: c.QueryInt("offset"),
+		Sort:   c.Query("sort"),
+	}
+}
+
+// listError mengembalikan 400 untuk field sort yang tidak dikenal, selain itu 500
+func listError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, repository.ErrInvalidSort) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
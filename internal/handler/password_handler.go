@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PasswordHandler struct {
+	service service.PasswordService
+}
+
+func NewPasswordHandler(s service.PasswordService) *PasswordHandler {
+	return &PasswordHandler{service: s}
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// ChangePassword mengganti password user yang login; sesi lain otomatis dicabut
+func (h *PasswordHandler) ChangePassword(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+	sessionID, err := getSessionIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var body changePasswordRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid request"})
+	}
+	if body.CurrentPassword == "" || body.NewPassword == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "current_password and new_password are required"})
+	}
+
+	if err := h.service.ChangePassword(userID, sessionID, body.CurrentPassword, body.NewPassword); err != nil {
+		if errors.Is(err, service.ErrInvalidCurrentPassword) || errors.Is(err, service.ErrSamePassword) || errors.Is(err, service.ErrPasswordTooShort) {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Password changed successfully, call /api/auth/refresh to get a new access token",
+	})
+}
+
+// ForgotPassword selalu menjawab 202 agar tidak membocorkan email mana yang terdaftar
+func (h *PasswordHandler) ForgotPassword(c *fiber.Ctx) error {
+	var body forgotPasswordRequest
+	if err := c.BodyParser(&body); err != nil || body.Email == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "email is required"})
+	}
+
+	if err := h.service.ForgotPassword(body.Email, c.IP()); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(http.StatusAccepted).JSON(fiber.Map{
+		"message": "If the email is registered, a password reset link has been sent",
+	})
+}
+
+// ResetPassword mengganti password memakai token dari email reset
+func (h *PasswordHandler) ResetPassword(c *fiber.Ctx) error {
+	var body resetPasswordRequest
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid request"})
+	}
+	if body.Token == "" || body.NewPassword == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "token and new_password are required"})
+	}
+
+	if err := h.service.ResetPassword(body.Token, body.NewPassword); err != nil {
+		if errors.Is(err, service.ErrInvalidResetToken) || errors.Is(err, service.ErrPasswordTooShort) {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Password has been reset, please login with the new password"})
+}
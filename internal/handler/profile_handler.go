@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+	"errors"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ProfileHandler menampilkan dan mengubah profil user yang sedang login (/api/me)
+type ProfileHandler struct {
+	service service.ProfileService
+}
+
+func NewProfileHandler(s service.ProfileService) *ProfileHandler {
+	return &ProfileHandler{service: s}
+}
+
+type UpdateProfileRequest struct {
+	Phone   *string `json:"phone,omitempty"`
+	Address *string `json:"address,omitempty"`
+}
+
+func (h *ProfileHandler) GetProfile(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	profile, err := h.service.GetProfile(userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(profile)
+}
+
+// UpdateProfile mengubah data kontak sendiri (phone, address)
+func (h *ProfileHandler) UpdateProfile(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var req UpdateProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	profile, err := h.service.UpdateContact(userID, service.UpdateContactInput{
+		Phone:   req.Phone,
+		Address: req.Address,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(profile)
+}
+
+// UploadPhoto menerima foto lewat multipart field "photo" atau langsung sebagai body (image/*)
+func (h *ProfileHandler) UploadPhoto(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var data []byte
+	if fh, err := c.FormFile("photo"); err == nil {
+		if fh.Size > service.MAX_PROFILE_PHOTO_SIZE {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": service.ErrPhotoTooLarge.Error()})
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot read uploaded file"})
+		}
+		defer f.Close()
+		if data, err = io.ReadAll(f); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "cannot read uploaded file"})
+		}
+	} else {
+		data = c.Body()
+	}
+	if len(data) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "photo is required"})
+	}
+
+	profile, err := h.service.UploadPhoto(userID, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPhotoTooLarge):
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, service.ErrUnsupportedPhotoType):
+			return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(profile)
+}
+
+func (h *ProfileHandler) DeletePhoto(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	profile, err := h.service.DeletePhoto(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(profile)
+}
+
+// AuditSnapshot memuat data kontak user yang login untuk audit log
+func (h *ProfileHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return "", nil
+	}
+	profile, err := h.service.GetProfile(userID)
+	if err != nil {
+		return userID.String(), nil
+	}
+	return userID.String(), fiber.Map{
+		"phone":     profile.Phone,
+		"address":   profile.Address,
+		"photo_url": profile.PhotoURL,
+	}
+}
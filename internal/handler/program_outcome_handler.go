@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ProgramOutcomeHandler mengelola CPL prodi di /study-programs/:id/cpl
+type ProgramOutcomeHandler struct {
+	service service.ProgramOutcomeService
+}
+
+func NewProgramOutcomeHandler(s service.ProgramOutcomeService) *ProgramOutcomeHandler {
+	return &ProgramOutcomeHandler{service: s}
+}
+
+type CreateProgramOutcomeRequest struct {
+	Kode      string `json:"kode"`
+	Deskripsi string `json:"deskripsi"`
+}
+
+type UpdateProgramOutcomeRequest struct {
+	Kode      *string `json:"kode,omitempty"`
+	Deskripsi *string `json:"deskripsi,omitempty"`
+}
+
+func (h *ProgramOutcomeHandler) ListProgramOutcomes(c *fiber.Ctx) error {
+	studyProgramID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study program id"})
+	}
+	if !inProgramScope(c, &studyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	outcomes, err := h.service.ListProgramOutcomes(studyProgramID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(outcomes)
+}
+
+func (h *ProgramOutcomeHandler) CreateProgramOutcome(c *fiber.Ctx) error {
+	studyProgramID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study program id"})
+	}
+	if !inProgramScope(c, &studyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	var req CreateProgramOutcomeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Kode == "" || req.Deskripsi == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "kode and deskripsi are required"})
+	}
+
+	outcome, err := h.service.CreateProgramOutcome(studyProgramID, req.Kode, req.Deskripsi)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":         "Program outcome created successfully",
+		"program_outcome": outcome,
+	})
+}
+
+func (h *ProgramOutcomeHandler) UpdateProgramOutcome(c *fiber.Ctx) error {
+	outcome, resp := h.findProgramOutcome(c)
+	if outcome == nil {
+		return resp
+	}
+
+	var req UpdateProgramOutcomeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	updated, err := h.service.UpdateProgramOutcome(outcome.ID, service.UpdateProgramOutcomeInput{
+		Kode:      req.Kode,
+		Deskripsi: req.Deskripsi,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":         "Program outcome updated successfully",
+		"program_outcome": updated,
+	})
+}
+
+func (h *ProgramOutcomeHandler) DeleteProgramOutcome(c *fiber.Ctx) error {
+	outcome, resp := h.findProgramOutcome(c)
+	if outcome == nil {
+		return resp
+	}
+
+	if err := h.service.DeleteProgramOutcome(outcome.ID); err != nil {
+		if handled, resp := dependencyConflict(c, err); handled {
+			return resp
+		}
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// findProgramOutcome memuat CPL dari param :cplId dan memastikan milik prodi :id dalam scope admin
+func (h *ProgramOutcomeHandler) findProgramOutcome(c *fiber.Ctx) (*models.ProgramOutcome, error) {
+	studyProgramID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study program id"})
+	}
+	id, err := uuid.Parse(c.Params("cplId"))
+	if err != nil {
+		return nil, c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid program outcome id"})
+	}
+	if !inProgramScope(c, &studyProgramID) {
+		return nil, outOfProgramScope(c)
+	}
+
+	outcome, err := h.service.GetProgramOutcomeByID(id)
+	if err != nil || outcome.StudyProgramID != studyProgramID {
+		return nil, c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "program outcome not found"})
+	}
+	return outcome, nil
+}
+
+// AuditSnapshot memuat state CPL dari param :cplId untuk audit log
+func (h *ProgramOutcomeHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "cplId", func(id uuid.UUID) (interface{}, error) {
+		return h.service.GetProgramOutcomeByID(id)
+	})
+}
@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RoleHandler mengelola role (kumpulan permission) dan role tambahan milik user
+type RoleHandler struct {
+	service service.RoleService
+}
+
+func NewRoleHandler(s service.RoleService) *RoleHandler {
+	return &RoleHandler{service: s}
+}
+
+type CreateRoleRequest struct {
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	Permissions      []string `json:"permissions"`
+	RequireTwoFactor bool     `json:"require_two_factor"`
+	Global           bool     `json:"global"`
+}
+
+type UpdateRoleRequest struct {
+	Name             *string   `json:"name,omitempty"`
+	Description      *string   `json:"description,omitempty"`
+	Permissions      *[]string `json:"permissions,omitempty"`
+	RequireTwoFactor *bool     `json:"require_two_factor,omitempty"`
+	Global           *bool     `json:"global,omitempty"`
+}
+
+type ReplaceUserRolesRequest struct {
+	RoleIDs []string `json:"role_ids"`
+}
+
+// ListPermissions menampilkan katalog permission yang bisa dipakai di role
+func (h *RoleHandler) ListPermissions(c *fiber.Ctx) error {
+	return c.JSON(h.service.ListPermissions())
+}
+
+func (h *RoleHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.service.ListRoles()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(roles)
+}
+
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	var req CreateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+	if req.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "name is required"})
+	}
+
+	role, err := h.service.CreateRole(service.CreateRoleInput{
+		Name:             req.Name,
+		Description:      req.Description,
+		Permissions:      req.Permissions,
+		RequireTwoFactor: req.RequireTwoFactor,
+		Global:           req.Global,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Role created successfully",
+		"role":    role,
+	})
+}
+
+func (h *RoleHandler) GetRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid role id"})
+	}
+
+	role, err := h.service.GetRole(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "role not found"})
+	}
+	return c.JSON(role)
+}
+
+// UpdateRole mengubah role; perubahan permission berlaku untuk token yang diterbitkan setelahnya
+func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid role id"})
+	}
+
+	var req UpdateRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	role, err := h.service.UpdateRole(id, service.UpdateRoleInput{
+		Name:             req.Name,
+		Description:      req.Description,
+		Permissions:      req.Permissions,
+		RequireTwoFactor: req.RequireTwoFactor,
+		Global:           req.Global,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Role updated successfully",
+		"role":    role,
+	})
+}
+
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid role id"})
+	}
+
+	if err := h.service.DeleteRole(id); err != nil {
+		if handled, resp := dependencyConflict(c, err); handled {
+			return resp
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetUserRoles menampilkan role tambahan dan permission efektif seorang user
+func (h *RoleHandler) GetUserRoles(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid user id"})
+	}
+
+	userRoles, err := h.service.GetUserRoles(userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(userRoles)
+}
+
+// ReplaceUserRoles mengganti seluruh role tambahan user dengan role_ids di body
+func (h *RoleHandler) ReplaceUserRoles(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid user id"})
+	}
+
+	var req ReplaceUserRolesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	roleIDs := make([]uuid.UUID, 0, len(req.RoleIDs))
+	for _, v := range req.RoleIDs {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid role id: " + v})
+		}
+		roleIDs = append(roleIDs, id)
+	}
+
+	userRoles, err := h.service.ReplaceUserRoles(userID, roleIDs)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":    "User roles updated successfully",
+		"user_roles": userRoles,
+	})
+}
+
+// AuditSnapshot memuat state role dari param :id untuk audit log
+func (h *RoleHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.service.GetRole(id)
+	})
+}
+
+// UserRolesAuditSnapshot memuat role tambahan user dari param :id untuk audit log
+func (h *RoleHandler) UserRolesAuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.service.GetUserRoles(id)
+	})
+}
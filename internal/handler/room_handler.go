@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"course-planner-api/internal/repository"
 	"course-planner-api/internal/service"
 
 	"github.com/gofiber/fiber/v2"
@@ -23,10 +24,16 @@ type UpdateRoomRequest struct {
 	Nama *string `json:"nama,omitempty"`
 }
 
+// ListRooms mendukung filter ?include_deleted= dan ?search= (nama), sort: nama
 func (h *RoomHandler) ListRooms(c *fiber.Ctx) error {
-	rooms, err := h.service.GetAllRooms()
+	filter := repository.RoomFilter{
+		IncludeDeleted: c.QueryBool("include_deleted"),
+		Search:         c.Query("search"),
+	}
+
+	rooms, err := h.service.GetAllRooms(filter, pageQuery(c))
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		return listError(c, err)
 	}
 	return c.JSON(rooms)
 }
@@ -95,8 +102,35 @@ func (h *RoomHandler) DeleteRoom(c *fiber.Ctx) error {
 	}
 
 	if err := h.service.DeleteRoom(id); err != nil {
+		if handled, resp := dependencyConflict(c, err); handled {
+			return resp
+		}
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
+
+func (h *RoomHandler) RestoreRoom(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid room id"})
+	}
+
+	room, err := h.service.RestoreRoom(id)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Room restored successfully",
+		"room":    room,
+	})
+}
+
+// AuditSnapshot memuat state ruangan dari param :id untuk audit log
+func (h *RoomHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.service.GetRoomByID(id)
+	})
+}
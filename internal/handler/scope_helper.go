@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var errOutOfProgramScope = errors.New("forbidden - outside your study program")
+
+// getStudyProgramScope mengembalikan prodi admin yang sedang login.
+// nil berarti admin global yang boleh mengelola semua prodi.
+func getStudyProgramScope(c *fiber.Ctx) *uuid.UUID {
+	userToken, ok := c.Locals("user").(*jwt.Token)
+	if !ok || userToken == nil {
+		return nil
+	}
+	claims, ok := userToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil
+	}
+	value, _ := claims["study_program_id"].(string)
+	if value == "" {
+		return nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// programFilter menentukan filter prodi untuk listing admin dari ?study_program_id=.
+// Admin yang di-scope ke prodi selalu dibatasi ke prodinya sendiri.
+func programFilter(c *fiber.Ctx) (*uuid.UUID, error) {
+	var requested *uuid.UUID
+	if v := c.Query("study_program_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, errors.New("invalid study_program_id")
+		}
+		requested = &id
+	}
+
+	scope := getStudyProgramScope(c)
+	if scope == nil {
+		return requested, nil
+	}
+	if requested != nil && *requested != *scope {
+		return nil, errOutOfProgramScope
+	}
+	return scope, nil
+}
+
+// inProgramScope memeriksa apakah data dengan prodi studyProgramID boleh dikelola admin yang login
+func inProgramScope(c *fiber.Ctx, studyProgramID *uuid.UUID) bool {
+	scope := getStudyProgramScope(c)
+	if scope == nil {
+		return true
+	}
+	return studyProgramID != nil && *studyProgramID == *scope
+}
+
+// programFilterError memetakan error dari programFilter ke response HTTP
+func programFilterError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errOutOfProgramScope) {
+		return outOfProgramScope(c)
+	}
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+}
+
+func outOfProgramScope(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": errOutOfProgramScope.Error()})
+}
+
+// parseOptionalUUID mem-parse string opsional dari body request
+func parseOptionalUUID(value *string) (*uuid.UUID, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type StudyProgramHandler struct {
+	service service.StudyProgramService
+}
+
+func NewStudyProgramHandler(s service.StudyProgramService) *StudyProgramHandler {
+	return &StudyProgramHandler{service: s}
+}
+
+type CreateStudyProgramRequest struct {
+	Kode      string `json:"kode"`
+	Nama      string `json:"nama"`
+	Jenjang   string `json:"jenjang"`
+	FacultyID string `json:"faculty_id"`
+}
+
+type UpdateStudyProgramRequest struct {
+	Kode      *string `json:"kode,omitempty"`
+	Nama      *string `json:"nama,omitempty"`
+	Jenjang   *string `json:"jenjang,omitempty"`
+	FacultyID *string `json:"faculty_id,omitempty"`
+}
+
+// ListStudyPrograms mendukung filter ?faculty_id=
+func (h *StudyProgramHandler) ListStudyPrograms(c *fiber.Ctx) error {
+	var facultyID *uuid.UUID
+	if v := c.Query("faculty_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid faculty_id"})
+		}
+		facultyID = &id
+	}
+
+	programs, err := h.service.GetAllStudyPrograms(facultyID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(programs)
+}
+
+func (h *StudyProgramHandler) CreateStudyProgram(c *fiber.Ctx) error {
+	var req CreateStudyProgramRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	if req.Kode == "" || req.Nama == "" || req.FacultyID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "kode, nama, and faculty_id are required"})
+	}
+
+	facultyID, err := uuid.Parse(req.FacultyID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid faculty_id"})
+	}
+
+	program, err := h.service.CreateStudyProgram(service.CreateStudyProgramInput{
+		Kode:      req.Kode,
+		Nama:      req.Nama,
+		Jenjang:   req.Jenjang,
+		FacultyID: facultyID,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message":       "Study program created successfully",
+		"study_program": program,
+	})
+}
+
+func (h *StudyProgramHandler) GetStudyProgram(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study program id"})
+	}
+
+	program, err := h.service.GetStudyProgramByID(id)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "study program not found"})
+	}
+
+	return c.JSON(program)
+}
+
+func (h *StudyProgramHandler) UpdateStudyProgram(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study program id"})
+	}
+
+	var req UpdateStudyProgramRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	facultyID, err := parseOptionalUUID(req.FacultyID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid faculty_id"})
+	}
+
+	program, err := h.service.UpdateStudyProgram(id, service.UpdateStudyProgramInput{
+		Kode:      req.Kode,
+		Nama:      req.Nama,
+		Jenjang:   req.Jenjang,
+		FacultyID: facultyID,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":       "Study program updated successfully",
+		"study_program": program,
+	})
+}
+
+func (h *StudyProgramHandler) DeleteStudyProgram(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid study program id"})
+	}
+
+	if err := h.service.DeleteStudyProgram(id); err != nil {
+		if handled, resp := dependencyConflict(c, err); handled {
+			return resp
+		}
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// AuditSnapshot memuat state prodi dari param :id untuk audit log
+func (h *StudyProgramHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.service.GetStudyProgramByID(id)
+	})
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SyllabusHandler mengelola silabus / RPS course. Dipakai admin (dibatasi prodi)
+// dan dosen koordinator kelas course tersebut.
+type SyllabusHandler struct {
+	service       service.SyllabusService
+	courseService service.CourseService
+}
+
+func NewSyllabusHandler(s service.SyllabusService, courseService service.CourseService) *SyllabusHandler {
+	return &SyllabusHandler{service: s, courseService: courseService}
+}
+
+type LearningOutcomeRequest struct {
+	Kode      string   `json:"kode"`
+	Deskripsi string   `json:"deskripsi"`
+	CPLIDs    []string `json:"cpl_ids"`
+}
+
+type SyllabusTopicRequest struct {
+	Minggu    int    `json:"minggu"`
+	Topik     string `json:"topik"`
+	Deskripsi string `json:"deskripsi"`
+	Metode    string `json:"metode"`
+	Penilaian string `json:"penilaian"`
+}
+
+type SyllabusReferenceRequest struct {
+	Jenis    string `json:"jenis"`
+	Penulis  string `json:"penulis"`
+	Judul    string `json:"judul"`
+	Penerbit string `json:"penerbit"`
+	Tahun    *int   `json:"tahun"`
+}
+
+type SaveSyllabusRequest struct {
+	TahunAkademik int                        `json:"tahun_akademik"`
+	Semester      string                     `json:"semester"`
+	Deskripsi     string                     `json:"deskripsi"`
+	CPMK          []LearningOutcomeRequest   `json:"cpmk"`
+	RPS           []SyllabusTopicRequest     `json:"rps"`
+	Referensi     []SyllabusReferenceRequest `json:"referensi"`
+}
+
+// semesterQuery membaca ?tahun_akademik= dan ?semester= (default semester berjalan)
+func semesterQuery(c *fiber.Ctx) (int, string, error) {
+	tahunAkademik := service.GetCurrentTahunAkademik()
+	if v := c.Query("tahun_akademik"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, "", errors.New("invalid tahun_akademik")
+		}
+		tahunAkademik = parsed
+	}
+	semester := c.Query("semester", service.GetCurrentSemester())
+	if err := service.ValidateSemester(tahunAkademik, semester); err != nil {
+		return 0, "", err
+	}
+	return tahunAkademik, semester, nil
+}
+
+// ListCoordinatedCourses menampilkan course yang kelasnya dikoordinasi dosen yang login
+func (h *SyllabusHandler) ListCoordinatedCourses(c *fiber.Ctx) error {
+	dosenID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	courses, err := h.service.ListCoordinatedCourses(dosenID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(courses)
+}
+
+// GetSyllabus menampilkan versi silabus yang berlaku di ?tahun_akademik= & ?semester=
+func (h *SyllabusHandler) GetSyllabus(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid course id"})
+	}
+	tahunAkademik, semester, err := semesterQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if allowed, resp := h.checkSyllabusAccess(c, courseID, service.PERM_COURSE_READ); !allowed {
+		return resp
+	}
+
+	syllabus, err := h.service.GetEffectiveSyllabus(courseID, tahunAkademik, semester)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "syllabus not found for this semester"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(syllabus)
+}
+
+// ListSyllabusVersions menampilkan semua versi silabus (tanpa detail), terbaru dulu
+func (h *SyllabusHandler) ListSyllabusVersions(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid course id"})
+	}
+	if allowed, resp := h.checkSyllabusAccess(c, courseID, service.PERM_COURSE_READ); !allowed {
+		return resp
+	}
+
+	versions, err := h.service.ListVersions(courseID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(versions)
+}
+
+// SaveSyllabus membuat versi baru yang berlaku mulai tahun_akademik + semester di body,
+// atau mengganti seluruh isi versi jika semester tersebut sudah punya versi
+func (h *SyllabusHandler) SaveSyllabus(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid course id"})
+	}
+
+	var req SaveSyllabusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+	}
+
+	if allowed, resp := h.checkSyllabusAccess(c, courseID, service.PERM_COURSE_WRITE); !allowed {
+		return resp
+	}
+
+	input := service.SaveSyllabusInput{
+		TahunAkademik: req.TahunAkademik,
+		Semester:      req.Semester,
+		Deskripsi:     req.Deskripsi,
+	}
+	if userID, err := getUserIDFromContext(c); err == nil {
+		input.UpdatedByID = &userID
+	}
+	for _, cpmk := range req.CPMK {
+		outcome := service.LearningOutcomeInput{Kode: cpmk.Kode, Deskripsi: cpmk.Deskripsi}
+		for _, v := range cpmk.CPLIDs {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid cpl_ids"})
+			}
+			outcome.ProgramOutcomeIDs = append(outcome.ProgramOutcomeIDs, id)
+		}
+		input.LearningOutcomes = append(input.LearningOutcomes, outcome)
+	}
+	for _, t := range req.RPS {
+		input.Topics = append(input.Topics, service.SyllabusTopicInput{
+			Minggu:    t.Minggu,
+			Topik:     t.Topik,
+			Deskripsi: t.Deskripsi,
+			Metode:    t.Metode,
+			Penilaian: t.Penilaian,
+		})
+	}
+	for _, r := range req.Referensi {
+		input.References = append(input.References, service.SyllabusReferenceInput{
+			Jenis:    r.Jenis,
+			Penulis:  r.Penulis,
+			Judul:    r.Judul,
+			Penerbit: r.Penerbit,
+			Tahun:    r.Tahun,
+		})
+	}
+
+	syllabus, err := h.service.SaveSyllabus(courseID, input)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":  "Syllabus saved successfully",
+		"syllabus": syllabus,
+	})
+}
+
+// DeleteSyllabus menghapus versi yang mulai berlaku tepat di ?tahun_akademik= & ?semester= (wajib)
+func (h *SyllabusHandler) DeleteSyllabus(c *fiber.Ctx) error {
+	courseID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid course id"})
+	}
+	if c.Query("tahun_akademik") == "" || c.Query("semester") == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "tahun_akademik and semester are required"})
+	}
+	tahunAkademik, semester, err := semesterQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	if allowed, resp := h.checkSyllabusAccess(c, courseID, service.PERM_COURSE_WRITE); !allowed {
+		return resp
+	}
+
+	if err := h.service.DeleteSyllabus(courseID, tahunAkademik, semester); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// checkSyllabusAccess: pemegang adminPermission (course:read / course:write) dibatasi prodinya,
+// pemegang syllabus:write harus koordinator salah satu kelas course
+func (h *SyllabusHandler) checkSyllabusAccess(c *fiber.Ctx, courseID uuid.UUID, adminPermission string) (allowed bool, resp error) {
+	course, err := h.courseService.GetCourseByID(courseID)
+	if err != nil {
+		return false, c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "course not found"})
+	}
+
+	switch {
+	case hasPermission(c, adminPermission):
+		if !inProgramScope(c, course.StudyProgramID) {
+			return false, outOfProgramScope(c)
+		}
+		return true, nil
+	case hasPermission(c, service.PERM_SYLLABUS_WRITE):
+		dosenID, err := getUserIDFromContext(c)
+		if err != nil {
+			return false, c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+		coordinator, err := h.service.IsCourseCoordinator(courseID, dosenID)
+		if err != nil {
+			return false, c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+		if !coordinator {
+			return false, c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden - not a coordinator of this course"})
+		}
+		return true, nil
+	default:
+		return false, c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+	}
+}
+
+// AuditSnapshot memuat daftar versi silabus course dari param :id untuk audit log
+func (h *SyllabusHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	return snapshotByParam(c, "id", func(id uuid.UUID) (interface{}, error) {
+		return h.service.ListVersions(id)
+	})
+}
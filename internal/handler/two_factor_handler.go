@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"course-planner-api/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// TwoFactorHandler mengelola 2FA (TOTP) akun sendiri dan reset 2FA oleh admin
+type TwoFactorHandler struct {
+	service     service.TwoFactorService
+	authService service.AuthService
+}
+
+func NewTwoFactorHandler(s service.TwoFactorService, authService service.AuthService) *TwoFactorHandler {
+	return &TwoFactorHandler{service: s, authService: authService}
+}
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type disableTwoFactorRequest struct {
+	Password string `json:"password"` // tidak dipakai untuk akun tanpa login password (hanya SSO)
+	Code     string `json:"code"`
+}
+
+func (h *TwoFactorHandler) GetStatus(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	status, err := h.service.Status(userID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(status)
+}
+
+// Enroll membuat secret baru; tampilkan provisioning_uri sebagai QR code lalu konfirmasi dengan kode pertama
+func (h *TwoFactorHandler) Enroll(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	enrollment, err := h.service.Enroll(userID)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+	return c.JSON(enrollment)
+}
+
+// Confirm mengaktifkan 2FA dan mengembalikan recovery code (hanya ditampilkan sekali)
+func (h *TwoFactorHandler) Confirm(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var body twoFactorCodeRequest
+	if err := c.BodyParser(&body); err != nil || body.Code == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "code is required"})
+	}
+
+	codes, err := h.service.Confirm(userID, body.Code)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+	return c.JSON(fiber.Map{
+		"message":        "Two-factor authentication enabled, call /api/auth/refresh to get a new access token",
+		"recovery_codes": codes,
+	})
+}
+
+// RegenerateRecoveryCodes mengganti semua recovery code; kode lama tidak berlaku lagi
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var body twoFactorCodeRequest
+	if err := c.BodyParser(&body); err != nil || body.Code == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "code is required"})
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(userID, body.Code)
+	if err != nil {
+		return twoFactorError(c, err)
+	}
+	return c.JSON(fiber.Map{"recovery_codes": codes})
+}
+
+// Disable mematikan 2FA akun sendiri (butuh password dan kode 2FA; akun SSO cukup kode TOTP)
+func (h *TwoFactorHandler) Disable(c *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var body disableTwoFactorRequest
+	if err := c.BodyParser(&body); err != nil || body.Code == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "code is required"})
+	}
+
+	if err := h.service.Disable(userID, body.Password, body.Code); err != nil {
+		return twoFactorError(c, err)
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// ResetUserTwoFactor menghapus 2FA user lain (perangkat & recovery code hilang). Izin mengikuti
+// jenis akun seperti buka kunci akun.
+func (h *TwoFactorHandler) ResetUserTwoFactor(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "invalid user id"})
+	}
+
+	user, err := h.authService.GetAccount(userID)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+	if permission := accountPermission(user.Role); !hasPermission(c, permission) {
+		return c.Status(http.StatusForbidden).JSON(fiber.Map{"error": "forbidden - required permission: " + permission})
+	}
+	if !inProgramScope(c, user.StudyProgramID) {
+		return outOfProgramScope(c)
+	}
+
+	if err := h.service.Reset(userID); err != nil {
+		return twoFactorError(c, err)
+	}
+	return c.JSON(fiber.Map{"message": "Two-factor authentication has been reset"})
+}
+
+// AuditSnapshot memuat status 2FA user dari param :id (admin) atau user yang login untuk audit log.
+// Selalu mengembalikan state non-nil agar after tidak jatuh ke body response enroll / recovery code
+// yang berisi secret TOTP dan recovery code.
+func (h *TwoFactorHandler) AuditSnapshot(c *fiber.Ctx) (string, interface{}) {
+	var userID uuid.UUID
+	var err error
+	if c.Params("id") != "" {
+		userID, err = uuid.Parse(c.Params("id"))
+	} else {
+		userID, err = getUserIDFromContext(c)
+	}
+	if err != nil {
+		return "", fiber.Map{}
+	}
+
+	status, err := h.service.Status(userID)
+	if err != nil {
+		return userID.String(), fiber.Map{"user_id": userID}
+	}
+	return userID.String(), status
+}
+
+func twoFactorError(c *fiber.Ctx, err error) error {
+	var throttled *service.LoginThrottledError
+	if errors.As(err, &throttled) {
+		// akun diberi jeda / dikunci karena kode salah berulang: respons sama dengan login (429 + Retry-After)
+		return loginError(c, err)
+	}
+	switch {
+	case errors.Is(err, service.ErrTwoFactorAlreadyEnabled):
+		return c.Status(http.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrTwoFactorNotEnabled):
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrTwoFactorRequired):
+		return c.Status(http.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, service.ErrTwoFactorNotEnrolled), errors.Is(err, service.ErrInvalidTwoFactorCode),
+		errors.Is(err, service.ErrInvalidCurrentPassword), errors.Is(err, service.ErrTOTPCodeRequired):
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+}
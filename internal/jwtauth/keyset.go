@@ -0,0 +1,166 @@
+// Package jwtauth menandatangani dan memverifikasi access token JWT dengan kunci asimetris
+// (RS256 / EdDSA). Dipakai bersama oleh service auth (sign) dan middleware router (verify).
+package jwtauth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MIN_RSA_KEY_BITS adalah ukuran minimal kunci RSA yang diterima
+const MIN_RSA_KEY_BITS = 2048
+
+var ErrNoSigningKey = errors.New("no JWT signing key configured")
+
+// key adalah satu kunci di key set. private nil berarti kunci lama yang hanya dipakai verifikasi.
+type key struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// KeySet berisi kunci aktif untuk sign dan semua kunci (aktif + lama) untuk verifikasi,
+// dipilih berdasarkan header kid sehingga kunci bisa dirotasi tanpa membatalkan token lama
+type KeySet struct {
+	issuer    string
+	activeKID string
+	keys      map[string]*key
+}
+
+// LoadKeySet membaca semua file *.pem di dir; nama file (tanpa .pem) menjadi kid.
+// File private key (PKCS#8, atau PKCS#1 untuk RSA) bisa dipakai sign, file public key (PKIX)
+// hanya untuk verifikasi token yang ditandatangani kunci lama. activeKID boleh kosong jika
+// hanya ada satu private key.
+func LoadKeySet(dir, activeKID, issuer string) (*KeySet, error) {
+	if dir == "" {
+		return nil, ErrNoSigningKey
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	ks := &KeySet{issuer: issuer, keys: make(map[string]*key)}
+	var privateKIDs []string
+	for _, path := range paths {
+		kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+		k, err := loadKey(path, kid)
+		if err != nil {
+			return nil, fmt.Errorf("JWT key %s: %w", kid, err)
+		}
+		ks.keys[kid] = k
+		if k.private != nil {
+			privateKIDs = append(privateKIDs, kid)
+		}
+	}
+
+	if activeKID == "" {
+		if len(privateKIDs) != 1 {
+			return nil, fmt.Errorf("JWT_ACTIVE_KID is required when %s has %d private keys", dir, len(privateKIDs))
+		}
+		activeKID = privateKIDs[0]
+	}
+	active, ok := ks.keys[activeKID]
+	if !ok || active.private == nil {
+		return nil, fmt.Errorf("%w: private key %q not found in %s", ErrNoSigningKey, activeKID, dir)
+	}
+	ks.activeKID = activeKID
+
+	return ks, nil
+}
+
+func loadKey(path, kid string) (*key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM file")
+	}
+
+	var parsed interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		if k.N.BitLen() < MIN_RSA_KEY_BITS {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", MIN_RSA_KEY_BITS)
+		}
+		return &key{kid: kid, method: jwt.SigningMethodRS256, private: k, public: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		if k.N.BitLen() < MIN_RSA_KEY_BITS {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", MIN_RSA_KEY_BITS)
+		}
+		return &key{kid: kid, method: jwt.SigningMethodRS256, public: k}, nil
+	case ed25519.PrivateKey:
+		return &key{kid: kid, method: jwt.SigningMethodEdDSA, private: k, public: k.Public()}, nil
+	case ed25519.PublicKey:
+		return &key{kid: kid, method: jwt.SigningMethodEdDSA, public: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T (use RSA or Ed25519)", parsed)
+	}
+}
+
+// ActiveKeyID mengembalikan kid kunci yang dipakai sign
+func (ks *KeySet) ActiveKeyID() string {
+	return ks.activeKID
+}
+
+// Sign menandatangani claims dengan kunci aktif; iss dan iat diisi otomatis
+func (ks *KeySet) Sign(claims jwt.MapClaims) (string, error) {
+	active := ks.keys[ks.activeKID]
+	claims["iss"] = ks.issuer
+	claims["iat"] = time.Now().Unix()
+
+	token := jwt.NewWithClaims(active.method, claims)
+	token.Header["kid"] = active.kid
+	return token.SignedString(active.private)
+}
+
+// Parse memverifikasi token: kid harus dikenal, algoritma harus sesuai kuncinya,
+// exp wajib ada dan iss harus sama dengan issuer key set
+func (ks *KeySet) Parse(tokenString string) (*jwt.Token, error) {
+	return jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		k, ok := ks.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+		if t.Method.Alg() != k.method.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s for kid %q", t.Method.Alg(), kid)
+		}
+		return k.public, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(ks.issuer),
+		jwt.WithExpirationRequired(),
+	)
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer menulis setiap email sebagai file .eml di dir (bisa dibuka dengan email client)
+func NewFileMailer(from, dir string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileMailer{from: from, dir: dir}, nil
+}
+
+func (m *fileMailer) Send(msg Message) error {
+	data, err := buildMessage(m.from, msg)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), uuid.NewString()[:8])
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o600)
+}
+
+type logMailer struct {
+	from string
+}
+
+// secretParamPattern mencocokkan token sekali pakai di link email (misal reset password, aktivasi)
+var secretParamPattern = regexp.MustCompile(`([?&]token=)[^\s&]+`)
+
+// NewLogMailer hanya mencetak email ke log aplikasi. Token di link disamarkan karena log bisa
+// dibaca pihak lain; gunakan mailer file untuk mencoba link di development.
+func NewLogMailer(from string) Mailer {
+	return &logMailer{from: from}
+}
+
+func (m *logMailer) Send(msg Message) error {
+	body := secretParamPattern.ReplaceAllString(msg.Body, "${1}[REDACTED]")
+	log.Printf("[mailer] from=%s to=%s subject=%q\n%s", m.from, msg.To, msg.Subject, body)
+	return nil
+}
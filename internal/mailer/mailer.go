@@ -0,0 +1,60 @@
+// Package mailer mengirim email transaksional (misal reset password) lewat implementasi yang
+// dipilih dari konfigurasi: SMTP untuk produksi, file .eml atau log untuk development.
+package mailer
+
+import (
+	"bytes"
+	"course-planner-api/config"
+	"fmt"
+	"mime"
+	"net/mail"
+	"time"
+)
+
+// Message adalah email teks biasa untuk satu penerima
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// New membuat mailer sesuai cfg.Driver
+func New(cfg config.MailConfig) (Mailer, error) {
+	if _, err := mail.ParseAddress(cfg.From); err != nil {
+		return nil, fmt.Errorf("invalid MAIL_FROM %q: %w", cfg.From, err)
+	}
+
+	switch cfg.Driver {
+	case config.MAILER_SMTP:
+		return NewSMTPMailer(cfg), nil
+	case config.MAILER_FILE:
+		return NewFileMailer(cfg.From, cfg.OutboxDir)
+	case config.MAILER_LOG:
+		return NewLogMailer(cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unknown mailer %q", cfg.Driver)
+	}
+}
+
+// buildMessage menyusun email RFC 5322 (text/plain UTF-8) beserta header-nya
+func buildMessage(from string, msg Message) ([]byte, error) {
+	if _, err := mail.ParseAddress(msg.To); err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", msg.To, err)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(msg.Body)
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"course-planner-api/config"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+)
+
+type smtpMailer struct {
+	from     string
+	addr     string
+	host     string
+	username string
+	password string
+}
+
+// NewSMTPMailer mengirim lewat server SMTP (STARTTLS otomatis jika didukung server).
+// Autentikasi PLAIN dipakai jika SMTP_USERNAME diisi.
+func NewSMTPMailer(cfg config.MailConfig) Mailer {
+	return &smtpMailer{
+		from:     cfg.From,
+		addr:     net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		host:     cfg.SMTPHost,
+		username: cfg.SMTPUsername,
+		password: cfg.SMTPPassword,
+	}
+}
+
+func (m *smtpMailer) Send(msg Message) error {
+	data, err := buildMessage(m.from, msg)
+	if err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(m.from)
+	if err != nil {
+		return err
+	}
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.username != "" {
+		auth = smtp.PlainAuth("", m.username, m.password, m.host)
+	}
+	if err := smtp.SendMail(m.addr, auth, from.Address, []string{to.Address}, data); err != nil {
+		return fmt.Errorf("smtp send to %s: %w", to.Address, err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKey adalah kredensial sistem lain (misal keuangan, perpustakaan) untuk membaca data tanpa JWT user.
+// Key hanya disimpan dalam bentuk hash; Prefix disimpan agar key bisa dikenali di daftar.
+type APIKey struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Name           string     `gorm:"size:100" json:"name"`
+	Prefix         string     `gorm:"size:16" json:"prefix"`
+	KeyHash        string     `gorm:"size:64;uniqueIndex" json:"-"`
+	Scopes         StringList `gorm:"type:jsonb" json:"scopes"`                           // permission yang boleh dipakai key, lihat service.API_KEY_SCOPES
+	StudyProgramID *uuid.UUID `gorm:"type:uuid" json:"study_program_id"`                  // jika diisi, data dibatasi ke prodi ini seperti admin prodi
+	ExpiresAt      *time.Time `gorm:"type:timestamp without time zone" json:"expires_at"` // null berarti tidak kedaluwarsa
+	LastUsedAt     *time.Time `gorm:"type:timestamp without time zone" json:"last_used_at"`
+	LastUsedIP     string     `gorm:"size:45" json:"last_used_ip"`
+	RevokedAt      *time.Time `gorm:"type:timestamp without time zone" json:"revoked_at"`
+	CreatedByID    *uuid.UUID `gorm:"type:uuid" json:"created_by_id"`
+	CreatedAt      time.Time  `gorm:"type:timestamp without time zone" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"type:timestamp without time zone" json:"updated_at"`
+}
+
+func (k *APIKey) BeforeCreate(tx *gorm.DB) (err error) {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditLog mencatat satu perubahan data: siapa, apa, kapan, dan state sebelum / sesudahnya
+type AuditLog struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	ActorID    *uuid.UUID `gorm:"type:uuid;index" json:"actor_id"`
+	ActorRole  string     `gorm:"size:20" json:"actor_role"`
+	Action     string     `gorm:"size:50;index" json:"action"`
+	Entity     string     `gorm:"size:50;index" json:"entity"`
+	EntityID   string     `gorm:"size:64;index" json:"entity_id"`
+	Before     JSONB      `gorm:"type:jsonb" json:"before"`
+	After      JSONB      `gorm:"type:jsonb" json:"after"`
+	Method     string     `gorm:"size:10" json:"method"`
+	Path       string     `gorm:"type:text" json:"path"`
+	StatusCode int        `json:"status_code"`
+	IPAddress  string     `gorm:"size:64" json:"ip_address"`
+	UserAgent  string     `gorm:"type:text" json:"user_agent"`
+	RequestID  string     `gorm:"size:64" json:"request_id"`
+	CreatedAt  time.Time  `gorm:"type:timestamp without time zone;index" json:"created_at"`
+}
+
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClassDosen menyimpan dosen pengampu sebuah kelas (team teaching)
+type ClassDosen struct {
+	ID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	ClassID  uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_class_dosen" json:"class_id"`
+	DosenID  uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_class_dosen" json:"dosen_id"`
+	Dosen    User      `gorm:"foreignKey:DosenID" json:"dosen"`
+	Role     string    `gorm:"size:20" json:"role"`
+	SKSShare *float64  `json:"sks_share"`
+}
+
+func (ClassDosen) TableName() string {
+	return "class_dosens"
+}
+
+func (cd *ClassDosen) BeforeCreate(tx *gorm.DB) (err error) {
+	if cd.ID == uuid.Nil {
+		cd.ID = uuid.New()
+	}
+	return nil
+}
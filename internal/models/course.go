@@ -6,11 +6,17 @@ import (
 )
 
 type Course struct {
-	ID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
-	Kode    string    `gorm:"size:20" json:"kode"`
-	Nama    string    `gorm:"size:100" json:"nama"`
-	SKS     int       `json:"sks"`
-	Classes []Class   `gorm:"foreignKey:CourseID" json:"classes,omitempty"`
+	ID             uuid.UUID            `gorm:"type:uuid;primaryKey" json:"id"`
+	Kode           string               `gorm:"size:20" json:"kode"`
+	Nama           string               `gorm:"size:100" json:"nama"`
+	SKS            int                  `json:"sks"`
+	Deskripsi      string               `gorm:"type:text" json:"deskripsi"`
+	StudyProgramID *uuid.UUID           `gorm:"type:uuid" json:"study_program_id"`
+	StudyProgram   *StudyProgram        `gorm:"foreignKey:StudyProgramID" json:"study_program,omitempty"`
+	Prerequisites  []CoursePrerequisite `gorm:"foreignKey:CourseID" json:"prerequisites,omitempty"`
+	Classes        []Class              `gorm:"foreignKey:CourseID" json:"classes,omitempty"`
+	Syllabus       *CourseSyllabus      `gorm:"-" json:"syllabus,omitempty"` // versi silabus yang berlaku, hanya diisi di detail course
+	DeletedAt      gorm.DeletedAt       `gorm:"type:timestamp without time zone;index" json:"deleted_at"`
 }
 
 func (c *Course) BeforeCreate(tx *gorm.DB) (err error) {
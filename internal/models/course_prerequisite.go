@@ -0,0 +1,25 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CoursePrerequisite menyimpan matakuliah yang harus sudah diambil sebelum sebuah course
+type CoursePrerequisite struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	CourseID       uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_course_prerequisite" json:"course_id"`
+	PrerequisiteID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_course_prerequisite" json:"prerequisite_id"`
+	Prerequisite   Course    `gorm:"foreignKey:PrerequisiteID" json:"prerequisite"`
+}
+
+func (CoursePrerequisite) TableName() string {
+	return "course_prerequisites"
+}
+
+func (cp *CoursePrerequisite) BeforeCreate(tx *gorm.DB) (err error) {
+	if cp.ID == uuid.Nil {
+		cp.ID = uuid.New()
+	}
+	return nil
+}
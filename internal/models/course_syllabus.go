@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CourseSyllabus adalah satu versi silabus / RPS course.
+// Versi berlaku mulai TahunAkademik + Semester sampai ada versi yang lebih baru.
+type CourseSyllabus struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	CourseID      uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_course_syllabus_term" json:"course_id"`
+	TahunAkademik int       `json:"tahun_akademik"`
+	Semester      string    `gorm:"size:10" json:"semester"`
+	// UrutanSemester = TahunAkademik*2 (+1 untuk genap), dipakai untuk mencari versi yang berlaku
+	UrutanSemester   int                 `gorm:"uniqueIndex:idx_course_syllabus_term" json:"-"`
+	Deskripsi        string              `gorm:"type:text" json:"deskripsi"`
+	LearningOutcomes []LearningOutcome   `gorm:"foreignKey:SyllabusID" json:"cpmk"`
+	Topics           []SyllabusTopic     `gorm:"foreignKey:SyllabusID" json:"rps"`
+	References       []SyllabusReference `gorm:"foreignKey:SyllabusID" json:"referensi"`
+	UpdatedByID      *uuid.UUID          `gorm:"type:uuid" json:"updated_by_id"`
+	CreatedAt        time.Time           `gorm:"type:timestamp without time zone" json:"created_at"`
+	UpdatedAt        time.Time           `gorm:"type:timestamp without time zone" json:"updated_at"`
+}
+
+func (CourseSyllabus) TableName() string {
+	return "course_syllabi"
+}
+
+func (cs *CourseSyllabus) BeforeCreate(tx *gorm.DB) (err error) {
+	if cs.ID == uuid.Nil {
+		cs.ID = uuid.New()
+	}
+	return nil
+}
+
+// LearningOutcome adalah capaian pembelajaran matakuliah (CPMK) dalam satu versi silabus
+type LearningOutcome struct {
+	ID              uuid.UUID                `gorm:"type:uuid;primaryKey" json:"id"`
+	SyllabusID      uuid.UUID                `gorm:"type:uuid;index" json:"syllabus_id"`
+	Kode            string                   `gorm:"size:20" json:"kode"`
+	Deskripsi       string                   `gorm:"type:text" json:"deskripsi"`
+	ProgramOutcomes []LearningOutcomeMapping `gorm:"foreignKey:LearningOutcomeID" json:"cpl"`
+}
+
+func (lo *LearningOutcome) BeforeCreate(tx *gorm.DB) (err error) {
+	if lo.ID == uuid.Nil {
+		lo.ID = uuid.New()
+	}
+	return nil
+}
+
+// LearningOutcomeMapping memetakan CPMK ke CPL prodi
+type LearningOutcomeMapping struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	LearningOutcomeID uuid.UUID      `gorm:"type:uuid;uniqueIndex:idx_learning_outcome_mapping" json:"cpmk_id"`
+	ProgramOutcomeID  uuid.UUID      `gorm:"type:uuid;uniqueIndex:idx_learning_outcome_mapping" json:"cpl_id"`
+	ProgramOutcome    ProgramOutcome `gorm:"foreignKey:ProgramOutcomeID" json:"program_outcome"`
+}
+
+func (lm *LearningOutcomeMapping) BeforeCreate(tx *gorm.DB) (err error) {
+	if lm.ID == uuid.Nil {
+		lm.ID = uuid.New()
+	}
+	return nil
+}
+
+// SyllabusTopic adalah rencana topik per minggu (RPS)
+type SyllabusTopic struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SyllabusID uuid.UUID `gorm:"type:uuid;index" json:"syllabus_id"`
+	Minggu     int       `json:"minggu"`
+	Topik      string    `gorm:"size:255" json:"topik"`
+	Deskripsi  string    `gorm:"type:text" json:"deskripsi"`
+	Metode     string    `gorm:"size:100" json:"metode"`
+	Penilaian  string    `gorm:"size:100" json:"penilaian"`
+}
+
+func (st *SyllabusTopic) BeforeCreate(tx *gorm.DB) (err error) {
+	if st.ID == uuid.Nil {
+		st.ID = uuid.New()
+	}
+	return nil
+}
+
+// SyllabusReference adalah pustaka acuan silabus (utama / pendukung)
+type SyllabusReference struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	SyllabusID uuid.UUID `gorm:"type:uuid;index" json:"syllabus_id"`
+	Urutan     int       `json:"urutan"`
+	Jenis      string    `gorm:"size:20" json:"jenis"`
+	Penulis    string    `gorm:"size:255" json:"penulis"`
+	Judul      string    `gorm:"size:255" json:"judul"`
+	Penerbit   string    `gorm:"size:255" json:"penerbit"`
+	Tahun      *int      `json:"tahun"`
+}
+
+func (sr *SyllabusReference) BeforeCreate(tx *gorm.DB) (err error) {
+	if sr.ID == uuid.Nil {
+		sr.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Curriculum adalah kurikulum sebuah prodi. Berlaku untuk angkatan >= Tahun
+// sampai ada kurikulum prodi yang lebih baru.
+type Curriculum struct {
+	ID             uuid.UUID          `gorm:"type:uuid;primaryKey" json:"id"`
+	StudyProgramID uuid.UUID          `gorm:"type:uuid;uniqueIndex:idx_curriculum_program_tahun" json:"study_program_id"`
+	StudyProgram   *StudyProgram      `gorm:"foreignKey:StudyProgramID" json:"study_program,omitempty"`
+	Tahun          int                `gorm:"uniqueIndex:idx_curriculum_program_tahun" json:"tahun"`
+	Nama           string             `gorm:"size:100" json:"nama"`
+	MinSKSLulus    int                `json:"min_sks_lulus"` // total SKS lulus minimal untuk wisuda
+	MinIPK         float64            `json:"min_ipk"`
+	ElectiveGroups []ElectiveGroup    `gorm:"foreignKey:CurriculumID" json:"elective_groups,omitempty"`
+	Courses        []CurriculumCourse `gorm:"foreignKey:CurriculumID" json:"courses,omitempty"`
+	CreatedAt      time.Time          `gorm:"type:timestamp without time zone" json:"created_at"`
+	UpdatedAt      time.Time          `gorm:"type:timestamp without time zone" json:"updated_at"`
+}
+
+func (Curriculum) TableName() string {
+	return "curricula"
+}
+
+func (cu *Curriculum) BeforeCreate(tx *gorm.DB) (err error) {
+	if cu.ID == uuid.Nil {
+		cu.ID = uuid.New()
+	}
+	return nil
+}
+
+// ElectiveGroup adalah kelompok matakuliah pilihan dengan SKS minimal yang harus diambil
+type ElectiveGroup struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	CurriculumID uuid.UUID `gorm:"type:uuid;index" json:"curriculum_id"`
+	Nama         string    `gorm:"size:100" json:"nama"`
+	MinSKS       int       `json:"min_sks"`
+}
+
+func (eg *ElectiveGroup) BeforeCreate(tx *gorm.DB) (err error) {
+	if eg.ID == uuid.Nil {
+		eg.ID = uuid.New()
+	}
+	return nil
+}
+
+// CurriculumCourse menempatkan course di kurikulum sebagai wajib / pilihan beserta rekomendasi semesternya
+type CurriculumCourse struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	CurriculumID        uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_curriculum_course" json:"curriculum_id"`
+	CourseID            uuid.UUID  `gorm:"type:uuid;uniqueIndex:idx_curriculum_course" json:"course_id"`
+	Course              Course     `gorm:"foreignKey:CourseID" json:"course"`
+	Jenis               string     `gorm:"size:10" json:"jenis"`
+	SemesterRekomendasi int        `json:"semester_rekomendasi"`
+	ElectiveGroupID     *uuid.UUID `gorm:"type:uuid" json:"elective_group_id"`
+}
+
+func (cc *CurriculumCourse) BeforeCreate(tx *gorm.DB) (err error) {
+	if cc.ID == uuid.Nil {
+		cc.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Faculty struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	Kode          string         `gorm:"size:20;uniqueIndex" json:"kode"`
+	Nama          string         `gorm:"size:100" json:"nama"`
+	StudyPrograms []StudyProgram `gorm:"foreignKey:FacultyID" json:"study_programs,omitempty"`
+}
+
+func (f *Faculty) BeforeCreate(tx *gorm.DB) (err error) {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
@@ -17,6 +17,8 @@ type KRSItem struct {
 	CreatedAt       time.Time  `gorm:"type:timestamp without time zone" json:"created_at"`
 	DiajukanBatalAt *time.Time `gorm:"type:timestamp without time zone" json:"diajukan_batal_at"`
 	DibatalkanAt    *time.Time `gorm:"type:timestamp without time zone" json:"dibatalkan_at"`
+	Nilai           *string    `gorm:"size:2" json:"nilai"` // nilai huruf (A, AB, B, BC, C, D, E), nil jika belum dinilai
+	DinilaiAt       *time.Time `gorm:"type:timestamp without time zone" json:"dinilai_at"`
 }
 
 func (KRSItem) TableName() string {
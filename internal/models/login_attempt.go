@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoginAttempt mencatat setiap percobaan login (berhasil maupun gagal). Dipakai untuk
+// riwayat login user dan pembatasan percobaan login per IP.
+type LoginAttempt struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID        *uuid.UUID `gorm:"type:uuid;index" json:"user_id"` // nil jika email tidak terdaftar
+	Email         string     `gorm:"size:100" json:"email"`
+	IPAddress     string     `gorm:"size:45;index:idx_login_attempts_ip_created" json:"ip_address"`
+	UserAgent     string     `gorm:"size:255" json:"user_agent"`
+	Method        string     `gorm:"size:20;default:password" json:"method"` // password atau oidc
+	Success       bool       `json:"success"`
+	FailureReason string     `gorm:"size:30" json:"failure_reason,omitempty"` // lihat LOGIN_FAILURE_* di service/auth_service.go
+	CreatedAt     time.Time  `gorm:"type:timestamp without time zone;index:idx_login_attempts_ip_created" json:"created_at"`
+}
+
+func (a *LoginAttempt) BeforeCreate(tx *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
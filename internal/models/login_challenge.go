@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoginChallenge adalah langkah kedua login untuk akun dengan 2FA: dibuat setelah password benar,
+// ditukar dengan token setelah kode TOTP / recovery code valid. Token hanya disimpan dalam bentuk hash.
+type LoginChallenge struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID     uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
+	TokenHash  string    `gorm:"size:64;uniqueIndex" json:"-"`
+	DeviceName string    `gorm:"size:100" json:"device_name"` // info perangkat untuk sesi yang dibuat setelah verifikasi
+	UserAgent  string    `gorm:"size:255" json:"user_agent"`
+	IPAddress  string    `gorm:"size:45" json:"ip_address"`
+	Method     string    `gorm:"size:20;default:password" json:"method"` // metode langkah pertama login: password atau oidc
+	Attempts   int       `gorm:"default:0" json:"attempts"`              // kode salah yang sudah dicoba
+	ExpiresAt  time.Time `gorm:"type:timestamp without time zone" json:"expires_at"`
+	CreatedAt  time.Time `gorm:"type:timestamp without time zone" json:"created_at"`
+}
+
+func (c *LoginChallenge) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Notification struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;index" json:"user_id"`
+	Type      string     `gorm:"size:50" json:"type"`
+	Title     string     `gorm:"size:200" json:"title"`
+	Message   string     `gorm:"type:text" json:"message"`
+	CreatedAt time.Time  `gorm:"type:timestamp without time zone" json:"created_at"`
+	ReadAt    *time.Time `gorm:"type:timestamp without time zone" json:"read_at"`
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) (err error) {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OIDCLoginState menyimpan state login OIDC yang sedang berjalan (dari authorize sampai callback):
+// code_verifier PKCE dan nonce ID token. State hanya disimpan dalam bentuk hash dan sekali pakai.
+type OIDCLoginState struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	StateHash    string    `gorm:"size:64;uniqueIndex" json:"-"`
+	CodeVerifier string    `gorm:"size:128" json:"-"`
+	Nonce        string    `gorm:"size:64" json:"-"`
+	ExpiresAt    time.Time `gorm:"type:timestamp without time zone" json:"expires_at"`
+	CreatedAt    time.Time `gorm:"type:timestamp without time zone" json:"created_at"`
+}
+
+func (s *OIDCLoginState) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PasswordResetToken adalah token lupa password sekali pakai. Token hanya disimpan dalam bentuk hash.
+type PasswordResetToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;index" json:"user_id"`
+	TokenHash string     `gorm:"size:64;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"type:timestamp without time zone" json:"expires_at"`
+	UsedAt    *time.Time `gorm:"type:timestamp without time zone" json:"used_at"`
+	IPAddress string     `gorm:"size:45" json:"ip_address"` // asal permintaan reset
+	CreatedAt time.Time  `gorm:"type:timestamp without time zone" json:"created_at"`
+}
+
+func (t *PasswordResetToken) BeforeCreate(tx *gorm.DB) (err error) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
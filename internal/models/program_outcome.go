@@ -0,0 +1,21 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProgramOutcome adalah capaian pembelajaran lulusan (CPL) milik sebuah prodi
+type ProgramOutcome struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	StudyProgramID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_program_outcome_kode" json:"study_program_id"`
+	Kode           string    `gorm:"size:20;uniqueIndex:idx_program_outcome_kode" json:"kode"`
+	Deskripsi      string    `gorm:"type:text" json:"deskripsi"`
+}
+
+func (po *ProgramOutcome) BeforeCreate(tx *gorm.DB) (err error) {
+	if po.ID == uuid.Nil {
+		po.ID = uuid.New()
+	}
+	return nil
+}
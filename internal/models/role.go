@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StringList menyimpan daftar string sebagai array JSON pada kolom jsonb
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*l = StringList{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+// Role adalah kumpulan permission bernama (misal class:write, krs:verify).
+// Role sistem (admin, dosen, mahasiswa) otomatis dimiliki user sesuai kolom users.role.
+type Role struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Name             string     `gorm:"size:50;uniqueIndex" json:"name"`
+	Description      string     `gorm:"size:255" json:"description"`
+	Permissions      StringList `gorm:"type:jsonb" json:"permissions"`
+	IsSystem         bool       `gorm:"default:false" json:"is_system"`          // role bawaan, tidak bisa dihapus / diganti nama
+	RequireTwoFactor bool       `gorm:"default:false" json:"require_two_factor"` // semua user dengan role ini wajib memakai 2FA
+	Global           bool       `gorm:"default:false" json:"global"`             // permission berlaku lintas prodi (misal staf_ruangan); false = dibatasi ke prodi user (misal kaprodi)
+	CreatedAt        time.Time  `gorm:"type:timestamp without time zone" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"type:timestamp without time zone" json:"updated_at"`
+}
+
+func (r *Role) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// UserRole adalah role tambahan yang diberikan ke user, misal dosen yang juga kaprodi
+type UserRole struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	RoleID    uuid.UUID `gorm:"type:uuid;primaryKey;index" json:"role_id"`
+	Role      Role      `gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE" json:"role"`
+	CreatedAt time.Time `gorm:"type:timestamp without time zone" json:"created_at"`
+}
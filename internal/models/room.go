@@ -6,9 +6,10 @@ import (
 )
 
 type Room struct {
-	ID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
-	Nama    string    `gorm:"size:50" json:"nama"`
-	Classes []Class   `gorm:"foreignKey:RoomID" json:"classes,omitempty"`
+	ID        uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	Nama      string         `gorm:"size:50" json:"nama"`
+	Classes   []Class        `gorm:"foreignKey:RoomID" json:"classes,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"type:timestamp without time zone;index" json:"deleted_at"`
 }
 
 func (r *Room) BeforeCreate(tx *gorm.DB) (err error) {
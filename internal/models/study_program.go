@@ -0,0 +1,23 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StudyProgram adalah program studi (prodi) di bawah sebuah fakultas
+type StudyProgram struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Kode      string    `gorm:"size:20;uniqueIndex" json:"kode"`
+	Nama      string    `gorm:"size:100" json:"nama"`
+	Jenjang   string    `gorm:"size:10" json:"jenjang"`
+	FacultyID uuid.UUID `gorm:"type:uuid" json:"faculty_id"`
+	Faculty   *Faculty  `gorm:"foreignKey:FacultyID" json:"faculty,omitempty"`
+}
+
+func (sp *StudyProgram) BeforeCreate(tx *gorm.DB) (err error) {
+	if sp.ID == uuid.Nil {
+		sp.ID = uuid.New()
+	}
+	return nil
+}
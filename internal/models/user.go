@@ -8,16 +8,31 @@ import (
 )
 
 type User struct {
-	ID         uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
-	Name       string     `gorm:"size:100" json:"name"`
-	Email      string     `gorm:"size:100;uniqueIndex" json:"email"`
-	Password   string     `json:"-"`
-	Role       string     `gorm:"size:20" json:"role"`
-	NIM        string     `gorm:"size:20" json:"nim"`
-	NIDN       string     `gorm:"size:20" json:"nidn"`
-	DosenPAID  *uuid.UUID `gorm:"type:uuid" json:"dosen_pa_id"`
-	CreatedAt  time.Time  `gorm:"type:timestamp without time zone" json:"created_at"`
-	UpdatedAt  time.Time  `gorm:"type:timestamp without time zone" json:"updated_at"`
+	ID                    uuid.UUID     `gorm:"type:uuid;primaryKey" json:"id"`
+	Name                  string        `gorm:"size:100" json:"name"`
+	Email                 string        `gorm:"size:100;uniqueIndex" json:"email"`
+	Password              string        `json:"-"`
+	Role                  string        `gorm:"size:20" json:"role"`
+	NIM                   string        `gorm:"size:20" json:"nim"`
+	NIDN                  string        `gorm:"size:20" json:"nidn"`
+	DosenPAID             *uuid.UUID    `gorm:"type:uuid" json:"dosen_pa_id"`
+	Angkatan              int           `json:"angkatan"`
+	StudyProgramID        *uuid.UUID    `gorm:"type:uuid" json:"study_program_id"`
+	StudyProgram          *StudyProgram `gorm:"foreignKey:StudyProgramID" json:"study_program,omitempty"`
+	KapasitasPA           int           `json:"kapasitas_pa"`
+	Phone                 string        `gorm:"size:20" json:"phone"`
+	Address               string        `gorm:"type:text" json:"address"`
+	PhotoKey              string        `gorm:"size:255" json:"-"`                         // key foto profil di storage, lihat internal/storage
+	IsActive              bool          `json:"is_active"`                                 // tanpa default GORM agar nilai false tetap tersimpan saat create
+	IsPending             bool          `gorm:"default:false" json:"is_pending"`           // akun belum diaktivasi (belum punya password)
+	MustChangePassword    bool          `gorm:"default:false" json:"must_change_password"` // password dari admin / seeder, wajib diganti setelah login
+	FailedLoginCount      int           `gorm:"default:0" json:"failed_login_count"`       // gagal login berturut-turut dalam LOGIN_FAILURE_WINDOW
+	LastFailedLoginAt     *time.Time    `gorm:"type:timestamp without time zone" json:"last_failed_login_at"`
+	LockedUntil           *time.Time    `gorm:"type:timestamp without time zone" json:"locked_until"` // akun dikunci sementara sampai waktu ini
+	OIDCSubject           *string       `gorm:"size:255;uniqueIndex" json:"-"`                        // claim sub dari identity provider kampus (login OIDC)
+	PasswordLoginDisabled bool          `gorm:"default:false" json:"password_login_disabled"`         // akun hanya bisa login lewat OIDC
+	CreatedAt             time.Time     `gorm:"type:timestamp without time zone" json:"created_at"`
+	UpdatedAt             time.Time     `gorm:"type:timestamp without time zone" json:"updated_at"`
 }
 
 // Auto-generate UUID sebelum insert
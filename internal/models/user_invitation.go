@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserInvitation adalah undangan aktivasi akun pending. Token hanya disimpan dalam bentuk hash.
+type UserInvitation struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;index" json:"user_id"`
+	User        User       `gorm:"foreignKey:UserID" json:"user"`
+	TokenHash   string     `gorm:"size:64;uniqueIndex" json:"-"`
+	ExpiresAt   time.Time  `gorm:"type:timestamp without time zone" json:"expires_at"`
+	UsedAt      *time.Time `gorm:"type:timestamp without time zone" json:"used_at"`
+	CreatedByID *uuid.UUID `gorm:"type:uuid" json:"created_by_id"`
+	CreatedAt   time.Time  `gorm:"type:timestamp without time zone" json:"created_at"`
+}
+
+func (ui *UserInvitation) BeforeCreate(tx *gorm.DB) (err error) {
+	if ui.ID == uuid.Nil {
+		ui.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserSession adalah sesi login per perangkat. Refresh token dirotasi setiap dipakai dan
+// hanya disimpan dalam bentuk hash; hash sebelumnya disimpan untuk mendeteksi pemakaian ulang.
+type UserSession struct {
+	ID                uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID            uuid.UUID  `gorm:"type:uuid;index" json:"user_id"`
+	RefreshTokenHash  string     `gorm:"size:64;uniqueIndex" json:"-"`
+	PreviousTokenHash string     `gorm:"size:64;index" json:"-"`
+	DeviceName        string     `gorm:"size:100" json:"device_name"`
+	UserAgent         string     `gorm:"size:255" json:"user_agent"`
+	IPAddress         string     `gorm:"size:45" json:"ip_address"`
+	LastUsedAt        time.Time  `gorm:"type:timestamp without time zone" json:"last_used_at"`
+	ExpiresAt         time.Time  `gorm:"type:timestamp without time zone" json:"expires_at"`
+	RevokedAt         *time.Time `gorm:"type:timestamp without time zone" json:"revoked_at"`
+	CreatedAt         time.Time  `gorm:"type:timestamp without time zone" json:"created_at"`
+}
+
+func (us *UserSession) BeforeCreate(tx *gorm.DB) (err error) {
+	if us.ID == uuid.Nil {
+		us.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserTwoFactor adalah secret TOTP (authenticator app) milik user. Secret disimpan terenkripsi
+// dengan TOTP_ENCRYPTION_KEY; 2FA baru aktif setelah kode pertama dikonfirmasi (ConfirmedAt).
+type UserTwoFactor struct {
+	UserID          uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	SecretEncrypted string     `gorm:"size:255" json:"-"`
+	ConfirmedAt     *time.Time `gorm:"type:timestamp without time zone" json:"confirmed_at"`
+	LastUsedStep    int64      `gorm:"default:0" json:"-"` // periode TOTP terakhir yang dipakai, mencegah kode dipakai ulang
+	CreatedAt       time.Time  `gorm:"type:timestamp without time zone" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"type:timestamp without time zone" json:"updated_at"`
+}
+
+// RecoveryCode adalah kode cadangan sekali pakai jika perangkat authenticator hilang.
+// Kode hanya ditampilkan sekali saat dibuat dan disimpan dalam bentuk hash.
+type RecoveryCode struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;index" json:"user_id"`
+	CodeHash  string     `gorm:"size:64" json:"-"`
+	UsedAt    *time.Time `gorm:"type:timestamp without time zone" json:"used_at"`
+	CreatedAt time.Time  `gorm:"type:timestamp without time zone" json:"created_at"`
+}
+
+func (r *RecoveryCode) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
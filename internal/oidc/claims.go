@@ -0,0 +1,42 @@
+package oidc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Claims adalah claim ID token yang sudah diverifikasi
+type Claims map[string]interface{}
+
+// String mengembalikan claim sebagai string; angka (misal NIM yang dikirim sebagai number) ikut diformat
+func (c Claims) String(name string) string {
+	switch v := c[name].(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+func (c Claims) Subject() string {
+	return c.String("sub")
+}
+
+func (c Claims) Email() string {
+	return strings.ToLower(c.String("email"))
+}
+
+// EmailVerified hanya bernilai true jika IdP secara eksplisit mengirim email_verified = true
+// (boolean, atau string "true" dari sebagian IdP). Claim yang tidak ada dianggap belum diverifikasi.
+func (c Claims) EmailVerified() bool {
+	switch v := c["email_verified"].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
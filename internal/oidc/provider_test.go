@@ -0,0 +1,153 @@
+package oidc
+
+import (
+	"course-planner-api/config"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID = "course-planner"
+	testKeyID    = "test-key"
+	testNonce    = "nonce-123"
+)
+
+// newTestIdP menjalankan IdP palsu yang melayani discovery dan JWKS berisi satu kunci RSA
+func newTestIdP(t *testing.T) (*Provider, *rsa.PrivateKey, string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Metadata{
+			Issuer:                server.URL,
+			AuthorizationEndpoint: server.URL + "/authorize",
+			TokenEndpoint:         server.URL + "/token",
+			JWKSURI:               server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(jwkSet{Keys: []jwk{{
+			KeyType: "RSA",
+			KeyID:   testKeyID,
+			Use:     "sig",
+			N:       base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+
+	provider := NewProvider(config.OIDCConfig{IssuerURL: server.URL, ClientID: testClientID})
+	return provider, key, server.URL
+}
+
+func validIDTokenClaims(issuer string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":   issuer,
+		"aud":   testClientID,
+		"sub":   "user-1",
+		"nonce": testNonce,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	raw, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return raw
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	provider, key, issuer := newTestIdP(t)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	with := func(name string, value interface{}) jwt.MapClaims {
+		claims := validIDTokenClaims(issuer)
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+		return claims
+	}
+
+	hs256 := jwt.NewWithClaims(jwt.SigningMethodHS256, validIDTokenClaims(issuer))
+	hs256.Header["kid"] = testKeyID
+	hs256Raw, err := hs256.SignedString([]byte("shared-secret"))
+	if err != nil {
+		t.Fatalf("sign HS256 token: %v", err)
+	}
+
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, validIDTokenClaims(issuer))
+	none.Header["kid"] = testKeyID
+	noneRaw, err := none.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("sign none token: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		nonce   string
+		wantErr bool
+	}{
+		{"valid token", signRS256(t, key, testKeyID, validIDTokenClaims(issuer)), testNonce, false},
+		{"wrong nonce", signRS256(t, key, testKeyID, validIDTokenClaims(issuer)), "other-nonce", true},
+		{"missing nonce claim", signRS256(t, key, testKeyID, with("nonce", nil)), testNonce, true},
+		{"empty expected nonce", signRS256(t, key, testKeyID, with("nonce", "")), "", true},
+		{"wrong audience", signRS256(t, key, testKeyID, with("aud", "other-client")), testNonce, true},
+		{"wrong issuer", signRS256(t, key, testKeyID, with("iss", "https://evil.example")), testNonce, true},
+		{"expired", signRS256(t, key, testKeyID, with("exp", time.Now().Add(-2*CLOCK_SKEW).Unix())), testNonce, true},
+		{"missing exp", signRS256(t, key, testKeyID, with("exp", nil)), testNonce, true},
+		{"missing sub", signRS256(t, key, testKeyID, with("sub", nil)), testNonce, true},
+		{"signed by unknown key", signRS256(t, otherKey, testKeyID, validIDTokenClaims(issuer)), testNonce, true},
+		{"unknown kid", signRS256(t, key, "other-kid", validIDTokenClaims(issuer)), testNonce, true},
+		{"alg HS256", hs256Raw, testNonce, true},
+		{"alg none", noneRaw, testNonce, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := provider.VerifyIDToken(tt.raw, tt.nonce)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidIDToken) {
+					t.Fatalf("VerifyIDToken() error = %v, want ErrInvalidIDToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyIDToken() error = %v", err)
+			}
+			if claims.Subject() != "user-1" {
+				t.Errorf("subject = %q, want user-1", claims.Subject())
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(key *models.APIKey) error
+	FindAll() ([]models.APIKey, error)
+	FindByID(id uuid.UUID) (*models.APIKey, error)
+	FindByHash(keyHash string) (*models.APIKey, error)
+	Update(key *models.APIKey) error
+	Revoke(id uuid.UUID) error
+	TouchLastUsed(id uuid.UUID, ipAddress string, now, staleBefore time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+// FindAll menampilkan semua key termasuk yang sudah dicabut, terbaru dulu
+func (r *apiKeyRepository) FindAll() ([]models.APIKey, error) {
+	keys := make([]models.APIKey, 0)
+	if err := r.db.Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) FindByID(id uuid.UUID) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.First(&key, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) FindByHash(keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) Update(key *models.APIKey) error {
+	return r.db.Model(key).Select("name", "scopes", "study_program_id", "expires_at", "updated_at").Updates(key).Error
+}
+
+// Revoke mencabut key; key yang sudah dicabut tidak bisa diaktifkan lagi
+func (r *apiKeyRepository) Revoke(id uuid.UUID) error {
+	result := r.db.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// TouchLastUsed mencatat pemakaian terakhir key. Hanya ditulis jika catatan sebelumnya lebih lama
+// dari staleBefore agar request beruntun tidak menulis ke database setiap kali.
+func (r *apiKeyRepository) TouchLastUsed(id uuid.UUID, ipAddress string, now, staleBefore time.Time) error {
+	return r.db.Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, staleBefore).
+		UpdateColumns(map[string]interface{}{
+			"last_used_at": now,
+			"last_used_ip": ipAddress,
+		}).Error
+}
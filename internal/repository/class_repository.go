@@ -6,15 +6,92 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ClassFilter berisi filter opsional untuk list kelas
+type ClassFilter struct {
+	IncludeDeleted bool
+	StudyProgramID *uuid.UUID
+	Semester       string
+	Hari           string
+	DosenID        *uuid.UUID // dosen utama atau anggota tim pengajar
+	RoomID         *uuid.UUID
+	CourseKode     string
+	Search         string // kode / nama matakuliah atau nama kelas
+	JamMulai       string // "15:04", kelas yang mulai pada atau sesudah jam ini
+	JamSelesai     string // "15:04", kelas yang selesai pada atau sebelum jam ini
+}
+
+var classSortColumns = sortColumns{
+	"kode":       "courses.kode",
+	"nama":       "courses.nama",
+	"sks":        "courses.sks",
+	"nama_kelas": "classes.nama_kelas",
+	"hari":       hariOrder("classes.hari"),
+	"jam_mulai":  "classes.jam_mulai",
+	"kuota":      "classes.kuota",
+	"semester":   "classes.semester_penawaran",
+}
+
+// applyClassFilter menerapkan ClassFilter ke query kelas yang sudah di-join dengan courses
+func applyClassFilter(db, query *gorm.DB, filter ClassFilter) *gorm.DB {
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
+	}
+	if filter.StudyProgramID != nil {
+		query = query.Where("classes.study_program_id = ?", *filter.StudyProgramID)
+	}
+	if filter.Semester != "" {
+		query = query.Where("classes.semester_penawaran = ?", filter.Semester)
+	}
+	if filter.Hari != "" {
+		query = query.Where("classes.hari = ?", filter.Hari)
+	}
+	if filter.DosenID != nil {
+		query = query.Where("(classes.dosen_id = ? OR classes.id IN (?))", *filter.DosenID,
+			db.Model(&models.ClassDosen{}).Select("class_id").Where("dosen_id = ?", *filter.DosenID))
+	}
+	if filter.RoomID != nil {
+		query = query.Where("classes.room_id = ?", *filter.RoomID)
+	}
+	if filter.CourseKode != "" {
+		query = query.Where("courses.kode = ?", filter.CourseKode)
+	}
+	if filter.Search != "" {
+		like := ilike(filter.Search)
+		query = query.Where("(courses.kode ILIKE ? OR courses.nama ILIKE ? OR classes.nama_kelas ILIKE ?)", like, like, like)
+	}
+	// jam disimpan sebagai timestamp, jadi yang dibandingkan hanya bagian jamnya
+	if filter.JamMulai != "" {
+		query = query.Where("CAST(classes.jam_mulai AS time) >= ?", filter.JamMulai)
+	}
+	if filter.JamSelesai != "" {
+		query = query.Where("CAST(classes.jam_selesai AS time) <= ?", filter.JamSelesai)
+	}
+	return query
+}
+
+// preloadClass memuat relasi yang ditampilkan di list kelas
+func preloadClass(db *gorm.DB) *gorm.DB {
+	return db.Preload("Course").Preload("Dosen").Preload("Dosens.Dosen").Preload("Room")
+}
+
 type ClassRepository interface {
 	Create(class *models.Class) error
 	FindByID(id uuid.UUID) (*models.Class, error)
-	FindAll() ([]models.Class, error)
-	Update(class *models.Class) error
+	FindAll(filter ClassFilter, page PageQuery) (*Page[models.Class], error)
+	Update(class *models.Class, dosens []models.ClassDosen) error
 	Delete(id uuid.UUID) error
+	DeleteAndCancelEnrollments(id uuid.UUID, cancelledStatus string, excludedStatuses []string) error
+	Restore(id uuid.UUID) error
+	FindDeletedByID(id uuid.UUID) (*models.Class, error)
 	HasTimeConflict(roomID uuid.UUID, hari string, start, end time.Time, excludeID *uuid.UUID) (bool, error)
+	HasDosenTimeConflict(dosenIDs []uuid.UUID, hari, semester string, start, end time.Time, excludeID *uuid.UUID) (bool, error)
+	FindByDosen(dosenID uuid.UUID, semester string) ([]models.Class, error)
+	FindByCourse(courseID uuid.UUID, semester string) ([]models.Class, error)
+	FindEnrolledItems(classID uuid.UUID, excludedStatuses []string) ([]models.KRSItem, error)
+	FindItemsByKRSIDs(krsIDs []uuid.UUID, excludedStatuses []string) ([]models.KRSItem, error)
 }
 
 type classRepository struct {
@@ -31,18 +108,16 @@ func (r *classRepository) Create(class *models.Class) error {
 
 func (r *classRepository) FindByID(id uuid.UUID) (*models.Class, error) {
 	var class models.Class
-	if err := r.db.Preload("Course").Preload("Dosen").Preload("Room").First(&class, "id = ?", id).Error; err != nil {
+	if err := r.db.Preload("Course").Preload("Dosen").Preload("Dosens.Dosen").Preload("Room").First(&class, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &class, nil
 }
 
-func (r *classRepository) FindAll() ([]models.Class, error) {
-	var classes []models.Class
-	if err := r.db.Preload("Course").Preload("Dosen").Preload("Room").Find(&classes).Error; err != nil {
-		return nil, err
-	}
-	return classes, nil
+func (r *classRepository) FindAll(filter ClassFilter, page PageQuery) (*Page[models.Class], error) {
+	query := r.db.Model(&models.Class{}).Joins("JOIN courses ON courses.id = classes.course_id")
+	query = applyClassFilter(r.db, query, filter)
+	return paginate[models.Class](query, page, classSortColumns, "kode,nama_kelas", "classes.id", preloadClass)
 }
 
 func (r *classRepository) HasTimeConflict(roomID uuid.UUID, hari string, start, end time.Time, excludeID *uuid.UUID) (bool, error) {
@@ -60,11 +135,160 @@ func (r *classRepository) HasTimeConflict(roomID uuid.UUID, hari string, start,
 	return count > 0, nil
 }
 
+// HasDosenTimeConflict mengecek apakah salah satu dosen sudah mengajar di kelas lain pada waktu yang sama,
+// baik sebagai dosen utama (classes.dosen_id) maupun sebagai anggota tim pengajar (class_dosens)
+func (r *classRepository) HasDosenTimeConflict(dosenIDs []uuid.UUID, hari, semester string, start, end time.Time, excludeID *uuid.UUID) (bool, error) {
+	if len(dosenIDs) == 0 {
+		return false, nil
+	}
+
+	var count int64
+	query := r.db.Model(&models.Class{}).
+		Where("hari = ? AND semester_penawaran = ? AND NOT (jam_selesai <= ? OR jam_mulai >= ?)", hari, semester, start, end).
+		Where("dosen_id IN ? OR id IN (?)", dosenIDs,
+			r.db.Model(&models.ClassDosen{}).Select("class_id").Where("dosen_id IN ?", dosenIDs))
+
+	if excludeID != nil {
+		query = query.Where("id <> ?", *excludeID)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// replaceDosens mengganti seluruh tim pengajar sebuah kelas
+func replaceDosens(tx *gorm.DB, classID uuid.UUID, dosens []models.ClassDosen) error {
+	if err := tx.Where("class_id = ?", classID).Delete(&models.ClassDosen{}).Error; err != nil {
+		return err
+	}
+	for i := range dosens {
+		dosens[i].ClassID = classID
+		if err := tx.Create(&dosens[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindByDosen mengembalikan kelas yang diampu seorang dosen pada semester tertentu
+func (r *classRepository) FindByDosen(dosenID uuid.UUID, semester string) ([]models.Class, error) {
+	var classes []models.Class
+	query := r.db.Preload("Course").Preload("Dosen").Preload("Dosens.Dosen").Preload("Room").
+		Where("dosen_id = ? OR id IN (?)", dosenID,
+			r.db.Model(&models.ClassDosen{}).Select("class_id").Where("dosen_id = ?", dosenID))
+
+	if semester != "" {
+		query = query.Where("semester_penawaran = ?", semester)
+	}
+
+	if err := query.Find(&classes).Error; err != nil {
+		return nil, err
+	}
+	return classes, nil
+}
+
+// FindByCourse mengembalikan semua kelas sebuah matakuliah pada semester tertentu (tanpa pagination),
+// urut nama kelas
+func (r *classRepository) FindByCourse(courseID uuid.UUID, semester string) ([]models.Class, error) {
+	classes := make([]models.Class, 0)
+	err := preloadClass(r.db).
+		Where("course_id = ? AND semester_penawaran = ?", courseID, semester).
+		Order("nama_kelas").
+		Find(&classes).Error
+	if err != nil {
+		return nil, err
+	}
+	return classes, nil
+}
+
+// FindEnrolledItems mengembalikan item KRS yang mengambil kelas ini beserta data mahasiswanya
+func (r *classRepository) FindEnrolledItems(classID uuid.UUID, excludedStatuses []string) ([]models.KRSItem, error) {
+	var items []models.KRSItem
+	query := r.db.Preload("KRS.Mahasiswa").Where("class_id = ?", classID)
+	if len(excludedStatuses) > 0 {
+		query = query.Where("status NOT IN ?", excludedStatuses)
+	}
+	if err := query.Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// FindItemsByKRSIDs mengembalikan seluruh item dari beberapa KRS sekaligus (dipakai untuk cek bentrok)
+func (r *classRepository) FindItemsByKRSIDs(krsIDs []uuid.UUID, excludedStatuses []string) ([]models.KRSItem, error) {
+	var items []models.KRSItem
+	if len(krsIDs) == 0 {
+		return items, nil
+	}
+	query := r.db.Preload("Class.Course").Where("krs_id IN ?", krsIDs)
+	if len(excludedStatuses) > 0 {
+		query = query.Where("status NOT IN ?", excludedStatuses)
+	}
+	if err := query.Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
 
-func (r *classRepository) Update(class *models.Class) error {
-	return r.db.Save(class).Error
+// Update menyimpan kelas dan (jika dosens tidak nil) mengganti tim pengajarnya dalam satu transaksi,
+// agar kelas tidak tersimpan dengan dosen koordinator yang tidak cocok dengan timnya
+func (r *classRepository) Update(class *models.Class, dosens []models.ClassDosen) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(class).Error; err != nil {
+			return err
+		}
+		if dosens == nil {
+			return nil
+		}
+		return replaceDosens(tx, class.ID, dosens)
+	})
 }
 
 func (r *classRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.Class{}, "id = ?", id).Error
 }
+
+// DeleteAndCancelEnrollments membatalkan seluruh item KRS aktif kelas ini lalu menghapus kelasnya
+func (r *classRepository) DeleteAndCancelEnrollments(id uuid.UUID, cancelledStatus string, excludedStatuses []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.KRSItem{}).Where("class_id = ?", id)
+		if len(excludedStatuses) > 0 {
+			query = query.Where("status NOT IN ?", excludedStatuses)
+		}
+		if err := query.Updates(map[string]interface{}{
+			"status":        cancelledStatus,
+			"dibatalkan_at": time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Class{}, "id = ?", id).Error
+	})
+}
+
+// Restore mengembalikan kelas yang sudah di-soft delete
+func (r *classRepository) Restore(id uuid.UUID) error {
+	result := r.db.Unscoped().Model(&models.Class{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *classRepository) FindDeletedByID(id uuid.UUID) (*models.Class, error) {
+	var class models.Class
+	if err := r.db.Unscoped().Preload("Course").Preload("Dosen").Preload("Dosens.Dosen").Preload("Room").
+		Where("id = ? AND deleted_at IS NOT NULL", id).First(&class).Error; err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
@@ -5,15 +5,44 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// CourseFilter berisi filter opsional untuk list matakuliah
+type CourseFilter struct {
+	IncludeDeleted bool
+	StudyProgramID *uuid.UUID
+	SKS            *int
+	Search         string // kode atau nama matakuliah
+}
+
+var courseSortColumns = sortColumns{
+	"kode": "kode",
+	"nama": "nama",
+	"sks":  "sks",
+}
+
 type CourseRepository interface {
 	Create(course *models.Course) error
 	FindByID(id uuid.UUID) (*models.Course, error)
-	FindAll() ([]models.Course, error)
+	FindAll(filter CourseFilter, page PageQuery) (*Page[models.Course], error)
 	Update(course *models.Course) error
 	Delete(id uuid.UUID) error
+	Restore(id uuid.UUID) error
+	FindDeletedByID(id uuid.UUID) (*models.Course, error)
+	CountActiveClasses(id uuid.UUID) (int64, error)
+	CountKRSItems(id uuid.UUID, excludedStatuses []string) (int64, error)
 	FindByKode(kode string) (*models.Course, error)
+	FindByIDs(ids []uuid.UUID) ([]models.Course, error)
+	ReplacePrerequisites(courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error
+	FindPrerequisiteEdges() ([]models.CoursePrerequisite, error)
+	CountPrerequisiteOf(id uuid.UUID) (int64, error)
+	CountCurriculumEntries(id uuid.UUID) (int64, error)
+}
+
+// preloadCourse memuat relasi yang ditampilkan di detail dan list course
+func preloadCourse(db *gorm.DB) *gorm.DB {
+	return db.Preload("StudyProgram").Preload("Prerequisites.Prerequisite")
 }
 
 type courseRepository struct {
@@ -30,28 +59,72 @@ func (r *courseRepository) Create(course *models.Course) error {
 
 func (r *courseRepository) FindByID(id uuid.UUID) (*models.Course, error) {
 	var course models.Course
-	if err := r.db.First(&course, "id = ?", id).Error; err != nil {
+	if err := preloadCourse(r.db).First(&course, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &course, nil
 }
 
-func (r *courseRepository) FindAll() ([]models.Course, error) {
-	var courses []models.Course
-	if err := r.db.Find(&courses).Error; err != nil {
-		return nil, err
+func (r *courseRepository) FindAll(filter CourseFilter, page PageQuery) (*Page[models.Course], error) {
+	query := r.db.Model(&models.Course{})
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
 	}
-	return courses, nil
+	if filter.StudyProgramID != nil {
+		query = query.Where("study_program_id = ?", *filter.StudyProgramID)
+	}
+	if filter.SKS != nil {
+		query = query.Where("sks = ?", *filter.SKS)
+	}
+	if filter.Search != "" {
+		like := ilike(filter.Search)
+		query = query.Where("(kode ILIKE ? OR nama ILIKE ?)", like, like)
+	}
+	return paginate[models.Course](query, page, courseSortColumns, "kode", "id", preloadCourse)
 }
 
 func (r *courseRepository) Update(course *models.Course) error {
-	return r.db.Save(course).Error
+	return r.db.Omit(clause.Associations).Save(course).Error
 }
 
 func (r *courseRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.Course{}, "id = ?", id).Error
 }
 
+// Restore mengembalikan course yang sudah di-soft delete
+func (r *courseRepository) Restore(id uuid.UUID) error {
+	result := r.db.Unscoped().Model(&models.Course{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *courseRepository) FindDeletedByID(id uuid.UUID) (*models.Course, error) {
+	var course models.Course
+	if err := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&course).Error; err != nil {
+		return nil, err
+	}
+	return &course, nil
+}
+
+// CountActiveClasses menghitung kelas (yang belum dihapus) yang masih memakai course ini
+func (r *courseRepository) CountActiveClasses(id uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.Class{}).Where("course_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 func (r *courseRepository) FindByKode(kode string) (*models.Course, error) {
 	var course models.Course
 	if err := r.db.Where("kode = ?", kode).First(&course).Error; err != nil {
@@ -59,3 +132,74 @@ func (r *courseRepository) FindByKode(kode string) (*models.Course, error) {
 	}
 	return &course, nil
 }
+
+// CountKRSItems menghitung item KRS di seluruh kelas aktif matakuliah ini
+func (r *courseRepository) CountKRSItems(id uuid.UUID, excludedStatuses []string) (int64, error) {
+	var count int64
+	query := r.db.Model(&models.KRSItem{}).
+		Joins("JOIN classes ON classes.id = krs_items.class_id AND classes.deleted_at IS NULL").
+		Where("classes.course_id = ?", id)
+	if len(excludedStatuses) > 0 {
+		query = query.Where("krs_items.status NOT IN ?", excludedStatuses)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *courseRepository) FindByIDs(ids []uuid.UUID) ([]models.Course, error) {
+	var courses []models.Course
+	if len(ids) == 0 {
+		return courses, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&courses).Error; err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+// ReplacePrerequisites mengganti seluruh prasyarat sebuah course
+func (r *courseRepository) ReplacePrerequisites(courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("course_id = ?", courseID).Delete(&models.CoursePrerequisite{}).Error; err != nil {
+			return err
+		}
+		for _, prerequisiteID := range prerequisiteIDs {
+			if err := tx.Create(&models.CoursePrerequisite{CourseID: courseID, PrerequisiteID: prerequisiteID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindPrerequisiteEdges mengembalikan seluruh relasi prasyarat (dipakai untuk cek siklus)
+func (r *courseRepository) FindPrerequisiteEdges() ([]models.CoursePrerequisite, error) {
+	var edges []models.CoursePrerequisite
+	if err := r.db.Find(&edges).Error; err != nil {
+		return nil, err
+	}
+	return edges, nil
+}
+
+// CountPrerequisiteOf menghitung course aktif yang menjadikan course ini sebagai prasyarat
+func (r *courseRepository) CountPrerequisiteOf(id uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.CoursePrerequisite{}).
+		Joins("JOIN courses ON courses.id = course_prerequisites.course_id AND courses.deleted_at IS NULL").
+		Where("course_prerequisites.prerequisite_id = ?", id).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountCurriculumEntries menghitung kurikulum yang masih memuat course ini
+func (r *courseRepository) CountCurriculumEntries(id uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.CurriculumCourse{}).Where("course_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
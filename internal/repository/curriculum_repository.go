@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CurriculumRepository interface {
+	Create(curriculum *models.Curriculum) error
+	FindByID(id uuid.UUID) (*models.Curriculum, error)
+	FindAll(studyProgramID *uuid.UUID) ([]models.Curriculum, error)
+	FindByProgramTahun(studyProgramID uuid.UUID, tahun int) (*models.Curriculum, error)
+	FindForAngkatan(studyProgramID uuid.UUID, angkatan int) (*models.Curriculum, error)
+	Update(curriculum *models.Curriculum) error
+	Delete(id uuid.UUID) error
+	ReplaceStructure(curriculumID uuid.UUID, groups []models.ElectiveGroup, courses []models.CurriculumCourse) error
+}
+
+// preloadCurriculum memuat prodi, kelompok pilihan, dan course kurikulum (urut semester rekomendasi)
+func preloadCurriculum(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("StudyProgram").
+		Preload("ElectiveGroups", func(db *gorm.DB) *gorm.DB { return db.Order("nama") }).
+		Preload("Courses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("semester_rekomendasi, (SELECT kode FROM courses WHERE courses.id = curriculum_courses.course_id)")
+		}).
+		Preload("Courses.Course")
+}
+
+type curriculumRepository struct {
+	db *gorm.DB
+}
+
+func NewCurriculumRepository(db *gorm.DB) CurriculumRepository {
+	return &curriculumRepository{db: db}
+}
+
+func (r *curriculumRepository) Create(curriculum *models.Curriculum) error {
+	return r.db.Omit(clause.Associations).Create(curriculum).Error
+}
+
+func (r *curriculumRepository) FindByID(id uuid.UUID) (*models.Curriculum, error) {
+	var curriculum models.Curriculum
+	if err := preloadCurriculum(r.db).First(&curriculum, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &curriculum, nil
+}
+
+// FindAll mengembalikan daftar kurikulum tanpa detail course, terbaru dulu per prodi
+func (r *curriculumRepository) FindAll(studyProgramID *uuid.UUID) ([]models.Curriculum, error) {
+	curricula := make([]models.Curriculum, 0)
+	query := r.db.Preload("StudyProgram")
+	if studyProgramID != nil {
+		query = query.Where("study_program_id = ?", *studyProgramID)
+	}
+	if err := query.Order("study_program_id, tahun DESC").Find(&curricula).Error; err != nil {
+		return nil, err
+	}
+	return curricula, nil
+}
+
+func (r *curriculumRepository) FindByProgramTahun(studyProgramID uuid.UUID, tahun int) (*models.Curriculum, error) {
+	var curriculum models.Curriculum
+	if err := r.db.Where("study_program_id = ? AND tahun = ?", studyProgramID, tahun).First(&curriculum).Error; err != nil {
+		return nil, err
+	}
+	return &curriculum, nil
+}
+
+// FindForAngkatan mengembalikan kurikulum terbaru prodi yang tahunnya tidak melewati angkatan mahasiswa
+func (r *curriculumRepository) FindForAngkatan(studyProgramID uuid.UUID, angkatan int) (*models.Curriculum, error) {
+	var curriculum models.Curriculum
+	if err := preloadCurriculum(r.db).
+		Where("study_program_id = ? AND tahun <= ?", studyProgramID, angkatan).
+		Order("tahun DESC").
+		First(&curriculum).Error; err != nil {
+		return nil, err
+	}
+	return &curriculum, nil
+}
+
+func (r *curriculumRepository) Update(curriculum *models.Curriculum) error {
+	return r.db.Omit(clause.Associations).Save(curriculum).Error
+}
+
+func (r *curriculumRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("curriculum_id = ?", id).Delete(&models.CurriculumCourse{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("curriculum_id = ?", id).Delete(&models.ElectiveGroup{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Curriculum{}, "id = ?", id).Error
+	})
+}
+
+// ReplaceStructure mengganti seluruh kelompok pilihan dan course sebuah kurikulum
+func (r *curriculumRepository) ReplaceStructure(curriculumID uuid.UUID, groups []models.ElectiveGroup, courses []models.CurriculumCourse) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("curriculum_id = ?", curriculumID).Delete(&models.CurriculumCourse{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("curriculum_id = ?", curriculumID).Delete(&models.ElectiveGroup{}).Error; err != nil {
+			return err
+		}
+		for i := range groups {
+			groups[i].CurriculumID = curriculumID
+			if err := tx.Create(&groups[i]).Error; err != nil {
+				return err
+			}
+		}
+		for i := range courses {
+			courses[i].CurriculumID = curriculumID
+			if err := tx.Omit(clause.Associations).Create(&courses[i]).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Curriculum{}).Where("id = ?", curriculumID).Update("updated_at", time.Now()).Error
+	})
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AdviseeCount adalah jumlah mahasiswa bimbingan seorang dosen PA
+type AdviseeCount struct {
+	DosenID      uuid.UUID `json:"dosen_id"`
+	Name         string    `json:"name"`
+	NIDN         string    `json:"nidn"`
+	KapasitasPA  int       `json:"kapasitas_pa"`
+	AdviseeCount int64     `json:"advisee_count"`
+}
+
+type DosenPAAssignmentRepository interface {
+	AssignDosenPA(mahasiswaIDs []uuid.UUID, dosenID uuid.UUID) (int64, error)
+	FindMahasiswaByIDs(ids []uuid.UUID) ([]models.User, error)
+	FindUnassignedMahasiswa() ([]models.User, error)
+	CountAdviseesPerDosen() ([]AdviseeCount, error)
+	CountPendingReviews(mahasiswaIDs []uuid.UUID, semester string, pendingItemStatuses []string) (int64, error)
+	AssignBatch(assignments map[uuid.UUID][]uuid.UUID) error
+}
+
+type dosenPAAssignmentRepository struct {
+	db *gorm.DB
+}
+
+func NewDosenPAAssignmentRepository(db *gorm.DB) DosenPAAssignmentRepository {
+	return &dosenPAAssignmentRepository{db: db}
+}
+
+func (r *dosenPAAssignmentRepository) AssignDosenPA(mahasiswaIDs []uuid.UUID, dosenID uuid.UUID) (int64, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id IN ? AND role = ?", mahasiswaIDs, "mahasiswa").
+		Update("dosen_pa_id", dosenID)
+	return result.RowsAffected, result.Error
+}
+
+func (r *dosenPAAssignmentRepository) FindMahasiswaByIDs(ids []uuid.UUID) ([]models.User, error) {
+	var users []models.User
+	if err := r.db.Where("id IN ? AND role = ?", ids, "mahasiswa").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// FindUnassignedMahasiswa mengembalikan mahasiswa aktif yang belum punya dosen PA
+func (r *dosenPAAssignmentRepository) FindUnassignedMahasiswa() ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("role = ? AND dosen_pa_id IS NULL AND is_active = ?", "mahasiswa", true).
+		Order("nim").
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// CountAdviseesPerDosen menghitung jumlah mahasiswa aktif bimbingan untuk setiap dosen
+func (r *dosenPAAssignmentRepository) CountAdviseesPerDosen() ([]AdviseeCount, error) {
+	var counts []AdviseeCount
+	err := r.db.Table("users AS d").
+		Select("d.id AS dosen_id, d.name, d.nidn, d.kapasitas_pa, COUNT(m.id) AS advisee_count").
+		Joins("LEFT JOIN users AS m ON m.dosen_pa_id = d.id AND m.role = ? AND m.is_active = ?", "mahasiswa", true).
+		Where("d.role = ?", "dosen").
+		Group("d.id, d.name, d.nidn, d.kapasitas_pa").
+		Order("d.name").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// CountPendingReviews menghitung item KRS semester ini yang masih menunggu keputusan dosen PA
+func (r *dosenPAAssignmentRepository) CountPendingReviews(mahasiswaIDs []uuid.UUID, semester string, pendingItemStatuses []string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.KRSItem{}).
+		Joins("JOIN krs ON krs.id = krs_items.krs_id").
+		Where("krs.mahasiswa_id IN ? AND krs.semester = ? AND krs_items.status IN ?", mahasiswaIDs, semester, pendingItemStatuses).
+		Count(&count).Error
+	return count, err
+}
+
+// AssignBatch menyimpan banyak penugasan dosen PA (dosenID -> mahasiswaIDs) dalam satu transaksi
+func (r *dosenPAAssignmentRepository) AssignBatch(assignments map[uuid.UUID][]uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for dosenID, mahasiswaIDs := range assignments {
+			if len(mahasiswaIDs) == 0 {
+				continue
+			}
+			if err := tx.Model(&models.User{}).
+				Where("id IN ? AND role = ?", mahasiswaIDs, "mahasiswa").
+				Update("dosen_pa_id", dosenID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -7,11 +7,26 @@ import (
 	"gorm.io/gorm"
 )
 
+// DosenFilter berisi filter opsional untuk list dosen
+type DosenFilter struct {
+	StudyProgramID *uuid.UUID
+	Search         string // nama atau NIDN
+}
+
+var dosenSortColumns = sortColumns{
+	"name":         "name",
+	"nidn":         "nidn",
+	"kapasitas_pa": "kapasitas_pa",
+	"created_at":   "created_at",
+}
+
 type DosenRepository interface {
+	Create(user *models.User) error
 	FindByID(id uuid.UUID) (*models.User, error)
-	FindAllDosen() ([]models.User, error)
+	FindAllDosen(filter DosenFilter, page PageQuery) (*Page[models.User], error)
 	Update(user *models.User) error
 	FindByNIDN(nidn string) (*models.User, error)
+	FindByEmail(email string) (*models.User, error)
 }
 
 type dosenRepository struct {
@@ -22,24 +37,34 @@ func NewDosenRepository(db *gorm.DB) DosenRepository {
 	return &dosenRepository{db: db}
 }
 
+func (r *dosenRepository) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
 func (r *dosenRepository) FindByID(id uuid.UUID) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("id = ? AND role = ?", id, "dosen").First(&user).Error; err != nil {
+	if err := r.db.Preload("StudyProgram").Where("id = ? AND role = ?", id, "dosen").First(&user).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *dosenRepository) FindAllDosen() ([]models.User, error) {
-	var users []models.User
-	if err := r.db.Where("role = ?", "dosen").Find(&users).Error; err != nil {
-		return nil, err
+func (r *dosenRepository) FindAllDosen(filter DosenFilter, page PageQuery) (*Page[models.User], error) {
+	query := r.db.Model(&models.User{}).Where("role = ?", "dosen")
+	if filter.StudyProgramID != nil {
+		query = query.Where("study_program_id = ?", *filter.StudyProgramID)
 	}
-	return users, nil
+	if filter.Search != "" {
+		like := ilike(filter.Search)
+		query = query.Where("(name ILIKE ? OR nidn ILIKE ?)", like, like)
+	}
+	return paginate[models.User](query, page, dosenSortColumns, "name", "id", func(db *gorm.DB) *gorm.DB {
+		return db.Preload("StudyProgram")
+	})
 }
 
 func (r *dosenRepository) Update(user *models.User) error {
-	return r.db.Save(user).Error
+	return r.db.Omit("StudyProgram").Save(user).Error
 }
 
 func (r *dosenRepository) FindByNIDN(nidn string) (*models.User, error) {
@@ -49,3 +74,11 @@ func (r *dosenRepository) FindByNIDN(nidn string) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+func (r *dosenRepository) FindByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
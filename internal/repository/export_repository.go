@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// hariOrder mengurutkan kolom hari sesuai urutan minggu, bukan alfabet
+func hariOrder(column string) string {
+	return "CASE " + column + " WHEN 'Senin' THEN 1 WHEN 'Selasa' THEN 2 WHEN 'Rabu' THEN 3 WHEN 'Kamis' THEN 4 WHEN 'Jumat' THEN 5 WHEN 'Sabtu' THEN 6 WHEN 'Minggu' THEN 7 ELSE 8 END"
+}
+
+type RosterExportRow struct {
+	NIM         string `gorm:"column:nim"`
+	Name        string `gorm:"column:name"`
+	Email       string `gorm:"column:email"`
+	Angkatan    int    `gorm:"column:angkatan"`
+	Status      string `gorm:"column:status"`
+	DosenPAName string `gorm:"column:dosen_pa_name"`
+}
+
+type KRSItemExportRow struct {
+	Semester      string `gorm:"column:semester"`
+	NIM           string `gorm:"column:nim"`
+	MahasiswaName string `gorm:"column:mahasiswa_name"`
+	CourseKode    string `gorm:"column:course_kode"`
+	CourseNama    string `gorm:"column:course_nama"`
+	SKS           int    `gorm:"column:sks"`
+	NamaKelas     string `gorm:"column:nama_kelas"`
+	ItemStatus    string `gorm:"column:item_status"`
+	KRSStatus     string `gorm:"column:krs_status"`
+	DosenPAName   string `gorm:"column:dosen_pa_name"`
+	DosenPANIDN   string `gorm:"column:dosen_pa_nidn"`
+}
+
+type ScheduleExportRow struct {
+	RoomNama          string    `gorm:"column:room_nama"`
+	Hari              string    `gorm:"column:hari"`
+	JamMulai          time.Time `gorm:"column:jam_mulai"`
+	JamSelesai        time.Time `gorm:"column:jam_selesai"`
+	CourseKode        string    `gorm:"column:course_kode"`
+	CourseNama        string    `gorm:"column:course_nama"`
+	NamaKelas         string    `gorm:"column:nama_kelas"`
+	DosenName         string    `gorm:"column:dosen_name"`
+	SemesterPenawaran string    `gorm:"column:semester_penawaran"`
+}
+
+type TeachingExportRow struct {
+	DosenName         string    `gorm:"column:dosen_name"`
+	NIDN              string    `gorm:"column:nidn"`
+	Role              string    `gorm:"column:role"`
+	SKSShare          *float64  `gorm:"column:sks_share"`
+	CourseKode        string    `gorm:"column:course_kode"`
+	CourseNama        string    `gorm:"column:course_nama"`
+	SKS               int       `gorm:"column:sks"`
+	NamaKelas         string    `gorm:"column:nama_kelas"`
+	Hari              string    `gorm:"column:hari"`
+	JamMulai          time.Time `gorm:"column:jam_mulai"`
+	JamSelesai        time.Time `gorm:"column:jam_selesai"`
+	RoomNama          string    `gorm:"column:room_nama"`
+	SemesterPenawaran string    `gorm:"column:semester_penawaran"`
+}
+
+// KRSItemExportFilter mengikuti filter list KRS / mahasiswa
+type KRSItemExportFilter struct {
+	Semester       string
+	Status         string
+	StudyProgramID *uuid.UUID // prodi mahasiswa
+	DosenPAID      *uuid.UUID
+	ClassID        *uuid.UUID
+}
+
+// ScheduleExportFilter mengikuti filter list kelas
+type ScheduleExportFilter struct {
+	Semester       string
+	StudyProgramID *uuid.UUID // prodi kelas
+	RoomID         *uuid.UUID
+	DosenID        *uuid.UUID
+	IncludeDeleted bool
+}
+
+// ExportRepository membaca data export baris per baris lewat callback
+// sehingga hasil query tidak pernah dimuat seluruhnya ke memori.
+type ExportRepository interface {
+	StreamClassRoster(classID uuid.UUID, excludedStatuses []string, fn func(row RosterExportRow) error) error
+	StreamKRSItems(filter KRSItemExportFilter, fn func(row KRSItemExportRow) error) error
+	StreamRoomSchedules(filter ScheduleExportFilter, fn func(row ScheduleExportRow) error) error
+	StreamTeachingList(filter ScheduleExportFilter, fn func(row TeachingExportRow) error) error
+}
+
+type exportRepository struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) ExportRepository {
+	return &exportRepository{db: db}
+}
+
+func (r *exportRepository) StreamClassRoster(classID uuid.UUID, excludedStatuses []string, fn func(row RosterExportRow) error) error {
+	query := r.db.Table("krs_items ki").
+		Select("u.nim, u.name, u.email, u.angkatan, ki.status, COALESCE(pa.name, '') AS dosen_pa_name").
+		Joins("JOIN krs k ON k.id = ki.krs_id").
+		Joins("JOIN users u ON u.id = k.mahasiswa_id").
+		Joins("LEFT JOIN users pa ON pa.id = u.dosen_pa_id").
+		Where("ki.class_id = ?", classID)
+	if len(excludedStatuses) > 0 {
+		query = query.Where("ki.status NOT IN ?", excludedStatuses)
+	}
+	return streamRows(query.Order("u.nim"), fn)
+}
+
+func (r *exportRepository) StreamKRSItems(filter KRSItemExportFilter, fn func(row KRSItemExportRow) error) error {
+	query := r.db.Table("krs_items ki").
+		Select(`k.semester, u.nim, u.name AS mahasiswa_name, co.kode AS course_kode, co.nama AS course_nama, co.sks,
+			c.nama_kelas, ki.status AS item_status, k.status AS krs_status,
+			COALESCE(pa.name, '') AS dosen_pa_name, COALESCE(pa.nidn, '') AS dosen_pa_nidn`).
+		Joins("JOIN krs k ON k.id = ki.krs_id").
+		Joins("JOIN users u ON u.id = k.mahasiswa_id").
+		Joins("JOIN classes c ON c.id = ki.class_id").
+		Joins("JOIN courses co ON co.id = c.course_id").
+		Joins("LEFT JOIN users pa ON pa.id = u.dosen_pa_id")
+
+	if filter.Semester != "" {
+		query = query.Where("k.semester = ?", filter.Semester)
+	}
+	if filter.Status != "" {
+		query = query.Where("ki.status = ?", filter.Status)
+	}
+	if filter.StudyProgramID != nil {
+		query = query.Where("u.study_program_id = ?", *filter.StudyProgramID)
+	}
+	if filter.DosenPAID != nil {
+		query = query.Where("u.dosen_pa_id = ?", *filter.DosenPAID)
+	}
+	if filter.ClassID != nil {
+		query = query.Where("ki.class_id = ?", *filter.ClassID)
+	}
+
+	return streamRows(query.Order("u.nim, co.kode, c.nama_kelas"), fn)
+}
+
+func (r *exportRepository) StreamRoomSchedules(filter ScheduleExportFilter, fn func(row ScheduleExportRow) error) error {
+	query := r.db.Table("classes c").
+		Select(`r.nama AS room_nama, c.hari, c.jam_mulai, c.jam_selesai, co.kode AS course_kode, co.nama AS course_nama,
+			c.nama_kelas, u.name AS dosen_name, c.semester_penawaran`).
+		Joins("JOIN rooms r ON r.id = c.room_id").
+		Joins("JOIN courses co ON co.id = c.course_id").
+		Joins("JOIN users u ON u.id = c.dosen_id")
+
+	query = applyScheduleFilter(query, filter)
+	if filter.RoomID != nil {
+		query = query.Where("c.room_id = ?", *filter.RoomID)
+	}
+	if filter.DosenID != nil {
+		query = query.Where("(c.dosen_id = ? OR c.id IN (?))", *filter.DosenID,
+			r.db.Table("class_dosens").Select("class_id").Where("dosen_id = ?", *filter.DosenID))
+	}
+
+	return streamRows(query.Order("r.nama, "+hariOrder("c.hari")+", c.jam_mulai"), fn)
+}
+
+// StreamTeachingList menghasilkan satu baris per dosen per kelas.
+// Kelas lama tanpa baris class_dosens dianggap diampu dosen_id sebagai koordinator.
+func (r *exportRepository) StreamTeachingList(filter ScheduleExportFilter, fn func(row TeachingExportRow) error) error {
+	query := r.db.Table("classes c").
+		Select(`u.name AS dosen_name, u.nidn, COALESCE(cd.role, 'coordinator') AS role, cd.sks_share,
+			co.kode AS course_kode, co.nama AS course_nama, co.sks, c.nama_kelas, c.hari, c.jam_mulai, c.jam_selesai,
+			r.nama AS room_nama, c.semester_penawaran`).
+		Joins("LEFT JOIN class_dosens cd ON cd.class_id = c.id").
+		Joins("JOIN users u ON u.id = COALESCE(cd.dosen_id, c.dosen_id)").
+		Joins("JOIN courses co ON co.id = c.course_id").
+		Joins("JOIN rooms r ON r.id = c.room_id")
+
+	query = applyScheduleFilter(query, filter)
+	if filter.RoomID != nil {
+		query = query.Where("c.room_id = ?", *filter.RoomID)
+	}
+	if filter.DosenID != nil {
+		query = query.Where("u.id = ?", *filter.DosenID)
+	}
+
+	return streamRows(query.Order("u.name, "+hariOrder("c.hari")+", c.jam_mulai"), fn)
+}
+
+func applyScheduleFilter(query *gorm.DB, filter ScheduleExportFilter) *gorm.DB {
+	if !filter.IncludeDeleted {
+		query = query.Where("c.deleted_at IS NULL")
+	}
+	if filter.Semester != "" {
+		query = query.Where("c.semester_penawaran = ?", filter.Semester)
+	}
+	if filter.StudyProgramID != nil {
+		query = query.Where("c.study_program_id = ?", *filter.StudyProgramID)
+	}
+	return query
+}
+
+// streamRows menjalankan query lalu memanggil fn untuk setiap baris hasil
+func streamRows[T any](query *gorm.DB, fn func(row T) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row T
+		if err := query.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FacultyRepository interface {
+	Create(faculty *models.Faculty) error
+	FindByID(id uuid.UUID) (*models.Faculty, error)
+	FindAll() ([]models.Faculty, error)
+	Update(faculty *models.Faculty) error
+	Delete(id uuid.UUID) error
+	FindByKode(kode string) (*models.Faculty, error)
+	CountStudyPrograms(id uuid.UUID) (int64, error)
+}
+
+type facultyRepository struct {
+	db *gorm.DB
+}
+
+func NewFacultyRepository(db *gorm.DB) FacultyRepository {
+	return &facultyRepository{db: db}
+}
+
+func (r *facultyRepository) Create(faculty *models.Faculty) error {
+	return r.db.Create(faculty).Error
+}
+
+func (r *facultyRepository) FindByID(id uuid.UUID) (*models.Faculty, error) {
+	var faculty models.Faculty
+	if err := r.db.Preload("StudyPrograms").First(&faculty, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &faculty, nil
+}
+
+func (r *facultyRepository) FindAll() ([]models.Faculty, error) {
+	var faculties []models.Faculty
+	if err := r.db.Preload("StudyPrograms").Order("kode").Find(&faculties).Error; err != nil {
+		return nil, err
+	}
+	return faculties, nil
+}
+
+func (r *facultyRepository) Update(faculty *models.Faculty) error {
+	return r.db.Omit("StudyPrograms").Save(faculty).Error
+}
+
+func (r *facultyRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Faculty{}, "id = ?", id).Error
+}
+
+func (r *facultyRepository) FindByKode(kode string) (*models.Faculty, error) {
+	var faculty models.Faculty
+	if err := r.db.Where("kode = ?", kode).First(&faculty).Error; err != nil {
+		return nil, err
+	}
+	return &faculty, nil
+}
+
+func (r *facultyRepository) CountStudyPrograms(id uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.StudyProgram{}).Where("faculty_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
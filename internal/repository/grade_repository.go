@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClassGrade adalah satu baris nilai mahasiswa di sebuah kelas
+type ClassGrade struct {
+	KRSItemID   uuid.UUID  `json:"krs_item_id"`
+	MahasiswaID uuid.UUID  `json:"mahasiswa_id"`
+	NIM         string     `json:"nim"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Nilai       *string    `json:"nilai"`
+	DinilaiAt   *time.Time `json:"dinilai_at"`
+}
+
+// GradeRecord adalah riwayat pengambilan satu course oleh mahasiswa (untuk audit kelulusan)
+type GradeRecord struct {
+	MahasiswaID uuid.UUID
+	CourseID    uuid.UUID
+	Kode        string
+	Nama        string
+	SKS         int
+	Status      string
+	Nilai       *string
+}
+
+type GradeRepository interface {
+	FindClassGrades(classID uuid.UUID, statuses []string) ([]ClassGrade, error)
+	SetGrades(classID uuid.UUID, grades map[uuid.UUID]string, statuses []string) error
+	IsClassLecturer(classID, dosenID uuid.UUID) (bool, error)
+	FindGradeRecords(mahasiswaIDs []uuid.UUID, inactiveStatuses []string) ([]GradeRecord, error)
+}
+
+type gradeRepository struct {
+	db *gorm.DB
+}
+
+func NewGradeRepository(db *gorm.DB) GradeRepository {
+	return &gradeRepository{db: db}
+}
+
+// FindClassGrades mengembalikan peserta kelas (item KRS dengan status tertentu) beserta nilainya, urut NIM
+func (r *gradeRepository) FindClassGrades(classID uuid.UUID, statuses []string) ([]ClassGrade, error) {
+	grades := make([]ClassGrade, 0)
+	if err := r.db.Table("krs_items").
+		Select("krs_items.id AS krs_item_id, krs.mahasiswa_id, users.nim, users.name, krs_items.status, krs_items.nilai, krs_items.dinilai_at").
+		Joins("JOIN krs ON krs.id = krs_items.krs_id").
+		Joins("JOIN users ON users.id = krs.mahasiswa_id").
+		Where("krs_items.class_id = ? AND krs_items.status IN ?", classID, statuses).
+		Order("users.nim").
+		Scan(&grades).Error; err != nil {
+		return nil, err
+	}
+	return grades, nil
+}
+
+// SetGrades menyimpan nilai per mahasiswa (key map) di sebuah kelas dalam satu transaksi.
+// Mengembalikan gorm.ErrRecordNotFound jika salah satu mahasiswa tidak terdaftar di kelas.
+func (r *gradeRepository) SetGrades(classID uuid.UUID, grades map[uuid.UUID]string, statuses []string) error {
+	now := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for mahasiswaID, nilai := range grades {
+			result := tx.Model(&models.KRSItem{}).
+				Where("class_id = ? AND status IN ?", classID, statuses).
+				Where("krs_id IN (?)", tx.Model(&models.KRS{}).Select("id").Where("mahasiswa_id = ?", mahasiswaID)).
+				Updates(map[string]interface{}{
+					"nilai":      nilai,
+					"dinilai_at": now,
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+}
+
+// IsClassLecturer memeriksa apakah dosen adalah koordinator atau anggota tim pengajar kelas
+func (r *gradeRepository) IsClassLecturer(classID, dosenID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Class{}).
+		Where("id = ?", classID).
+		Where("dosen_id = ? OR id IN (?)", dosenID, r.db.Model(&models.ClassDosen{}).Select("class_id").Where("dosen_id = ?", dosenID)).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindGradeRecords mengembalikan seluruh riwayat course yang diambil mahasiswa (termasuk kelas yang sudah dihapus)
+func (r *gradeRepository) FindGradeRecords(mahasiswaIDs []uuid.UUID, inactiveStatuses []string) ([]GradeRecord, error) {
+	records := make([]GradeRecord, 0)
+	if len(mahasiswaIDs) == 0 {
+		return records, nil
+	}
+
+	query := r.db.Table("krs_items").
+		Select("krs.mahasiswa_id, classes.course_id, courses.kode, courses.nama, courses.sks, krs_items.status, krs_items.nilai").
+		Joins("JOIN krs ON krs.id = krs_items.krs_id").
+		Joins("JOIN classes ON classes.id = krs_items.class_id").
+		Joins("JOIN courses ON courses.id = classes.course_id").
+		Where("krs.mahasiswa_id IN ?", mahasiswaIDs)
+	if len(inactiveStatuses) > 0 {
+		query = query.Where("krs_items.status NOT IN ?", inactiveStatuses)
+	}
+	if err := query.Scan(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
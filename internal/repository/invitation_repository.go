@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvitationFilter berisi filter opsional untuk list undangan
+type InvitationFilter struct {
+	StudyProgramID *uuid.UUID
+	PendingOnly    bool // hanya undangan yang belum dipakai
+}
+
+type InvitationRepository interface {
+	Create(invitation *models.UserInvitation) error
+	FindByID(id uuid.UUID) (*models.UserInvitation, error)
+	FindByTokenHash(tokenHash string) (*models.UserInvitation, error)
+	FindAll(filter InvitationFilter) ([]models.UserInvitation, error)
+	UpdateToken(id uuid.UUID, tokenHash string, expiresAt time.Time) error
+	Delete(id uuid.UUID) error
+	CountSince(userID uuid.UUID, since time.Time) (int64, error)
+	ActivateUser(userID uuid.UUID, passwordHash string, invitationID *uuid.UUID) error
+}
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) Create(invitation *models.UserInvitation) error {
+	return r.db.Omit("User").Create(invitation).Error
+}
+
+func (r *invitationRepository) FindByID(id uuid.UUID) (*models.UserInvitation, error) {
+	var invitation models.UserInvitation
+	if err := r.db.Preload("User").First(&invitation, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindByTokenHash(tokenHash string) (*models.UserInvitation, error) {
+	var invitation models.UserInvitation
+	if err := r.db.Preload("User").Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindAll mengembalikan undangan terbaru dulu
+func (r *invitationRepository) FindAll(filter InvitationFilter) ([]models.UserInvitation, error) {
+	invitations := make([]models.UserInvitation, 0)
+	query := r.db.Preload("User")
+	if filter.StudyProgramID != nil {
+		query = query.Where("user_id IN (?)", r.db.Model(&models.User{}).Select("id").Where("study_program_id = ?", *filter.StudyProgramID))
+	}
+	if filter.PendingOnly {
+		query = query.Where("used_at IS NULL")
+	}
+	if err := query.Order("created_at DESC").Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// UpdateToken mengganti token undangan yang belum dipakai (kirim ulang)
+func (r *invitationRepository) UpdateToken(id uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	result := r.db.Model(&models.UserInvitation{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"token_hash": tokenHash,
+			"expires_at": expiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *invitationRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.UserInvitation{}, "id = ?", id).Error
+}
+
+// CountSince menghitung undangan (termasuk link aktivasi registrasi mandiri) untuk user sejak waktu tertentu
+func (r *invitationRepository) CountSince(userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserInvitation{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// ActivateUser mengisi password akun pending, menandai undangan yang dipakai (jika ada),
+// dan menghapus undangan lain yang belum dipakai untuk user tersebut
+func (r *invitationRepository) ActivateUser(userID uuid.UUID, passwordHash string, invitationID *uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND is_pending = ?", userID, true).
+			Updates(map[string]interface{}{
+				"password":   passwordHash,
+				"is_pending": false,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		unused := tx.Where("user_id = ? AND used_at IS NULL", userID)
+		if invitationID != nil {
+			if err := tx.Model(&models.UserInvitation{}).
+				Where("id = ?", *invitationID).
+				Update("used_at", time.Now()).Error; err != nil {
+				return err
+			}
+			unused = unused.Where("id <> ?", *invitationID)
+		}
+		return unused.Delete(&models.UserInvitation{}).Error
+	})
+}
@@ -9,6 +9,17 @@ import (
 	"gorm.io/gorm"
 )
 
+// AvailableClassQuery berisi parameter pencarian kelas tersedia untuk mahasiswa
+type AvailableClassQuery struct {
+	Semester         string
+	ExcludedClassIDs []uuid.UUID
+	InactiveStatuses []string // status item KRS yang tidak menghabiskan kuota
+	MinSeats         *int     // sisa kuota minimal
+	Filter           ClassFilter
+	// PriorityCourseIDs ditampilkan lebih dulu sebelum urutan ?sort= (mis. rekomendasi kurikulum)
+	PriorityCourseIDs []uuid.UUID
+}
+
 type KRSRepository struct {
 	DB *gorm.DB
 }
@@ -22,6 +33,7 @@ func (r *KRSRepository) GetClassesByIDs(classIDs []uuid.UUID) ([]models.Class, e
 	err := r.DB.Where("id IN ?", classIDs).
 		Preload("Course").
 		Preload("Dosen").
+		Preload("Dosens.Dosen").
 		Find(&classes).Error
 	if err != nil {
 		return nil, err
@@ -61,6 +73,7 @@ func (r *KRSRepository) GetOrCreateKRS(mahasiswaID uuid.UUID, semester string, k
 		Preload("Items.KRS.Mahasiswa").
 		Preload("Items.Class.Course").
 		Preload("Items.Class.Dosen").
+		Preload("Items.Class.Dosens.Dosen").
 		Preload("Items.Class.Room").
 		First(&krs).Error
 
@@ -95,6 +108,7 @@ func (r *KRSRepository) GetKRSByMahasiswaID(mahasiswaID uuid.UUID, semester stri
 		Preload("Items.Class").                  // preload class di setiap item
 		Preload("Items.Class.Course").           // preload course di class
 		Preload("Items.Class.Dosen").            // preload dosen di class
+		Preload("Items.Class.Dosens.Dosen").     // preload tim pengajar di class
 		Preload("Items.Class.Room").             // preload room di class
 		First(&krs).Error
 
@@ -106,21 +120,75 @@ func (r *KRSRepository) GetKRSByMahasiswaID(mahasiswaID uuid.UUID, semester stri
 }
 
 // ListAvailableClasses menampilkan semua kelas yang ditawarkan di semester ini dan belum diambil oleh mahasiswa (Req. 1)
-func (r *KRSRepository) ListAvailableClasses(semester string, excludedClassIDs []uuid.UUID) ([]models.Class, error) {
-	var classes []models.Class
-	query := r.DB.
-		Where("semester_penawaran = ?", semester).
-		Preload("Course").
-		Preload("Dosen")
+func (r *KRSRepository) ListAvailableClasses(q AvailableClassQuery, page PageQuery) (*Page[models.Class], error) {
+	filter := q.Filter
+	filter.Semester = q.Semester
+	filter.IncludeDeleted = false
+
+	query := r.DB.Model(&models.Class{}).Joins("JOIN courses ON courses.id = classes.course_id")
+	query = applyClassFilter(r.DB, query, filter)
+
+	if len(q.ExcludedClassIDs) > 0 {
+		query = query.Where("classes.id NOT IN (?)", q.ExcludedClassIDs)
+	}
+	if q.MinSeats != nil {
+		enrolled := r.DB.Model(&models.KRSItem{}).Select("COUNT(*)").Where("krs_items.class_id = classes.id")
+		if len(q.InactiveStatuses) > 0 {
+			enrolled = enrolled.Where("krs_items.status NOT IN ?", q.InactiveStatuses)
+		}
+		query = query.Where("classes.kuota - (?) >= ?", enrolled, *q.MinSeats)
+	}
+	if len(q.PriorityCourseIDs) > 0 {
+		// ORDER BY ini didahulukan; urutan dari ?sort= ditambahkan paginate setelahnya
+		query = query.
+			Joins("LEFT JOIN courses AS priority_courses ON priority_courses.id = classes.course_id AND priority_courses.id IN ?", q.PriorityCourseIDs).
+			Order("priority_courses.id IS NULL")
+	}
+
+	return paginate[models.Class](query, page, classSortColumns, "kode,nama_kelas", "classes.id", preloadClass)
+}
 
-	if len(excludedClassIDs) > 0 {
-		query = query.Where("id NOT IN (?)", excludedClassIDs)
+// CountEnrolled menghitung jumlah item KRS aktif per kelas
+func (r *KRSRepository) CountEnrolled(classIDs []uuid.UUID, inactiveStatuses []string) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	if len(classIDs) == 0 {
+		return counts, nil
 	}
 
-	if err := query.Find(&classes).Error; err != nil {
+	var rows []struct {
+		ClassID uuid.UUID
+		Total   int
+	}
+	query := r.DB.Model(&models.KRSItem{}).
+		Select("class_id, COUNT(*) AS total").
+		Where("class_id IN ?", classIDs)
+	if len(inactiveStatuses) > 0 {
+		query = query.Where("status NOT IN ?", inactiveStatuses)
+	}
+	if err := query.Group("class_id").Scan(&rows).Error; err != nil {
 		return nil, err
 	}
-	return classes, nil
+
+	for _, row := range rows {
+		counts[row.ClassID] = row.Total
+	}
+	return counts, nil
+}
+
+// FindTakenCourseIDs mengembalikan matakuliah yang pernah diambil mahasiswa di KRS lain (semester sebelumnya)
+func (r *KRSRepository) FindTakenCourseIDs(mahasiswaID uuid.UUID, excludeKRSID uuid.UUID, inactiveStatuses []string) ([]uuid.UUID, error) {
+	var courseIDs []uuid.UUID
+	query := r.DB.Model(&models.KRSItem{}).
+		Joins("JOIN krs ON krs.id = krs_items.krs_id").
+		Joins("JOIN classes ON classes.id = krs_items.class_id").
+		Where("krs.mahasiswa_id = ? AND krs.id <> ?", mahasiswaID, excludeKRSID)
+	if len(inactiveStatuses) > 0 {
+		query = query.Where("krs_items.status NOT IN ?", inactiveStatuses)
+	}
+	if err := query.Distinct().Pluck("classes.course_id", &courseIDs).Error; err != nil {
+		return nil, err
+	}
+	return courseIDs, nil
 }
 
 // AddItem menambahkan KRS Item baru (Req. 2)
@@ -171,11 +239,11 @@ func (r *KRSRepository) RequestCancellation(krsID uuid.UUID, classID uuid.UUID,
 	return nil
 }
 
-// ListMahasiswaByDosenPA mengembalikan seluruh mahasiswa bimbingan dosen PA tertentu
-func (r *KRSRepository) ListMahasiswaByDosenPA(dosenID uuid.UUID) ([]models.User, error) {
-	var students []models.User
-	err := r.DB.Where("role = ? AND dosen_pa_id = ?", "mahasiswa", dosenID).Find(&students).Error
-	return students, err
+// ListMahasiswaByDosenPA mengembalikan mahasiswa bimbingan dosen PA tertentu
+func (r *KRSRepository) ListMahasiswaByDosenPA(dosenID uuid.UUID, filter MahasiswaFilter, page PageQuery) (*Page[models.User], error) {
+	filter.DosenPAID = &dosenID
+	query := applyMahasiswaFilter(r.DB.Model(&models.User{}), filter)
+	return paginate[models.User](query, page, mahasiswaSortColumns, "nim", "id", nil)
 }
 
 // UpdateKRSItemStatus memperbarui status item KRS (digunakan dosen PA saat verifikasi atau pembatalan)
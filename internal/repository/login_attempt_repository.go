@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepository interface {
+	Create(attempt *models.LoginAttempt) error
+	FindByUser(userID uuid.UUID, limit int) ([]models.LoginAttempt, error)
+	CountIPFailuresSince(ipAddress, reason string, since time.Time) (int64, *time.Time, error)
+	RecordFailure(userID uuid.UUID, windowStart time.Time, maxFailures int, lockedUntil time.Time) (*models.User, error)
+	ResetFailures(userID uuid.UUID) error
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Create(attempt *models.LoginAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+// FindByUser mengembalikan riwayat login user, terbaru dulu
+func (r *loginAttemptRepository) FindByUser(userID uuid.UUID, limit int) ([]models.LoginAttempt, error) {
+	attempts := make([]models.LoginAttempt, 0)
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&attempts).Error
+	if err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+// CountIPFailuresSince menghitung gagal login dengan alasan reason dari satu IP sejak since,
+// beserta waktu gagal terakhir (nil jika tidak ada)
+func (r *loginAttemptRepository) CountIPFailuresSince(ipAddress, reason string, since time.Time) (int64, *time.Time, error) {
+	var result struct {
+		Count int64
+		Last  *time.Time
+	}
+	err := r.db.Model(&models.LoginAttempt{}).
+		Select("COUNT(*) AS count, MAX(created_at) AS last").
+		Where("ip_address = ? AND success = ? AND failure_reason = ? AND created_at >= ?", ipAddress, false, reason, since).
+		Scan(&result).Error
+	return result.Count, result.Last, err
+}
+
+// RecordFailure menambah hitungan gagal login user secara atomik. Hitungan dimulai ulang jika gagal
+// terakhir sebelum windowStart, dan akun dikunci sampai lockedUntil saat hitungan mencapai maxFailures.
+func (r *loginAttemptRepository) RecordFailure(userID uuid.UUID, windowStart time.Time, maxFailures int, lockedUntil time.Time) (*models.User, error) {
+	count := gorm.Expr("CASE WHEN last_failed_login_at IS NULL OR last_failed_login_at < ? THEN 1 ELSE failed_login_count + 1 END", windowStart)
+	err := r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"failed_login_count":   count,
+			"last_failed_login_at": time.Now(),
+			"locked_until": gorm.Expr(
+				"CASE WHEN (CASE WHEN last_failed_login_at IS NULL OR last_failed_login_at < ? THEN 1 ELSE failed_login_count + 1 END) >= ? THEN ? ELSE locked_until END",
+				windowStart, maxFailures, lockedUntil,
+			),
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var user models.User
+	if err := r.db.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ResetFailures menghapus hitungan gagal login dan kunci akun (login berhasil / dibuka admin)
+func (r *loginAttemptRepository) ResetFailures(userID uuid.UUID) error {
+	result := r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"failed_login_count":   0,
+			"last_failed_login_at": nil,
+			"locked_until":         nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MahasiswaFilter berisi filter opsional untuk list mahasiswa
+type MahasiswaFilter struct {
+	Angkatan       *int
+	StudyProgramID *uuid.UUID
+	DosenPAID      *uuid.UUID
+	IsActive       *bool
+	Search         string // nama atau NIM
+}
+
+var mahasiswaSortColumns = sortColumns{
+	"nim":        "nim",
+	"name":       "name",
+	"angkatan":   "angkatan",
+	"created_at": "created_at",
+}
+
+// applyMahasiswaFilter menerapkan MahasiswaFilter ke query users (role mahasiswa)
+func applyMahasiswaFilter(query *gorm.DB, filter MahasiswaFilter) *gorm.DB {
+	query = query.Where("role = ?", "mahasiswa")
+	if filter.Angkatan != nil {
+		query = query.Where("angkatan = ?", *filter.Angkatan)
+	}
+	if filter.StudyProgramID != nil {
+		query = query.Where("study_program_id = ?", *filter.StudyProgramID)
+	}
+	if filter.DosenPAID != nil {
+		query = query.Where("dosen_pa_id = ?", *filter.DosenPAID)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+	if filter.Search != "" {
+		like := ilike(filter.Search)
+		query = query.Where("(name ILIKE ? OR nim ILIKE ?)", like, like)
+	}
+	return query
+}
+
+type MahasiswaRepository interface {
+	Create(user *models.User) error
+	FindByID(id uuid.UUID) (*models.User, error)
+	FindAll(filter MahasiswaFilter, page PageQuery) (*Page[models.User], error)
+	FindAllMatching(filter MahasiswaFilter) ([]models.User, error)
+	Update(user *models.User) error
+	FindByNIM(nim string) (*models.User, error)
+	FindByEmail(email string) (*models.User, error)
+	SetActive(id uuid.UUID, active bool) error
+}
+
+type mahasiswaRepository struct {
+	db *gorm.DB
+}
+
+func NewMahasiswaRepository(db *gorm.DB) MahasiswaRepository {
+	return &mahasiswaRepository{db: db}
+}
+
+func (r *mahasiswaRepository) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
+func (r *mahasiswaRepository) FindByID(id uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := r.db.Preload("StudyProgram").Where("id = ? AND role = ?", id, "mahasiswa").First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *mahasiswaRepository) FindAll(filter MahasiswaFilter, page PageQuery) (*Page[models.User], error) {
+	query := applyMahasiswaFilter(r.db.Model(&models.User{}), filter)
+	return paginate[models.User](query, page, mahasiswaSortColumns, "nim", "id", func(db *gorm.DB) *gorm.DB {
+		return db.Preload("StudyProgram")
+	})
+}
+
+// FindAllMatching mengembalikan seluruh mahasiswa yang cocok dengan filter tanpa paginasi (untuk laporan batch)
+func (r *mahasiswaRepository) FindAllMatching(filter MahasiswaFilter) ([]models.User, error) {
+	users := make([]models.User, 0)
+	query := applyMahasiswaFilter(r.db.Model(&models.User{}), filter)
+	if err := query.Preload("StudyProgram").Order("nim").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Update menyimpan data mahasiswa; dosen PA tidak ikut ditulis karena hanya diubah lewat penugasan dosen PA
+func (r *mahasiswaRepository) Update(user *models.User) error {
+	return r.db.Omit("StudyProgram", "DosenPAID").Save(user).Error
+}
+
+func (r *mahasiswaRepository) FindByNIM(nim string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("nim = ? AND role = ?", nim, "mahasiswa").First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *mahasiswaRepository) FindByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *mahasiswaRepository) SetActive(id uuid.UUID, active bool) error {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND role = ?", id, "mahasiswa").
+		Update("is_active", active)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OIDCRepository interface {
+	CreateState(state *models.OIDCLoginState) error
+	ConsumeState(stateHash string) (*models.OIDCLoginState, error)
+	FindUserBySubject(subject string) (*models.User, error)
+	FindUserByEmail(email string) (*models.User, error)
+	FindDosenByNIDN(nidn string) (*models.User, error)
+	LinkSubject(userID uuid.UUID, subject string) error
+	SetPasswordLoginDisabled(userID uuid.UUID, disabled bool) error
+}
+
+type oidcRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCRepository(db *gorm.DB) OIDCRepository {
+	return &oidcRepository{db: db}
+}
+
+// CreateState menyimpan state login baru dan membersihkan state yang sudah kedaluwarsa
+func (r *oidcRepository) CreateState(state *models.OIDCLoginState) error {
+	if err := r.db.Where("expires_at < ?", time.Now()).Delete(&models.OIDCLoginState{}).Error; err != nil {
+		return err
+	}
+	return r.db.Create(state).Error
+}
+
+// ConsumeState mengambil lalu menghapus state; state hanya bisa dipakai sekali walau callback dikirim bersamaan
+func (r *oidcRepository) ConsumeState(stateHash string) (*models.OIDCLoginState, error) {
+	var state models.OIDCLoginState
+	if err := r.db.Where("state_hash = ?", stateHash).First(&state).Error; err != nil {
+		return nil, err
+	}
+	result := r.db.Delete(&models.OIDCLoginState{}, "id = ?", state.ID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &state, nil
+}
+
+func (r *oidcRepository) FindUserBySubject(subject string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("oidc_subject = ?", subject).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindUserByEmail mencocokkan email tanpa membedakan huruf besar / kecil (email dari IdP bisa berbeda kapitalisasi)
+func (r *oidcRepository) FindUserByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("LOWER(email) = LOWER(?)", email).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *oidcRepository) FindDosenByNIDN(nidn string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("nidn = ? AND role = ?", nidn, "dosen").First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// LinkSubject menghubungkan akun dengan subject IdP (hanya jika belum terhubung). Akun pending
+// dianggap aktif karena identitasnya sudah diverifikasi IdP.
+func (r *oidcRepository) LinkSubject(userID uuid.UUID, subject string) error {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND oidc_subject IS NULL", userID).
+		Updates(map[string]interface{}{
+			"oidc_subject": subject,
+			"is_pending":   false,
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *oidcRepository) SetPasswordLoginDisabled(userID uuid.UUID, disabled bool) error {
+	result := r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"password_login_disabled": disabled,
+			"updated_at":              time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_PAGE_LIMIT = 20
+	MAX_PAGE_LIMIT     = 100
+)
+
+var ErrInvalidSort = errors.New("invalid sort field")
+
+// PageQuery berisi parameter pagination dan sorting dari query string.
+// Sort berupa daftar field dipisah koma, awali dengan "-" untuk urutan menurun (contoh: "-sks,kode").
+type PageQuery struct {
+	Limit  int
+	Offset int
+	Sort   string
+}
+
+// Page adalah envelope standar untuk semua endpoint list
+type Page[T any] struct {
+	Data   []T   `json:"data"`
+	Total  int64 `json:"total"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+// sortColumns memetakan nama field di ?sort= ke ekspresi SQL yang boleh dipakai untuk ORDER BY
+type sortColumns map[string]string
+
+// paginate menghitung total baris query lalu mengambil satu halaman data.
+// query hanya berisi filter; preload dipisah agar tidak ikut dijalankan saat COUNT.
+// tieBreaker ditambahkan di akhir ORDER BY supaya urutan antar halaman stabil.
+func paginate[T any](query *gorm.DB, page PageQuery, columns sortColumns, defaultSort, tieBreaker string, preload func(*gorm.DB) *gorm.DB) (*Page[T], error) {
+	if page.Limit <= 0 {
+		page.Limit = DEFAULT_PAGE_LIMIT
+	}
+	if page.Limit > MAX_PAGE_LIMIT {
+		page.Limit = MAX_PAGE_LIMIT
+	}
+	if page.Offset < 0 {
+		page.Offset = 0
+	}
+
+	sort := page.Sort
+	if sort == "" {
+		sort = defaultSort
+	}
+	order, err := buildOrder(sort, columns)
+	if err != nil {
+		return nil, err
+	}
+	if tieBreaker != "" {
+		order = append(order, tieBreaker)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Model(new(T)).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	data := make([]T, 0)
+	find := query.Session(&gorm.Session{})
+	if preload != nil {
+		find = preload(find)
+	}
+	if err := find.Order(strings.Join(order, ", ")).Limit(page.Limit).Offset(page.Offset).Find(&data).Error; err != nil {
+		return nil, err
+	}
+
+	return &Page[T]{Data: data, Total: total, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func buildOrder(sort string, columns sortColumns) ([]string, error) {
+	var order []string
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		direction := "ASC"
+		if strings.HasPrefix(field, "-") {
+			direction = "DESC"
+			field = field[1:]
+		}
+		column, ok := columns[field]
+		if !ok {
+			return nil, ErrInvalidSort
+		}
+		order = append(order, column+" "+direction)
+	}
+	return order, nil
+}
+
+// ilike membungkus kata kunci pencarian untuk dipakai dengan ILIKE
+func ilike(search string) string {
+	return "%" + search + "%"
+}
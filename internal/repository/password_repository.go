@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PasswordRepository interface {
+	CreateResetToken(token *models.PasswordResetToken) error
+	FindResetTokenByHash(tokenHash string) (*models.PasswordResetToken, error)
+	CountResetTokensSince(userID uuid.UUID, since time.Time) (int64, error)
+	ResetPassword(tokenID, userID uuid.UUID, passwordHash string) error
+	UpdatePassword(userID uuid.UUID, passwordHash string) error
+}
+
+type passwordRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordRepository(db *gorm.DB) PasswordRepository {
+	return &passwordRepository{db: db}
+}
+
+func (r *passwordRepository) CreateResetToken(token *models.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *passwordRepository) FindResetTokenByHash(tokenHash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// CountResetTokensSince dipakai membatasi frekuensi email reset per user
+func (r *passwordRepository) CountResetTokensSince(userID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// ResetPassword menandai token terpakai (hanya jika belum dipakai), mengganti password,
+// dan menghapus token reset lain milik user dalam satu transaksi
+func (r *passwordRepository) ResetPassword(tokenID, userID uuid.UUID, passwordHash string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", tokenID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := NewPasswordRepository(tx).UpdatePassword(userID, passwordHash); err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND id <> ? AND used_at IS NULL", userID, tokenID).
+			Delete(&models.PasswordResetToken{}).Error
+	})
+}
+
+// UpdatePassword mengganti password, menghapus kewajiban ganti password dan membuka kunci login
+func (r *passwordRepository) UpdatePassword(userID uuid.UUID, passwordHash string) error {
+	result := r.db.Model(&models.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"password":             passwordHash,
+			"must_change_password": false,
+			"failed_login_count":   0,
+			"last_failed_login_at": nil,
+			"locked_until":         nil,
+			"updated_at":           time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
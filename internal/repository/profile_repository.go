@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// KRSSummary adalah ringkasan KRS mahasiswa pada satu semester untuk halaman profil
+type KRSSummary struct {
+	Status     string `json:"status"`
+	ClassCount int    `json:"class_count"`
+	TotalSKS   int    `json:"total_sks"`
+}
+
+type ProfileRepository interface {
+	FindUser(id uuid.UUID) (*models.User, error)
+	CountAdvisees(dosenID uuid.UUID) (int64, error)
+	FindKRSSummary(mahasiswaID uuid.UUID, semester string, inactiveStatuses []string) (*KRSSummary, error)
+	UpdateContact(userID uuid.UUID, fields map[string]interface{}) error
+	UpdatePhotoKey(userID uuid.UUID, key string) error
+}
+
+type profileRepository struct {
+	db *gorm.DB
+}
+
+func NewProfileRepository(db *gorm.DB) ProfileRepository {
+	return &profileRepository{db: db}
+}
+
+func (r *profileRepository) FindUser(id uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := r.db.Preload("StudyProgram.Faculty").First(&user, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CountAdvisees menghitung mahasiswa aktif bimbingan PA seorang dosen
+func (r *profileRepository) CountAdvisees(dosenID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).
+		Where("dosen_pa_id = ? AND role = ? AND is_active = ?", dosenID, "mahasiswa", true).
+		Count(&count).Error
+	return count, err
+}
+
+// FindKRSSummary mengembalikan status KRS dan jumlah kelas / SKS yang masih aktif.
+// nil berarti mahasiswa belum punya KRS di semester tersebut.
+func (r *profileRepository) FindKRSSummary(mahasiswaID uuid.UUID, semester string, inactiveStatuses []string) (*KRSSummary, error) {
+	var krs models.KRS
+	err := r.db.Where("mahasiswa_id = ? AND semester = ?", mahasiswaID, semester).First(&krs).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var totals struct {
+		ClassCount int
+		TotalSKS   int
+	}
+	err = r.db.Table("krs_items AS ki").
+		Select("COUNT(ki.id) AS class_count, COALESCE(SUM(co.sks), 0) AS total_sks").
+		Joins("JOIN classes AS cl ON cl.id = ki.class_id AND cl.deleted_at IS NULL").
+		Joins("JOIN courses AS co ON co.id = cl.course_id").
+		Where("ki.krs_id = ? AND ki.status NOT IN ?", krs.ID, inactiveStatuses).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &KRSSummary{Status: krs.Status, ClassCount: totals.ClassCount, TotalSKS: totals.TotalSKS}, nil
+}
+
+func (r *profileRepository) UpdateContact(userID uuid.UUID, fields map[string]interface{}) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Updates(fields).Error
+}
+
+func (r *profileRepository) UpdatePhotoKey(userID uuid.UUID, key string) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Update("photo_key", key).Error
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ProgramOutcomeRepository interface {
+	Create(outcome *models.ProgramOutcome) error
+	FindByID(id uuid.UUID) (*models.ProgramOutcome, error)
+	FindByIDs(ids []uuid.UUID) ([]models.ProgramOutcome, error)
+	FindByStudyProgram(studyProgramID uuid.UUID) ([]models.ProgramOutcome, error)
+	FindByKode(studyProgramID uuid.UUID, kode string) (*models.ProgramOutcome, error)
+	Update(outcome *models.ProgramOutcome) error
+	Delete(id uuid.UUID) error
+	CountMappings(id uuid.UUID) (int64, error)
+}
+
+type programOutcomeRepository struct {
+	db *gorm.DB
+}
+
+func NewProgramOutcomeRepository(db *gorm.DB) ProgramOutcomeRepository {
+	return &programOutcomeRepository{db: db}
+}
+
+func (r *programOutcomeRepository) Create(outcome *models.ProgramOutcome) error {
+	return r.db.Create(outcome).Error
+}
+
+func (r *programOutcomeRepository) FindByID(id uuid.UUID) (*models.ProgramOutcome, error) {
+	var outcome models.ProgramOutcome
+	if err := r.db.First(&outcome, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &outcome, nil
+}
+
+func (r *programOutcomeRepository) FindByIDs(ids []uuid.UUID) ([]models.ProgramOutcome, error) {
+	var outcomes []models.ProgramOutcome
+	if len(ids) == 0 {
+		return outcomes, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Find(&outcomes).Error; err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+func (r *programOutcomeRepository) FindByStudyProgram(studyProgramID uuid.UUID) ([]models.ProgramOutcome, error) {
+	outcomes := make([]models.ProgramOutcome, 0)
+	if err := r.db.Where("study_program_id = ?", studyProgramID).Order("kode").Find(&outcomes).Error; err != nil {
+		return nil, err
+	}
+	return outcomes, nil
+}
+
+func (r *programOutcomeRepository) FindByKode(studyProgramID uuid.UUID, kode string) (*models.ProgramOutcome, error) {
+	var outcome models.ProgramOutcome
+	if err := r.db.Where("study_program_id = ? AND kode = ?", studyProgramID, kode).First(&outcome).Error; err != nil {
+		return nil, err
+	}
+	return &outcome, nil
+}
+
+func (r *programOutcomeRepository) Update(outcome *models.ProgramOutcome) error {
+	return r.db.Save(outcome).Error
+}
+
+func (r *programOutcomeRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.ProgramOutcome{}, "id = ?", id).Error
+}
+
+// CountMappings menghitung CPMK (di semua versi silabus) yang dipetakan ke CPL ini
+func (r *programOutcomeRepository) CountMappings(id uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.LearningOutcomeMapping{}).Where("program_outcome_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	Create(role *models.Role) error
+	FindByID(id uuid.UUID) (*models.Role, error)
+	FindByName(name string) (*models.Role, error)
+	FindByIDs(ids []uuid.UUID) ([]models.Role, error)
+	FindAll() ([]models.Role, error)
+	Update(role *models.Role) error
+	Delete(id uuid.UUID) error
+	CountUsers(id uuid.UUID) (int64, error)
+	FindUserRoles(userID uuid.UUID) ([]models.Role, error)
+	FindEffectiveRoles(userID uuid.UUID, accountRole string) ([]models.Role, error)
+	ReplaceUserRoles(userID uuid.UUID, roleIDs []uuid.UUID) error
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) Create(role *models.Role) error {
+	return r.db.Create(role).Error
+}
+
+func (r *roleRepository) FindByID(id uuid.UUID) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.First(&role, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) FindByName(name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) FindByIDs(ids []uuid.UUID) ([]models.Role, error) {
+	roles := make([]models.Role, 0)
+	if len(ids) == 0 {
+		return roles, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// FindAll mengembalikan role sistem dulu, lalu role lain urut nama
+func (r *roleRepository) FindAll() ([]models.Role, error) {
+	roles := make([]models.Role, 0)
+	if err := r.db.Order("is_system DESC, name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *roleRepository) Update(role *models.Role) error {
+	return r.db.Save(role).Error
+}
+
+func (r *roleRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Role{}, "id = ?", id).Error
+}
+
+// CountUsers menghitung user yang diberi role ini sebagai role tambahan
+func (r *roleRepository) CountUsers(id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserRole{}).Where("role_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+// FindUserRoles mengembalikan role tambahan milik user (tanpa role sistem dari users.role)
+func (r *roleRepository) FindUserRoles(userID uuid.UUID) ([]models.Role, error) {
+	roles := make([]models.Role, 0)
+	err := r.db.
+		Where("id IN (?)", r.db.Model(&models.UserRole{}).Select("role_id").Where("user_id = ?", userID)).
+		Order("name").
+		Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// FindEffectiveRoles mengembalikan role sistem sesuai users.role ditambah role tambahan user
+func (r *roleRepository) FindEffectiveRoles(userID uuid.UUID, accountRole string) ([]models.Role, error) {
+	roles := make([]models.Role, 0)
+	err := r.db.
+		Where("(is_system = ? AND name = ?) OR id IN (?)", true, accountRole,
+			r.db.Model(&models.UserRole{}).Select("role_id").Where("user_id = ?", userID)).
+		Order("is_system DESC, name").
+		Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// ReplaceUserRoles mengganti seluruh role tambahan user dalam satu transaksi
+func (r *roleRepository) ReplaceUserRoles(userID uuid.UUID, roleIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+		userRoles := make([]models.UserRole, 0, len(roleIDs))
+		for _, roleID := range roleIDs {
+			userRoles = append(userRoles, models.UserRole{UserID: userID, RoleID: roleID})
+		}
+		return tx.Omit("Role").Create(&userRoles).Error
+	})
+}
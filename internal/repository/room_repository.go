@@ -7,12 +7,25 @@ import (
 	"gorm.io/gorm"
 )
 
+// RoomFilter berisi filter opsional untuk list ruangan
+type RoomFilter struct {
+	IncludeDeleted bool
+	Search         string // nama ruangan
+}
+
+var roomSortColumns = sortColumns{
+	"nama": "nama",
+}
+
 type RoomRepository interface {
 	Create(room *models.Room) error
 	FindByID(id uuid.UUID) (*models.Room, error)
-	FindAll() ([]models.Room, error)
+	FindAll(filter RoomFilter, page PageQuery) (*Page[models.Room], error)
 	Update(room *models.Room) error
 	Delete(id uuid.UUID) error
+	Restore(id uuid.UUID) error
+	FindDeletedByID(id uuid.UUID) (*models.Room, error)
+	CountActiveClasses(id uuid.UUID) (int64, error)
 	FindByNama(nama string) (*models.Room, error)
 }
 
@@ -36,12 +49,15 @@ func (r *roomRepository) FindByID(id uuid.UUID) (*models.Room, error) {
 	return &room, nil
 }
 
-func (r *roomRepository) FindAll() ([]models.Room, error) {
-	var rooms []models.Room
-	if err := r.db.Find(&rooms).Error; err != nil {
-		return nil, err
+func (r *roomRepository) FindAll(filter RoomFilter, page PageQuery) (*Page[models.Room], error) {
+	query := r.db.Model(&models.Room{})
+	if filter.IncludeDeleted {
+		query = query.Unscoped()
+	}
+	if filter.Search != "" {
+		query = query.Where("nama ILIKE ?", ilike(filter.Search))
 	}
-	return rooms, nil
+	return paginate[models.Room](query, page, roomSortColumns, "nama", "id", nil)
 }
 
 func (r *roomRepository) Update(room *models.Room) error {
@@ -52,6 +68,40 @@ func (r *roomRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.Room{}, "id = ?", id).Error
 }
 
+// Restore mengembalikan room yang sudah di-soft delete
+func (r *roomRepository) Restore(id uuid.UUID) error {
+	result := r.db.Unscoped().Model(&models.Room{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *roomRepository) FindDeletedByID(id uuid.UUID) (*models.Room, error) {
+	var room models.Room
+	if err := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&room).Error; err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// CountActiveClasses menghitung kelas (yang belum dihapus) yang masih memakai room ini
+func (r *roomRepository) CountActiveClasses(id uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&models.Class{}).Where("room_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 func (r *roomRepository) FindByNama(nama string) (*models.Room, error) {
 	var room models.Room
 	if err := r.db.Where("nama = ?", nama).First(&room).Error; err != nil {
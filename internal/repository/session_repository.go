@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SessionRepository interface {
+	Create(session *models.UserSession) error
+	FindByID(id uuid.UUID) (*models.UserSession, error)
+	FindByRefreshTokenHash(tokenHash string) (*models.UserSession, error)
+	FindByPreviousTokenHash(tokenHash string) (*models.UserSession, error)
+	FindActiveByUser(userID uuid.UUID) ([]models.UserSession, error)
+	Rotate(id uuid.UUID, oldHash, newHash string, expiresAt time.Time) error
+	IsActive(id uuid.UUID) (bool, error)
+	Revoke(id uuid.UUID) error
+	RevokeAllForUser(userID uuid.UUID, exceptID *uuid.UUID) (int64, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(session *models.UserSession) error {
+	return r.db.Create(session).Error
+}
+
+func (r *sessionRepository) FindByID(id uuid.UUID) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.First(&session, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) FindByRefreshTokenHash(tokenHash string) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.Where("refresh_token_hash = ?", tokenHash).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) FindByPreviousTokenHash(tokenHash string) (*models.UserSession, error) {
+	var session models.UserSession
+	if err := r.db.Where("previous_token_hash = ?", tokenHash).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindActiveByUser mengembalikan sesi yang belum dicabut dan belum kedaluwarsa, terakhir dipakai dulu
+func (r *sessionRepository) FindActiveByUser(userID uuid.UUID) ([]models.UserSession, error) {
+	sessions := make([]models.UserSession, 0)
+	err := r.db.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// Rotate mengganti refresh token sesi. Update bersyarat pada hash lama sehingga dua refresh
+// bersamaan dengan token yang sama hanya satu yang berhasil.
+func (r *sessionRepository) Rotate(id uuid.UUID, oldHash, newHash string, expiresAt time.Time) error {
+	result := r.db.Model(&models.UserSession{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", id, oldHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  newHash,
+			"previous_token_hash": oldHash,
+			"last_used_at":        time.Now(),
+			"expires_at":          expiresAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// IsActive dipakai middleware JWT untuk menolak access token dari sesi yang sudah dicabut
+func (r *sessionRepository) IsActive(id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ?", id, time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *sessionRepository) Revoke(id uuid.UUID) error {
+	return r.db.Model(&models.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeAllForUser mencabut semua sesi aktif user, kecuali exceptID jika diisi
+func (r *sessionRepository) RevokeAllForUser(userID uuid.UUID, exceptID *uuid.UUID) (int64, error) {
+	query := r.db.Model(&models.UserSession{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptID != nil {
+		query = query.Where("id <> ?", *exceptID)
+	}
+	result := query.Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StudyProgramRepository interface {
+	Create(program *models.StudyProgram) error
+	FindByID(id uuid.UUID) (*models.StudyProgram, error)
+	FindAll(facultyID *uuid.UUID) ([]models.StudyProgram, error)
+	Update(program *models.StudyProgram) error
+	Delete(id uuid.UUID) error
+	FindByKode(kode string) (*models.StudyProgram, error)
+	CountDependents(id uuid.UUID) (map[string]int64, error)
+}
+
+type studyProgramRepository struct {
+	db *gorm.DB
+}
+
+func NewStudyProgramRepository(db *gorm.DB) StudyProgramRepository {
+	return &studyProgramRepository{db: db}
+}
+
+func (r *studyProgramRepository) Create(program *models.StudyProgram) error {
+	return r.db.Create(program).Error
+}
+
+func (r *studyProgramRepository) FindByID(id uuid.UUID) (*models.StudyProgram, error) {
+	var program models.StudyProgram
+	if err := r.db.Preload("Faculty").First(&program, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &program, nil
+}
+
+func (r *studyProgramRepository) FindAll(facultyID *uuid.UUID) ([]models.StudyProgram, error) {
+	var programs []models.StudyProgram
+	query := r.db.Preload("Faculty")
+	if facultyID != nil {
+		query = query.Where("faculty_id = ?", *facultyID)
+	}
+	if err := query.Order("kode").Find(&programs).Error; err != nil {
+		return nil, err
+	}
+	return programs, nil
+}
+
+func (r *studyProgramRepository) Update(program *models.StudyProgram) error {
+	return r.db.Omit("Faculty").Save(program).Error
+}
+
+func (r *studyProgramRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.StudyProgram{}, "id = ?", id).Error
+}
+
+func (r *studyProgramRepository) FindByKode(kode string) (*models.StudyProgram, error) {
+	var program models.StudyProgram
+	if err := r.db.Where("kode = ?", kode).First(&program).Error; err != nil {
+		return nil, err
+	}
+	return &program, nil
+}
+
+// CountDependents menghitung course, kelas, user, CPL, dan kurikulum yang masih terhubung ke program studi ini
+func (r *studyProgramRepository) CountDependents(id uuid.UUID) (map[string]int64, error) {
+	counts := make(map[string]int64)
+	for name, model := range map[string]interface{}{
+		"courses":          &models.Course{},
+		"classes":          &models.Class{},
+		"users":            &models.User{},
+		"program_outcomes": &models.ProgramOutcome{},
+		"curricula":        &models.Curriculum{},
+	} {
+		var count int64
+		if err := r.db.Model(model).Where("study_program_id = ?", id).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		counts[name] = count
+	}
+	return counts, nil
+}
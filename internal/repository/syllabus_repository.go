@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SyllabusRepository interface {
+	FindVersions(courseID uuid.UUID) ([]models.CourseSyllabus, error)
+	FindEffective(courseID uuid.UUID, urutanSemester int) (*models.CourseSyllabus, error)
+	SaveVersion(syllabus *models.CourseSyllabus) error
+	DeleteVersion(courseID uuid.UUID, urutanSemester int) error
+	IsCourseCoordinator(courseID, dosenID uuid.UUID) (bool, error)
+	FindCoordinatedCourses(dosenID uuid.UUID) ([]models.Course, error)
+}
+
+// preloadSyllabus memuat CPMK (beserta CPL), RPS dan referensi sebuah versi silabus
+func preloadSyllabus(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("LearningOutcomes", func(db *gorm.DB) *gorm.DB { return db.Order("kode") }).
+		Preload("LearningOutcomes.ProgramOutcomes.ProgramOutcome").
+		Preload("Topics", func(db *gorm.DB) *gorm.DB { return db.Order("minggu") }).
+		Preload("References", func(db *gorm.DB) *gorm.DB { return db.Order("urutan") })
+}
+
+type syllabusRepository struct {
+	db *gorm.DB
+}
+
+func NewSyllabusRepository(db *gorm.DB) SyllabusRepository {
+	return &syllabusRepository{db: db}
+}
+
+// FindVersions mengembalikan seluruh versi silabus course (tanpa detail), terbaru dulu
+func (r *syllabusRepository) FindVersions(courseID uuid.UUID) ([]models.CourseSyllabus, error) {
+	versions := make([]models.CourseSyllabus, 0)
+	if err := r.db.Where("course_id = ?", courseID).Order("urutan_semester DESC").Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// FindEffective mengembalikan versi terakhir yang mulai berlaku pada atau sebelum urutanSemester
+func (r *syllabusRepository) FindEffective(courseID uuid.UUID, urutanSemester int) (*models.CourseSyllabus, error) {
+	var syllabus models.CourseSyllabus
+	if err := preloadSyllabus(r.db).
+		Where("course_id = ? AND urutan_semester <= ?", courseID, urutanSemester).
+		Order("urutan_semester DESC").
+		First(&syllabus).Error; err != nil {
+		return nil, err
+	}
+	return &syllabus, nil
+}
+
+// SaveVersion membuat atau mengganti seluruh isi versi silabus untuk semester mulai berlakunya
+func (r *syllabusRepository) SaveVersion(syllabus *models.CourseSyllabus) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.CourseSyllabus
+		err := tx.Where("course_id = ? AND urutan_semester = ?", syllabus.CourseID, syllabus.UrutanSemester).First(&existing).Error
+		switch {
+		case err == nil:
+			if err := deleteSyllabusDetails(tx, existing.ID); err != nil {
+				return err
+			}
+			syllabus.ID = existing.ID
+			syllabus.CreatedAt = existing.CreatedAt
+			if err := tx.Omit(clause.Associations).Save(syllabus).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := tx.Omit(clause.Associations).Create(syllabus).Error; err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		for i := range syllabus.LearningOutcomes {
+			outcome := &syllabus.LearningOutcomes[i]
+			outcome.ID = uuid.Nil
+			outcome.SyllabusID = syllabus.ID
+			if err := tx.Omit(clause.Associations).Create(outcome).Error; err != nil {
+				return err
+			}
+			for j := range outcome.ProgramOutcomes {
+				mapping := &outcome.ProgramOutcomes[j]
+				mapping.ID = uuid.Nil
+				mapping.LearningOutcomeID = outcome.ID
+				if err := tx.Omit(clause.Associations).Create(mapping).Error; err != nil {
+					return err
+				}
+			}
+		}
+		for i := range syllabus.Topics {
+			syllabus.Topics[i].ID = uuid.Nil
+			syllabus.Topics[i].SyllabusID = syllabus.ID
+			if err := tx.Create(&syllabus.Topics[i]).Error; err != nil {
+				return err
+			}
+		}
+		for i := range syllabus.References {
+			syllabus.References[i].ID = uuid.Nil
+			syllabus.References[i].SyllabusID = syllabus.ID
+			if err := tx.Create(&syllabus.References[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *syllabusRepository) DeleteVersion(courseID uuid.UUID, urutanSemester int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing models.CourseSyllabus
+		if err := tx.Where("course_id = ? AND urutan_semester = ?", courseID, urutanSemester).First(&existing).Error; err != nil {
+			return err
+		}
+		if err := deleteSyllabusDetails(tx, existing.ID); err != nil {
+			return err
+		}
+		return tx.Delete(&existing).Error
+	})
+}
+
+// deleteSyllabusDetails menghapus CPMK, pemetaan CPL, RPS dan referensi sebuah versi silabus
+func deleteSyllabusDetails(tx *gorm.DB, syllabusID uuid.UUID) error {
+	outcomeIDs := tx.Model(&models.LearningOutcome{}).Select("id").Where("syllabus_id = ?", syllabusID)
+	if err := tx.Where("learning_outcome_id IN (?)", outcomeIDs).Delete(&models.LearningOutcomeMapping{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("syllabus_id = ?", syllabusID).Delete(&models.LearningOutcome{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("syllabus_id = ?", syllabusID).Delete(&models.SyllabusTopic{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("syllabus_id = ?", syllabusID).Delete(&models.SyllabusReference{}).Error
+}
+
+// IsCourseCoordinator memeriksa apakah dosen menjadi koordinator (Class.DosenID) salah satu kelas aktif course
+func (r *syllabusRepository) IsCourseCoordinator(courseID, dosenID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.Class{}).
+		Where("course_id = ? AND dosen_id = ?", courseID, dosenID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindCoordinatedCourses mengembalikan course yang salah satu kelas aktifnya dikoordinasi dosen
+func (r *syllabusRepository) FindCoordinatedCourses(dosenID uuid.UUID) ([]models.Course, error) {
+	courses := make([]models.Course, 0)
+	if err := r.db.
+		Where("id IN (?)", r.db.Model(&models.Class{}).Select("course_id").Where("dosen_id = ?", dosenID)).
+		Order("kode").
+		Find(&courses).Error; err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
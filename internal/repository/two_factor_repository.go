@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"course-planner-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TwoFactorRepository interface {
+	FindByUser(userID uuid.UUID) (*models.UserTwoFactor, error)
+	SaveSecret(userID uuid.UUID, secretEncrypted string) error
+	Confirm(userID uuid.UUID, step int64, codeHashes []string) error
+	UpdateLastUsedStep(userID uuid.UUID, step int64) (bool, error)
+	Delete(userID uuid.UUID) error
+	ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error
+	UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(userID uuid.UUID) (int64, error)
+	CreateChallenge(challenge *models.LoginChallenge) error
+	FindChallengeByHash(tokenHash string) (*models.LoginChallenge, error)
+	IncrementChallengeAttempts(id uuid.UUID) error
+	DeleteChallenge(id uuid.UUID) (bool, error)
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+func (r *twoFactorRepository) FindByUser(userID uuid.UUID) (*models.UserTwoFactor, error) {
+	var twoFactor models.UserTwoFactor
+	if err := r.db.First(&twoFactor, "user_id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// SaveSecret menyimpan secret baru yang belum dikonfirmasi; enrollment sebelumnya yang belum
+// dikonfirmasi ditimpa
+func (r *twoFactorRepository) SaveSecret(userID uuid.UUID, secretEncrypted string) error {
+	return r.db.Save(&models.UserTwoFactor{
+		UserID:          userID,
+		SecretEncrypted: secretEncrypted,
+		CreatedAt:       time.Now(),
+	}).Error
+}
+
+// Confirm mengaktifkan 2FA (hanya jika belum aktif) sekaligus menyimpan recovery code pertama
+func (r *twoFactorRepository) Confirm(userID uuid.UUID, step int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.UserTwoFactor{}).
+			Where("user_id = ? AND confirmed_at IS NULL", userID).
+			Updates(map[string]interface{}{
+				"confirmed_at":   time.Now(),
+				"last_used_step": step,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return NewTwoFactorRepository(tx).ReplaceRecoveryCodes(userID, codeHashes)
+	})
+}
+
+// UpdateLastUsedStep mencatat periode TOTP yang dipakai. Update bersyarat sehingga dua request
+// bersamaan dengan kode yang sama hanya satu yang berhasil.
+func (r *twoFactorRepository) UpdateLastUsedStep(userID uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&models.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Delete menonaktifkan 2FA: secret, recovery code dan challenge login yang tersisa dihapus
+func (r *twoFactorRepository) Delete(userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.LoginChallenge{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.UserTwoFactor{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes mengganti semua recovery code user (yang lama tidak berlaku lagi)
+func (r *twoFactorRepository) ReplaceRecoveryCodes(userID uuid.UUID, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codeHashes) == 0 {
+			return nil
+		}
+
+		codes := make([]models.RecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, models.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode menandai recovery code terpakai; false jika kode tidak dikenal atau sudah dipakai
+func (r *twoFactorRepository) UseRecoveryCode(userID uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *twoFactorRepository) CountUnusedRecoveryCodes(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// CreateChallenge menyimpan challenge login baru dan membersihkan challenge user yang sudah kedaluwarsa
+func (r *twoFactorRepository) CreateChallenge(challenge *models.LoginChallenge) error {
+	if err := r.db.Where("user_id = ? AND expires_at < ?", challenge.UserID, time.Now()).Delete(&models.LoginChallenge{}).Error; err != nil {
+		return err
+	}
+	return r.db.Create(challenge).Error
+}
+
+func (r *twoFactorRepository) FindChallengeByHash(tokenHash string) (*models.LoginChallenge, error) {
+	var challenge models.LoginChallenge
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&challenge).Error; err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *twoFactorRepository) IncrementChallengeAttempts(id uuid.UUID) error {
+	return r.db.Model(&models.LoginChallenge{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// DeleteChallenge menghapus challenge; false jika sudah dihapus request lain (challenge hanya sekali pakai)
+func (r *twoFactorRepository) DeleteChallenge(id uuid.UUID) (bool, error) {
+	result := r.db.Delete(&models.LoginChallenge{}, "id = ?", id)
+	return result.RowsAffected > 0, result.Error
+}
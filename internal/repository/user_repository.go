@@ -3,12 +3,15 @@ package repository
 import (
 	"course-planner-api/internal/models"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
 	Create(user *models.User) error
 	FindByEmail(email string) (*models.User, error)
+	FindByID(id uuid.UUID) (*models.User, error)
+	FindMahasiswaByNIM(nim string) (*models.User, error)
 }
 
 type userRepository struct {
@@ -31,3 +34,18 @@ func (r *userRepository) FindByEmail(email string) (*models.User, error) {
 	return &user, nil
 }
 
+func (r *userRepository) FindByID(id uuid.UUID) (*models.User, error) {
+	var user models.User
+	if err := r.db.Preload("StudyProgram").First(&user, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindMahasiswaByNIM(nim string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("nim = ? AND role = ?", nim, "mahasiswa").First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
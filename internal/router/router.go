@@ -2,11 +2,14 @@ package router
 
 import (
 	"course-planner-api/internal/handler"
-	"os"
+	"course-planner-api/internal/jwtauth"
+	"course-planner-api/internal/service"
+	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
-	jwtware "github.com/gofiber/jwt/v3"
-	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func SetupRoutes(
@@ -19,92 +22,407 @@ func SetupRoutes(
 	roomHandler *handler.RoomHandler,
 	dosenMgmtHandler *handler.DosenManagementHandler,
 	bookHandler *handler.BookHandler,
+	notificationHandler *handler.NotificationHandler,
+	mahasiswaMgmtHandler *handler.MahasiswaManagementHandler,
+	dosenPAAssignmentHandler *handler.DosenPAAssignmentHandler,
+	facultyHandler *handler.FacultyHandler,
+	studyProgramHandler *handler.StudyProgramHandler,
+	importHandler *handler.ImportHandler,
+	exportHandler *handler.ExportHandler,
+	auditHandler *handler.AuditHandler,
+	catalogHandler *handler.CatalogHandler,
+	programOutcomeHandler *handler.ProgramOutcomeHandler,
+	syllabusHandler *handler.SyllabusHandler,
+	curriculumHandler *handler.CurriculumHandler,
+	gradeHandler *handler.GradeHandler,
+	degreeAuditHandler *handler.DegreeAuditHandler,
+	invitationHandler *handler.InvitationHandler,
+	roleHandler *handler.RoleHandler,
+	passwordHandler *handler.PasswordHandler,
+	profileHandler *handler.ProfileHandler,
+	twoFactorHandler *handler.TwoFactorHandler,
+	apiKeyHandler *handler.APIKeyHandler,
+	tokenKeys *jwtauth.KeySet,
 ) {
+	// JWKS publik agar service kampus lain bisa memverifikasi access token (pilih kunci dari header kid)
+	app.Get("/.well-known/jwks.json", publicCacheMiddleware(JWKS_CACHE_MAX_AGE), func(c *fiber.Ctx) error {
+		return c.JSON(tokenKeys.JWKS())
+	})
+
 	api := app.Group("/api")
 
+	// can menolak request jika token tidak memuat permission (claim permissions, lihat service.PERMISSIONS)
+	can := requirePermission
+	canAny := requireAnyPermission
+	// canGlobally untuk operasi lintas prodi (ruangan, fakultas, prodi, restore, role, dll)
+	canGlobally := requireGlobalPermission
+
+	// audit mencatat setiap request yang mengubah data (lihat handler.AuditHandler.Track)
+	audit := auditHandler.Track
+
+	// requireAuth memverifikasi access token lalu menolak token dari sesi yang sudah dicabut (logout)
+	requireAuth := jwtMiddleware(tokenKeys, authHandler.RequireActiveSession)
+
 	auth := api.Group("/auth")
-	auth.Post("/register", authHandler.Register)
+	auth.Post("/register", audit("user", "register", nil), authHandler.Register)
 	auth.Post("/login", authHandler.Login)
+	auth.Post("/login/verify", authHandler.VerifyTwoFactor)
+	auth.Post("/oidc/authorize", authHandler.OIDCAuthorize)
+	auth.Post("/oidc/callback", authHandler.OIDCCallback)
+	auth.Post("/refresh", authHandler.Refresh)
+	auth.Post("/logout", requireAuth, audit("session", "logout", nil), authHandler.Logout)
+	auth.Post("/activate", audit("invitation", "accept", nil), invitationHandler.AcceptInvitation)
+	auth.Post("/forgot-password", passwordHandler.ForgotPassword)
+	auth.Post("/reset-password", audit("user", "reset_password", nil), passwordHandler.ResetPassword)
+
+	// Katalog publik: tanpa autentikasi, bisa di-cache dan mendukung ETag / If-None-Match
+	catalog := api.Group("/catalog")
+	catalog.Use(publicCacheMiddleware(CATALOG_CACHE_MAX_AGE), etag.New())
+	catalog.Get("/courses", catalogHandler.ListCourses)
+	catalog.Get("/courses/:kode", catalogHandler.GetCourse)
+	catalog.Get("/schedule", catalogHandler.ListSchedule)
 
 	protected := api.Group("/me")
-	protected.Use(jwtMiddleware())
-	protected.Get("/", func(c *fiber.Ctx) error {
-		// JWT middleware sudah mem-parse token, di sini hanya contoh respon
-		return c.JSON(fiber.Map{"message": "authenticated endpoint"})
-	})
+	protected.Use(requireAuth)
+	protected.Get("/", profileHandler.GetProfile)
+	protected.Patch("/", audit("user", "update_profile", profileHandler.AuditSnapshot), profileHandler.UpdateProfile)
+	protected.Put("/photo", audit("user", "update_photo", profileHandler.AuditSnapshot), profileHandler.UploadPhoto)
+	protected.Delete("/photo", audit("user", "delete_photo", profileHandler.AuditSnapshot), profileHandler.DeletePhoto)
+	protected.Put("/password", audit("user", "change_password", nil), passwordHandler.ChangePassword)
+	protected.Get("/login-history", authHandler.LoginHistory)
+	protected.Patch("/password-login", audit("user", "update_password_login", authHandler.PasswordLoginAuditSnapshot), authHandler.SetPasswordLogin)
+	protected.Get("/2fa", twoFactorHandler.GetStatus)
+	protected.Delete("/2fa", audit("two_factor", "disable", twoFactorHandler.AuditSnapshot), twoFactorHandler.Disable)
+	protected.Post("/2fa/enroll", audit("two_factor", "enroll", twoFactorHandler.AuditSnapshot), twoFactorHandler.Enroll)
+	protected.Post("/2fa/confirm", audit("two_factor", "confirm", twoFactorHandler.AuditSnapshot), twoFactorHandler.Confirm)
+	protected.Post("/2fa/recovery-codes", audit("two_factor", "regenerate_recovery_codes", twoFactorHandler.AuditSnapshot), twoFactorHandler.RegenerateRecoveryCodes)
+	protected.Get("/sessions", authHandler.ListSessions)
+	protected.Delete("/sessions", audit("session", "revoke_others", nil), authHandler.RevokeOtherSessions)
+	protected.Delete("/sessions/:id", audit("session", "revoke", handler.AuditIDParam("id")), authHandler.RevokeSession)
+	protected.Get("/notifications", notificationHandler.ListNotifications)
+	protected.Patch("/notifications/:id/read", audit("notification", "mark_read", handler.AuditIDParam("id")), notificationHandler.MarkAsRead)
+	protected.Get("/curriculum", can(service.PERM_STUDY_PLAN_READ), curriculumHandler.GetMyCurriculum)
+	protected.Get("/degree-audit", can(service.PERM_STUDY_PLAN_READ), degreeAuditHandler.GetMyDegreeAudit)
 
+	// Admin - JWT, atau API key sistem lain untuk route read-only di API_KEY_ROUTE_PREFIXES
 	admin := api.Group("/admin")
-	admin.Use(jwtMiddleware(), adminOnlyMiddleware())
+	admin.Use(jwtOrAPIKeyMiddleware(requireAuth, apiKeyHandler.Authenticate))
 
 	krs := api.Group("/krs")
-	krs.Use(jwtMiddleware(), roleOnlyMiddleware("mahasiswa"))
+	krs.Use(requireAuth, can(service.PERM_KRS_WRITE))
 	krs.Get("/", krsHandler.GetTakenClasses)
 	krs.Get("/available-classes", krsHandler.ListAvailableClasses)
 	krsItems := krs.Group("/items")
-	krsItems.Post("/", krsHandler.TakeClass)
-	krsItems.Delete("/:classId", krsHandler.DropClass)
-	krsItems.Patch("/:classId/request-cancellation", krsHandler.RequestCancellation)
+	krsItems.Post("/", audit("krs", "take_class", krsHandler.AuditSnapshot), krsHandler.TakeClass)
+	krsItems.Delete("/:classId", audit("krs", "drop_class", krsHandler.AuditSnapshot), krsHandler.DropClass)
+	krsItems.Patch("/:classId/request-cancellation", audit("krs", "request_cancellation", krsHandler.AuditSnapshot), krsHandler.RequestCancellation)
 
 	dosen := api.Group("/dosen")
-	dosen.Use(jwtMiddleware(), roleOnlyMiddleware("dosen"))
-	dosen.Get("/students", dosenHandler.ListStudents)
-	dosen.Get("/students/:mahasiswaId/krs", dosenHandler.GetMahasiswaKRS)
-	dosenItems := dosen.Group("/students/:mahasiswaId/krs/items")
-	dosenItems.Delete("/:classId", dosenHandler.RemoveMahasiswaClass)
-	dosenItems.Patch("/:classId", dosenHandler.UpdateMahasiswaClass)
-	dosenItems.Patch("/:classId/approve", dosenHandler.ApproveMahasiswaClass)
-	dosenItems.Patch("/:classId/reject", dosenHandler.RejectMahasiswaClass)
+	dosen.Use(requireAuth)
+	dosen.Get("/students", can(service.PERM_ADVISEE_READ), dosenHandler.ListStudents)
+	dosen.Get("/students/:mahasiswaId", can(service.PERM_ADVISEE_READ), degreeAuditHandler.GetAdviseeDegreeAudit)
+	dosen.Get("/students/:mahasiswaId/krs", can(service.PERM_ADVISEE_READ), dosenHandler.GetMahasiswaKRS)
+	dosenItems := dosen.Group("/students/:mahasiswaId/krs/items", can(service.PERM_KRS_VERIFY))
+	dosenItems.Delete("/:classId", audit("krs", "remove_class", dosenHandler.AuditSnapshot), dosenHandler.RemoveMahasiswaClass)
+	dosenItems.Patch("/:classId", audit("krs", "update_class", dosenHandler.AuditSnapshot), dosenHandler.UpdateMahasiswaClass)
+	dosenItems.Patch("/:classId/approve", audit("krs", "approve_class", dosenHandler.AuditSnapshot), dosenHandler.ApproveMahasiswaClass)
+	dosenItems.Patch("/:classId/reject", audit("krs", "reject_class", dosenHandler.AuditSnapshot), dosenHandler.RejectMahasiswaClass)
+
+	// Dosen - Silabus course yang kelasnya dikoordinasi
+	dosenCourses := dosen.Group("/courses", can(service.PERM_SYLLABUS_WRITE))
+	dosenCourses.Get("/", syllabusHandler.ListCoordinatedCourses)
+	dosenCourses.Get("/:id/syllabus", syllabusHandler.GetSyllabus)
+	dosenCourses.Get("/:id/syllabus/versions", syllabusHandler.ListSyllabusVersions)
+	dosenCourses.Put("/:id/syllabus", audit("syllabus", "save", syllabusHandler.AuditSnapshot), syllabusHandler.SaveSyllabus)
+	dosen.Get("/study-programs/:id/cpl", can(service.PERM_SYLLABUS_WRITE), programOutcomeHandler.ListProgramOutcomes)
+
+	// Dosen - Nilai peserta kelas yang diampu
+	dosen.Get("/classes/:id/grades", can(service.PERM_GRADE_WRITE), gradeHandler.ListClassGrades)
+	dosen.Put("/classes/:id/grades", can(service.PERM_GRADE_WRITE), audit("grade", "submit", gradeHandler.AuditSnapshot), gradeHandler.SubmitGrades)
+
+	// Admin - Undangan akun (onboarding dosen / mahasiswa / admin)
+	invitations := admin.Group("/invitations", can(service.PERM_INVITATION_MANAGE))
+	invitations.Get("/", invitationHandler.ListInvitations)
+	invitations.Post("/", audit("invitation", "create", invitationHandler.AuditSnapshot), invitationHandler.CreateInvitation)
+	invitations.Get("/:id", invitationHandler.GetInvitation)
+	invitations.Post("/:id/resend", audit("invitation", "resend", invitationHandler.AuditSnapshot), invitationHandler.ResendInvitation)
+	invitations.Delete("/:id", audit("invitation", "delete", invitationHandler.AuditSnapshot), invitationHandler.RevokeInvitation)
+
+	// Admin - Role & permission (hanya admin global)
+	admin.Get("/permissions", can(service.PERM_ROLE_MANAGE), roleHandler.ListPermissions)
+	roles := admin.Group("/roles", canGlobally(service.PERM_ROLE_MANAGE))
+	roles.Get("/", roleHandler.ListRoles)
+	roles.Post("/", audit("role", "create", roleHandler.AuditSnapshot), roleHandler.CreateRole)
+	roles.Get("/:id", roleHandler.GetRole)
+	roles.Patch("/:id", audit("role", "update", roleHandler.AuditSnapshot), roleHandler.UpdateRole)
+	roles.Delete("/:id", audit("role", "delete", roleHandler.AuditSnapshot), roleHandler.DeleteRole)
+	// Admin - Buka kunci akun & reset 2FA (izin dicek per jenis akun di handler)
+	admin.Post("/users/:id/unlock", canAny(service.PERM_MAHASISWA_WRITE, service.PERM_DOSEN_WRITE, service.PERM_ROLE_MANAGE), audit("user", "unlock", authHandler.UnlockAuditSnapshot), authHandler.UnlockAccount)
+	admin.Delete("/users/:id/2fa", canAny(service.PERM_MAHASISWA_WRITE, service.PERM_DOSEN_WRITE, service.PERM_ROLE_MANAGE), audit("two_factor", "reset", twoFactorHandler.AuditSnapshot), twoFactorHandler.ResetUserTwoFactor)
+
+	// Admin - API key sistem lain (hanya admin global)
+	apiKeys := admin.Group("/api-keys", canGlobally(service.PERM_ROLE_MANAGE))
+	apiKeys.Get("/", apiKeyHandler.ListAPIKeys)
+	apiKeys.Post("/", audit("api_key", "create", apiKeyHandler.AuditSnapshot), apiKeyHandler.CreateAPIKey)
+	apiKeys.Get("/scopes", apiKeyHandler.ListAPIKeyScopes)
+	apiKeys.Get("/:id", apiKeyHandler.GetAPIKey)
+	apiKeys.Patch("/:id", audit("api_key", "update", apiKeyHandler.AuditSnapshot), apiKeyHandler.UpdateAPIKey)
+	apiKeys.Delete("/:id", audit("api_key", "revoke", apiKeyHandler.AuditSnapshot), apiKeyHandler.RevokeAPIKey)
+
+	userRoles := admin.Group("/users/:id/roles", canGlobally(service.PERM_ROLE_MANAGE))
+	userRoles.Get("/", roleHandler.GetUserRoles)
+	userRoles.Put("/", audit("user_role", "replace", roleHandler.UserRolesAuditSnapshot), roleHandler.ReplaceUserRoles)
 
 	// Admin - Classes
 	classes := admin.Group("/classes")
-	classes.Get("/", classHandler.ListClasses)
-	classes.Post("/", classHandler.CreateClass)
-	classes.Get("/:id", classHandler.GetClass)
-	classes.Patch("/:id", classHandler.UpdateClass)
-	classes.Delete("/:id", classHandler.DeleteClass)
+	classes.Get("/", can(service.PERM_CLASS_READ), classHandler.ListClasses)
+	classes.Post("/", can(service.PERM_CLASS_WRITE), audit("class", "create", classHandler.AuditSnapshot), classHandler.CreateClass)
+	classes.Get("/:id", can(service.PERM_CLASS_READ), classHandler.GetClass)
+	classes.Patch("/:id", can(service.PERM_CLASS_WRITE), audit("class", "update", classHandler.AuditSnapshot), classHandler.UpdateClass)
+	classes.Delete("/:id", can(service.PERM_CLASS_WRITE), audit("class", "delete", classHandler.AuditSnapshot), classHandler.DeleteClass)
+	classes.Post("/:id/restore", canGlobally(service.PERM_CLASS_WRITE), audit("class", "restore", classHandler.AuditSnapshot), classHandler.RestoreClass)
+	classes.Get("/:id/grades", can(service.PERM_GRADE_MANAGE), gradeHandler.ListClassGrades)
+	classes.Put("/:id/grades", can(service.PERM_GRADE_MANAGE), audit("grade", "submit", gradeHandler.AuditSnapshot), gradeHandler.SubmitGrades)
 
 	// Admin - Courses
 	courses := admin.Group("/courses")
-	courses.Get("/", courseHandler.ListCourses)
-	courses.Post("/", courseHandler.CreateCourse)
-	courses.Get("/:id", courseHandler.GetCourse)
-	courses.Patch("/:id", courseHandler.UpdateCourse)
-	courses.Delete("/:id", courseHandler.DeleteCourse)
+	courses.Get("/", can(service.PERM_COURSE_READ), courseHandler.ListCourses)
+	courses.Post("/", can(service.PERM_COURSE_WRITE), audit("course", "create", courseHandler.AuditSnapshot), courseHandler.CreateCourse)
+	courses.Get("/:id", can(service.PERM_COURSE_READ), courseHandler.GetCourse)
+	courses.Patch("/:id", can(service.PERM_COURSE_WRITE), audit("course", "update", courseHandler.AuditSnapshot), courseHandler.UpdateCourse)
+	courses.Delete("/:id", can(service.PERM_COURSE_WRITE), audit("course", "delete", courseHandler.AuditSnapshot), courseHandler.DeleteCourse)
+	courses.Post("/:id/restore", canGlobally(service.PERM_COURSE_WRITE), audit("course", "restore", courseHandler.AuditSnapshot), courseHandler.RestoreCourse)
+	courses.Get("/:id/syllabus", can(service.PERM_COURSE_READ), syllabusHandler.GetSyllabus)
+	courses.Get("/:id/syllabus/versions", can(service.PERM_COURSE_READ), syllabusHandler.ListSyllabusVersions)
+	courses.Put("/:id/syllabus", can(service.PERM_COURSE_WRITE), audit("syllabus", "save", syllabusHandler.AuditSnapshot), syllabusHandler.SaveSyllabus)
+	courses.Delete("/:id/syllabus", can(service.PERM_COURSE_WRITE), audit("syllabus", "delete", syllabusHandler.AuditSnapshot), syllabusHandler.DeleteSyllabus)
+
+	// Admin - Curricula
+	curricula := admin.Group("/curricula")
+	curricula.Get("/", can(service.PERM_CURRICULUM_READ), curriculumHandler.ListCurricula)
+	curricula.Post("/", can(service.PERM_CURRICULUM_WRITE), audit("curriculum", "create", curriculumHandler.AuditSnapshot), curriculumHandler.CreateCurriculum)
+	curricula.Get("/:id", can(service.PERM_CURRICULUM_READ), curriculumHandler.GetCurriculum)
+	curricula.Patch("/:id", can(service.PERM_CURRICULUM_WRITE), audit("curriculum", "update", curriculumHandler.AuditSnapshot), curriculumHandler.UpdateCurriculum)
+	curricula.Delete("/:id", can(service.PERM_CURRICULUM_WRITE), audit("curriculum", "delete", curriculumHandler.AuditSnapshot), curriculumHandler.DeleteCurriculum)
+	curricula.Put("/:id/courses", can(service.PERM_CURRICULUM_WRITE), audit("curriculum", "replace_courses", curriculumHandler.AuditSnapshot), curriculumHandler.ReplaceCourses)
 
 	// Admin - Rooms
 	rooms := admin.Group("/rooms")
-	rooms.Get("/", roomHandler.ListRooms)
-	rooms.Post("/", roomHandler.CreateRoom)
-	rooms.Get("/:id", roomHandler.GetRoom)
-	rooms.Patch("/:id", roomHandler.UpdateRoom)
-	rooms.Delete("/:id", roomHandler.DeleteRoom)
+	rooms.Get("/", can(service.PERM_ROOM_READ), roomHandler.ListRooms)
+	rooms.Post("/", canGlobally(service.PERM_ROOM_WRITE), audit("room", "create", roomHandler.AuditSnapshot), roomHandler.CreateRoom)
+	rooms.Get("/:id", can(service.PERM_ROOM_READ), roomHandler.GetRoom)
+	rooms.Patch("/:id", canGlobally(service.PERM_ROOM_WRITE), audit("room", "update", roomHandler.AuditSnapshot), roomHandler.UpdateRoom)
+	rooms.Delete("/:id", canGlobally(service.PERM_ROOM_WRITE), audit("room", "delete", roomHandler.AuditSnapshot), roomHandler.DeleteRoom)
+	rooms.Post("/:id/restore", canGlobally(service.PERM_ROOM_WRITE), audit("room", "restore", roomHandler.AuditSnapshot), roomHandler.RestoreRoom)
 
 	// Admin - Dosen Management
 	dosenMgmt := admin.Group("/dosen")
-	dosenMgmt.Get("/", dosenMgmtHandler.ListDosen)
-	dosenMgmt.Get("/:id", dosenMgmtHandler.GetDosen)
-	dosenMgmt.Patch("/:id", dosenMgmtHandler.UpdateDosen)
+	dosenMgmt.Get("/", can(service.PERM_DOSEN_READ), dosenMgmtHandler.ListDosen)
+	dosenMgmt.Post("/", can(service.PERM_DOSEN_WRITE), audit("dosen", "create", dosenMgmtHandler.AuditSnapshot), dosenMgmtHandler.CreateDosen)
+	dosenMgmt.Get("/:id", can(service.PERM_DOSEN_READ), dosenMgmtHandler.GetDosen)
+	dosenMgmt.Patch("/:id", can(service.PERM_DOSEN_WRITE), audit("dosen", "update", dosenMgmtHandler.AuditSnapshot), dosenMgmtHandler.UpdateDosen)
+	dosenMgmt.Get("/:id/teaching-load", can(service.PERM_DOSEN_READ), dosenMgmtHandler.GetTeachingLoad)
+
+	// Admin - Mahasiswa Management
+	mahasiswaMgmt := admin.Group("/mahasiswa")
+	mahasiswaMgmt.Get("/", can(service.PERM_MAHASISWA_READ), mahasiswaMgmtHandler.ListMahasiswa)
+	mahasiswaMgmt.Post("/", can(service.PERM_MAHASISWA_WRITE), audit("mahasiswa", "create", mahasiswaMgmtHandler.AuditSnapshot), mahasiswaMgmtHandler.CreateMahasiswa)
+	mahasiswaMgmt.Get("/:id", can(service.PERM_MAHASISWA_READ), mahasiswaMgmtHandler.GetMahasiswa)
+	mahasiswaMgmt.Patch("/:id", can(service.PERM_MAHASISWA_WRITE), audit("mahasiswa", "update", mahasiswaMgmtHandler.AuditSnapshot), mahasiswaMgmtHandler.UpdateMahasiswa)
+	mahasiswaMgmt.Patch("/:id/deactivate", can(service.PERM_MAHASISWA_WRITE), audit("mahasiswa", "deactivate", mahasiswaMgmtHandler.AuditSnapshot), mahasiswaMgmtHandler.DeactivateMahasiswa)
+	mahasiswaMgmt.Patch("/:id/activate", can(service.PERM_MAHASISWA_WRITE), audit("mahasiswa", "activate", mahasiswaMgmtHandler.AuditSnapshot), mahasiswaMgmtHandler.ActivateMahasiswa)
+	mahasiswaMgmt.Get("/:id/degree-audit", can(service.PERM_DEGREE_AUDIT_READ), degreeAuditHandler.GetMahasiswaDegreeAudit)
+	mahasiswaMgmt.Put("/:id/dosen-pa", canGlobally(service.PERM_DOSEN_PA_ASSIGN), audit("mahasiswa", "assign_dosen_pa", mahasiswaMgmtHandler.AuditSnapshot), dosenPAAssignmentHandler.AssignAdvisee)
+
+	// Admin - Laporan mahasiswa yang memenuhi syarat lulus
+	admin.Get("/degree-audits/eligible", can(service.PERM_DEGREE_AUDIT_READ), degreeAuditHandler.ListEligibleStudents)
+
+	// Admin - Dosen PA Assignment
+	dosenPA := admin.Group("/dosen-pa")
+	dosenPA.Use(canGlobally(service.PERM_DOSEN_PA_ASSIGN))
+	dosenPA.Get("/workload", dosenPAAssignmentHandler.GetWorkload)
+	dosenPA.Post("/assignments", audit("dosen_pa", "bulk_assign", nil), dosenPAAssignmentHandler.BulkAssignAdvisees)
+	dosenPA.Post("/auto-balance", audit("dosen_pa", "auto_balance", nil), dosenPAAssignmentHandler.AutoBalance)
+
+	// Admin - Faculties
+	faculties := admin.Group("/faculties")
+	faculties.Get("/", can(service.PERM_FACULTY_READ), facultyHandler.ListFaculties)
+	faculties.Post("/", canGlobally(service.PERM_FACULTY_WRITE), audit("faculty", "create", facultyHandler.AuditSnapshot), facultyHandler.CreateFaculty)
+	faculties.Get("/:id", can(service.PERM_FACULTY_READ), facultyHandler.GetFaculty)
+	faculties.Patch("/:id", canGlobally(service.PERM_FACULTY_WRITE), audit("faculty", "update", facultyHandler.AuditSnapshot), facultyHandler.UpdateFaculty)
+	faculties.Delete("/:id", canGlobally(service.PERM_FACULTY_WRITE), audit("faculty", "delete", facultyHandler.AuditSnapshot), facultyHandler.DeleteFaculty)
+
+	// Admin - Study Programs
+	studyPrograms := admin.Group("/study-programs")
+	studyPrograms.Get("/", can(service.PERM_STUDY_PROGRAM_READ), studyProgramHandler.ListStudyPrograms)
+	studyPrograms.Post("/", canGlobally(service.PERM_STUDY_PROGRAM_WRITE), audit("study_program", "create", studyProgramHandler.AuditSnapshot), studyProgramHandler.CreateStudyProgram)
+	studyPrograms.Get("/:id", can(service.PERM_STUDY_PROGRAM_READ), studyProgramHandler.GetStudyProgram)
+	studyPrograms.Patch("/:id", canGlobally(service.PERM_STUDY_PROGRAM_WRITE), audit("study_program", "update", studyProgramHandler.AuditSnapshot), studyProgramHandler.UpdateStudyProgram)
+	studyPrograms.Delete("/:id", canGlobally(service.PERM_STUDY_PROGRAM_WRITE), audit("study_program", "delete", studyProgramHandler.AuditSnapshot), studyProgramHandler.DeleteStudyProgram)
+
+	// Admin - CPL (capaian pembelajaran lulusan) per prodi
+	studyPrograms.Get("/:id/cpl", can(service.PERM_STUDY_PROGRAM_READ), programOutcomeHandler.ListProgramOutcomes)
+	studyPrograms.Post("/:id/cpl", can(service.PERM_CPL_WRITE), audit("program_outcome", "create", programOutcomeHandler.AuditSnapshot), programOutcomeHandler.CreateProgramOutcome)
+	studyPrograms.Patch("/:id/cpl/:cplId", can(service.PERM_CPL_WRITE), audit("program_outcome", "update", programOutcomeHandler.AuditSnapshot), programOutcomeHandler.UpdateProgramOutcome)
+	studyPrograms.Delete("/:id/cpl/:cplId", can(service.PERM_CPL_WRITE), audit("program_outcome", "delete", programOutcomeHandler.AuditSnapshot), programOutcomeHandler.DeleteProgramOutcome)
+
+	// Admin - Bulk Import (CSV)
+	admin.Post("/import/:entity", canGlobally(service.PERM_IMPORT_RUN), audit("import", "import", handler.AuditIDParam("entity")), importHandler.Import)
+
+	// Admin - Exports (CSV / XLSX)
+	exports := admin.Group("/exports", can(service.PERM_EXPORT_RUN))
+	exports.Get("/classes/:id/roster", exportHandler.ExportClassRoster)
+	exports.Get("/krs-items", exportHandler.ExportKRSItems)
+	exports.Get("/room-schedules", exportHandler.ExportRoomSchedules)
+	exports.Get("/teaching-lists", exportHandler.ExportTeachingList)
+
+	// Admin - Audit Log
+	admin.Get("/audit", can(service.PERM_AUDIT_READ), auditHandler.ListAuditLogs)
 
 	// Books - External API (Google Books) - UAS Feature
 	books := api.Group("/books")
-	books.Use(jwtMiddleware())
+	books.Use(requireAuth)
 	books.Get("/", bookHandler.SearchBooks)
 	books.Get("/:id", bookHandler.GetBookDetail)
 }
 
-func jwtMiddleware() fiber.Handler {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "secret"
+// CATALOG_CACHE_MAX_AGE adalah lama (detik) response katalog boleh di-cache client / proxy
+const CATALOG_CACHE_MAX_AGE = 300
+
+// JWKS_CACHE_MAX_AGE dibuat pendek agar kunci baru cepat terlihat saat rotasi
+const JWKS_CACHE_MAX_AGE = 300
+
+// publicCacheMiddleware menandai response sukses sebagai public cacheable
+func publicCacheMiddleware(maxAge int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if status := c.Response().StatusCode(); status == fiber.StatusOK || status == fiber.StatusNotModified {
+			c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(maxAge))
+		}
+		return nil
 	}
+}
 
-	return jwtware.New(jwtware.Config{
-		SigningKey: []byte(secret),
-		ContextKey: "user",
-	})
+// jwtMiddleware memverifikasi access token dari header Authorization: Bearer dengan key set,
+// menyimpan token di Locals("user"), lalu menjalankan sessionCheck
+func jwtMiddleware(keys *jwtauth.KeySet, sessionCheck fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth := c.Get(fiber.HeaderAuthorization)
+		tokenString, found := strings.CutPrefix(auth, "Bearer ")
+		if !found || tokenString == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Missing or malformed JWT"})
+		}
+
+		token, err := keys.Parse(tokenString)
+		if err != nil || !token.Valid {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid or expired JWT"})
+		}
+
+		c.Locals("user", token)
+		if restriction := blockedByRestriction(token, c.Method()+" "+strings.TrimSuffix(c.Path(), "/")); restriction != nil {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": restriction.Message})
+		}
+		return sessionCheck(c)
+	}
+}
+
+// API_KEY_ROUTE_PREFIXES adalah group route admin yang juga menerima API key (header X-API-Key).
+// Hanya request GET yang diterima; permission per route tetap dicek terhadap scope key.
+var API_KEY_ROUTE_PREFIXES = []string{
+	"/api/admin/classes",
+	"/api/admin/mahasiswa",
+	"/api/admin/exports",
 }
 
-func adminOnlyMiddleware() fiber.Handler {
+// jwtOrAPIKeyMiddleware memakai apiKeyAuth jika request membawa header X-API-Key, selain itu requireAuth
+func jwtOrAPIKeyMiddleware(requireAuth, apiKeyAuth fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Get(handler.API_KEY_HEADER) == "" {
+			return requireAuth(c)
+		}
+		if !acceptsAPIKey(c.Method(), c.Path()) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "API keys are not accepted on this route"})
+		}
+		return apiKeyAuth(c)
+	}
+}
+
+func acceptsAPIKey(method, path string) bool {
+	if method != fiber.MethodGet {
+		return false
+	}
+	path = strings.TrimSuffix(path, "/")
+	for _, prefix := range API_KEY_ROUTE_PREFIXES {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenRestriction membatasi token dengan claim boolean Claim hanya ke AllowedRoutes ("METHOD /path")
+type tokenRestriction struct {
+	Claim         string
+	Message       string
+	AllowedRoutes map[string]bool
+}
+
+// TOKEN_RESTRICTIONS: akun yang wajib ganti password (akun seeder / dibuat admin) atau wajib
+// mendaftar 2FA (role dengan require_two_factor) hanya bisa mengakses endpoint untuk menyelesaikannya
+var TOKEN_RESTRICTIONS = []tokenRestriction{
+	{
+		Claim:   "must_change_password",
+		Message: "password change required, use PUT /api/me/password",
+		AllowedRoutes: map[string]bool{
+			"GET /api/me":           true,
+			"PUT /api/me/password":  true,
+			"POST /api/auth/logout": true,
+		},
+	},
+	{
+		Claim:   "two_factor_enrollment_required",
+		Message: "two-factor authentication is required for your role, enroll via POST /api/me/2fa/enroll",
+		AllowedRoutes: map[string]bool{
+			"GET /api/me":              true,
+			"GET /api/me/2fa":          true,
+			"POST /api/me/2fa/enroll":  true,
+			"POST /api/me/2fa/confirm": true,
+			"POST /api/auth/logout":    true,
+		},
+	},
+}
+
+// blockedByRestriction mengembalikan pembatasan yang menolak route untuk token ini, atau nil.
+// Jika token punya beberapa pembatasan, route boleh diakses jika diizinkan salah satunya
+// agar semuanya bisa diselesaikan.
+func blockedByRestriction(token *jwt.Token, route string) *tokenRestriction {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil
+	}
+
+	var blocked *tokenRestriction
+	for i := range TOKEN_RESTRICTIONS {
+		restriction := &TOKEN_RESTRICTIONS[i]
+		if active, _ := claims[restriction.Claim].(bool); !active {
+			continue
+		}
+		if restriction.AllowedRoutes[route] {
+			return nil
+		}
+		if blocked == nil {
+			blocked = restriction
+		}
+	}
+	return blocked
+}
+
+// requireGlobalPermission seperti requirePermission, untuk operasi lintas prodi. Token yang di-scope ke
+// prodi (claim study_program_id) hanya lolos jika permission berasal dari role global user (claim
+// global_permissions), misal dosen kaprodi yang juga staf_ruangan tetap bisa mengelola ruangan.
+func requireGlobalPermission(permission string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		token, ok := c.Locals("user").(*jwt.Token)
 		if !ok || token == nil {
@@ -116,16 +434,27 @@ func adminOnlyMiddleware() fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
 		}
 
-		role, _ := claims["role"].(string)
-		if role != "admin" {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden"})
+		granted, _ := claims["permissions"].([]interface{})
+		if scope, _ := claims["study_program_id"].(string); scope != "" {
+			granted, _ = claims["global_permissions"].([]interface{})
 		}
-
-		return c.Next()
+		for _, p := range granted {
+			if p == permission {
+				return c.Next()
+			}
+		}
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden - required permission outside your study program: " + permission})
 	}
 }
 
-func roleOnlyMiddleware(requiredRole string) fiber.Handler {
+// requirePermission menolak request jika claim permissions di token tidak memuat permission
+func requirePermission(permission string) fiber.Handler {
+	return requireAnyPermission(permission)
+}
+
+// requireAnyPermission menolak request jika token tidak memuat satu pun dari permissions;
+// pengecekan yang lebih spesifik dilakukan di handler
+func requireAnyPermission(permissions ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		token, ok := c.Locals("user").(*jwt.Token)
 		if !ok || token == nil {
@@ -137,11 +466,14 @@ func roleOnlyMiddleware(requiredRole string) fiber.Handler {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "unauthorized"})
 		}
 
-		role, _ := claims["role"].(string)
-		if role != requiredRole {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden - required role: " + requiredRole})
+		granted, _ := claims["permissions"].([]interface{})
+		for _, p := range granted {
+			for _, permission := range permissions {
+				if p == permission {
+					return c.Next()
+				}
+			}
 		}
-
-		return c.Next()
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "forbidden - required permission: " + strings.Join(permissions, " or ")})
 	}
 }
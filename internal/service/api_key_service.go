@@ -0,0 +1,233 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// API_KEY_PREFIX menandai key agar mudah dikenali bila bocor di log atau repository kode
+	API_KEY_PREFIX = "cpk_"
+	// API_KEY_DISPLAY_LENGTH adalah panjang awal key yang disimpan apa adanya untuk ditampilkan di daftar
+	API_KEY_DISPLAY_LENGTH = 12
+	// API_KEY_LAST_USED_INTERVAL membatasi seberapa sering last_used_at ditulis untuk satu key
+	API_KEY_LAST_USED_INTERVAL = time.Minute
+	MAX_API_KEY_NAME_LENGTH    = 100
+)
+
+// API_KEY_SCOPES adalah permission read-only yang boleh diberikan ke API key
+var API_KEY_SCOPES = []string{PERM_CLASS_READ, PERM_MAHASISWA_READ, PERM_EXPORT_RUN}
+
+var (
+	ErrInvalidAPIKey    = errors.New("invalid, expired or revoked API key")
+	ErrAPIKeyNotFound   = errors.New("API key not found")
+	ErrAPIKeyRevoked    = errors.New("API key already revoked")
+	ErrInvalidAPIKeyTTL = errors.New("expires_at must be in the future")
+)
+
+type CreateAPIKeyInput struct {
+	Name           string
+	Scopes         []string
+	StudyProgramID *uuid.UUID // nil berarti key bisa membaca data semua prodi
+	ExpiresAt      *time.Time // nil berarti tidak kedaluwarsa
+	CreatedByID    *uuid.UUID
+}
+
+// UpdateAPIKeyInput mengubah key yang masih aktif; nil berarti tidak diubah
+type UpdateAPIKeyInput struct {
+	Name      *string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// IssuedAPIKey berisi key dalam bentuk asli. Key hanya ditampilkan sekali saat dibuat.
+type IssuedAPIKey struct {
+	APIKey *models.APIKey `json:"api_key"`
+	Key    string         `json:"key"`
+}
+
+type APIKeyService interface {
+	ListScopes() []PermissionInfo
+	ListAPIKeys() ([]models.APIKey, error)
+	GetAPIKey(id uuid.UUID) (*models.APIKey, error)
+	CreateAPIKey(input CreateAPIKeyInput) (*IssuedAPIKey, error)
+	UpdateAPIKey(id uuid.UUID, input UpdateAPIKeyInput) (*models.APIKey, error)
+	RevokeAPIKey(id uuid.UUID) error
+	Authenticate(key, ipAddress string) (*models.APIKey, error)
+}
+
+type apiKeyService struct {
+	repo             repository.APIKeyRepository
+	studyProgramRepo repository.StudyProgramRepository
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository, studyProgramRepo repository.StudyProgramRepository) APIKeyService {
+	return &apiKeyService{repo: repo, studyProgramRepo: studyProgramRepo}
+}
+
+func (s *apiKeyService) ListScopes() []PermissionInfo {
+	scopes := make([]PermissionInfo, 0, len(API_KEY_SCOPES))
+	for _, name := range API_KEY_SCOPES {
+		if p, ok := findPermission(name); ok {
+			scopes = append(scopes, p)
+		}
+	}
+	return scopes
+}
+
+func (s *apiKeyService) ListAPIKeys() ([]models.APIKey, error) {
+	return s.repo.FindAll()
+}
+
+func (s *apiKeyService) GetAPIKey(id uuid.UUID) (*models.APIKey, error) {
+	key, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (s *apiKeyService) CreateAPIKey(input CreateAPIKeyInput) (*IssuedAPIKey, error) {
+	name, err := validateAPIKeyName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := validateAPIKeyScopes(input.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidAPIKeyTTL
+	}
+	if input.StudyProgramID != nil {
+		if _, err := s.studyProgramRepo.FindByID(*input.StudyProgramID); err != nil {
+			return nil, errors.New("study program not found")
+		}
+	}
+
+	token, _, err := newOneTimeToken()
+	if err != nil {
+		return nil, err
+	}
+	plain := API_KEY_PREFIX + token
+
+	key := &models.APIKey{
+		Name:           name,
+		Prefix:         plain[:API_KEY_DISPLAY_LENGTH],
+		KeyHash:        hashToken(plain),
+		Scopes:         scopes,
+		StudyProgramID: input.StudyProgramID,
+		ExpiresAt:      input.ExpiresAt,
+		CreatedByID:    input.CreatedByID,
+	}
+	if err := s.repo.Create(key); err != nil {
+		return nil, err
+	}
+	return &IssuedAPIKey{APIKey: key, Key: plain}, nil
+}
+
+// UpdateAPIKey mengganti nama, scope, atau masa berlaku. Prodi tidak bisa diubah; buat key baru
+// jika sistem lain perlu membaca prodi berbeda.
+func (s *apiKeyService) UpdateAPIKey(id uuid.UUID, input UpdateAPIKeyInput) (*models.APIKey, error) {
+	key, err := s.GetAPIKey(id)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, ErrAPIKeyRevoked
+	}
+
+	if input.Name != nil {
+		if key.Name, err = validateAPIKeyName(*input.Name); err != nil {
+			return nil, err
+		}
+	}
+	if input.Scopes != nil {
+		if key.Scopes, err = validateAPIKeyScopes(input.Scopes); err != nil {
+			return nil, err
+		}
+	}
+	if input.ExpiresAt != nil {
+		if !input.ExpiresAt.After(time.Now()) {
+			return nil, ErrInvalidAPIKeyTTL
+		}
+		key.ExpiresAt = input.ExpiresAt
+	}
+
+	if err := s.repo.Update(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (s *apiKeyService) RevokeAPIKey(id uuid.UUID) error {
+	key, err := s.GetAPIKey(id)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return ErrAPIKeyRevoked
+	}
+	if err := s.repo.Revoke(id); err != nil {
+		return ErrAPIKeyRevoked
+	}
+	return nil
+}
+
+// Authenticate mencocokkan key dengan hash yang tersimpan dan mencatat pemakaian terakhirnya
+func (s *apiKeyService) Authenticate(plain, ipAddress string) (*models.APIKey, error) {
+	if !strings.HasPrefix(plain, API_KEY_PREFIX) {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := s.repo.FindByHash(hashToken(plain))
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !key.ExpiresAt.After(now)) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// kegagalan mencatat pemakaian tidak boleh menggagalkan request sistem lain
+	if err := s.repo.TouchLastUsed(key.ID, ipAddress, now, now.Add(-API_KEY_LAST_USED_INTERVAL)); err != nil {
+		log.Printf("failed to record API key usage %s: %v", key.ID, err)
+	}
+	return key, nil
+}
+
+func validateAPIKeyName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("name is required")
+	}
+	if utf8.RuneCountInString(name) > MAX_API_KEY_NAME_LENGTH {
+		return "", fmt.Errorf("name must be at most %d characters", MAX_API_KEY_NAME_LENGTH)
+	}
+	return name, nil
+}
+
+// validateAPIKeyScopes memastikan scope tidak kosong dan hanya berisi permission read-only di API_KEY_SCOPES
+func validateAPIKeyScopes(scopes []string) (models.StringList, error) {
+	if len(scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	result := make(models.StringList, 0, len(scopes))
+	for _, name := range scopes {
+		if !containsString(API_KEY_SCOPES, name) {
+			return nil, fmt.Errorf("scope %s is not allowed for API keys (allowed: %s)", name, strings.Join(API_KEY_SCOPES, ", "))
+		}
+		if !containsString(result, name) {
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
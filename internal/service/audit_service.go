@@ -0,0 +1,101 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	AUDIT_DEFAULT_LIMIT = 50
+	AUDIT_MAX_LIMIT     = 500
+)
+
+// AuditEntry adalah data satu aksi yang akan dicatat ke audit log.
+// Before / After boleh berupa struct apa pun yang bisa di-marshal ke JSON.
+type AuditEntry struct {
+	ActorID    *uuid.UUID
+	ActorRole  string
+	Action     string
+	Entity     string
+	EntityID   string
+	Before     interface{}
+	After      interface{}
+	Method     string
+	Path       string
+	StatusCode int
+	IPAddress  string
+	UserAgent  string
+	RequestID  string
+}
+
+type AuditService interface {
+	Record(entry AuditEntry) error
+	ListAuditLogs(filter repository.AuditFilter) ([]models.AuditLog, int64, error)
+}
+
+type auditService struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditService(repo repository.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) Record(entry AuditEntry) error {
+	before, err := toJSONB(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := toJSONB(entry.After)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Create(&models.AuditLog{
+		ActorID:    entry.ActorID,
+		ActorRole:  entry.ActorRole,
+		Action:     entry.Action,
+		Entity:     entry.Entity,
+		EntityID:   entry.EntityID,
+		Before:     before,
+		After:      after,
+		Method:     entry.Method,
+		Path:       entry.Path,
+		StatusCode: entry.StatusCode,
+		IPAddress:  entry.IPAddress,
+		UserAgent:  entry.UserAgent,
+		RequestID:  entry.RequestID,
+		CreatedAt:  time.Now(),
+	})
+}
+
+func (s *auditService) ListAuditLogs(filter repository.AuditFilter) ([]models.AuditLog, int64, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = AUDIT_DEFAULT_LIMIT
+	}
+	if filter.Limit > AUDIT_MAX_LIMIT {
+		filter.Limit = AUDIT_MAX_LIMIT
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.FindAll(filter)
+}
+
+func toJSONB(value interface{}) (models.JSONB, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case json.RawMessage:
+		return models.JSONB(v), nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return models.JSONB(data), nil
+}
@@ -1,75 +1,721 @@
 package service
 
 import (
+	"course-planner-api/config"
+	"course-planner-api/internal/jwtauth"
+	"course-planner-api/internal/mailer"
 	"course-planner-api/internal/models"
+	"course-planner-api/internal/oidc"
 	"course-planner-api/internal/repository"
 	"errors"
-	"os"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+const (
+	ROLE_ADMIN     = "admin"
+	ROLE_DOSEN     = "dosen"
+	ROLE_MAHASISWA = "mahasiswa"
+
+	MIN_PASSWORD_LENGTH = 8
+
+	// MAX_ACTIVATION_EMAILS_PER_HOUR membatasi email aktivasi registrasi mandiri per akun
+	MAX_ACTIVATION_EMAILS_PER_HOUR = 3
+
+	// LOGIN_FREE_ATTEMPTS adalah jumlah gagal login per akun sebelum percobaan berikutnya diberi jeda
+	LOGIN_FREE_ATTEMPTS = 2
+	// MAX_LOGIN_DELAY adalah jeda maksimum antar percobaan login sebelum akun dikunci
+	MAX_LOGIN_DELAY = 30 * time.Second
+	// DUMMY_PASSWORD_HASH (bcrypt, cost default) dibandingkan saat email tidak terdaftar atau akun belum punya
+	// password, agar waktu respons login sama dengan akun yang terdaftar
+	DUMMY_PASSWORD_HASH = "$2a$10$zDDaUILybFGHkw7DC7CVKOUoPXfL7UeOv0m4D3jlibpww2WUiHvU."
+
+	LOGIN_HISTORY_DEFAULT_LIMIT = 20
+	LOGIN_HISTORY_MAX_LIMIT     = 100
+
+	// TWO_FACTOR_CHALLENGE_TTL adalah batas waktu memasukkan kode 2FA setelah password benar
+	TWO_FACTOR_CHALLENGE_TTL = 5 * time.Minute
+	// MAX_TWO_FACTOR_ATTEMPTS adalah jumlah kode salah per challenge sebelum harus login ulang
+	MAX_TWO_FACTOR_ATTEMPTS = 5
+
+	// Metode login di riwayat login
+	LOGIN_METHOD_PASSWORD = "password"
+	LOGIN_METHOD_OIDC     = "oidc"
+
+	// Alasan gagal login di riwayat login
+	LOGIN_FAILURE_INVALID_CREDENTIALS = "invalid_credentials"
+	LOGIN_FAILURE_ACCOUNT_LOCKED      = "account_locked"
+	LOGIN_FAILURE_THROTTLED           = "throttled"
+	LOGIN_FAILURE_ACCOUNT_DEACTIVATED = "account_deactivated"
+	LOGIN_FAILURE_INVALID_TWO_FACTOR  = "invalid_two_factor"
+	LOGIN_FAILURE_PASSWORD_DISABLED   = "password_login_disabled"
+	LOGIN_FAILURE_OIDC_NOT_LINKED     = "oidc_account_not_found"
+	LOGIN_FAILURE_OIDC_CONFLICT       = "oidc_account_conflict"
+)
+
+var (
+	// ErrRegistrationDisabled dikembalikan jika REGISTRATION_MODE=disabled
+	ErrRegistrationDisabled = errors.New("self-registration is disabled, please ask an admin for an invitation")
+	ErrPasswordTooShort     = fmt.Errorf("password must be at least %d characters", MIN_PASSWORD_LENGTH)
+	// ErrInvalidRefreshToken dikembalikan untuk refresh token yang tidak dikenal, kedaluwarsa, dicabut,
+	// atau sudah pernah dirotasi (pemakaian ulang juga mencabut seluruh sesinya)
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid, expired, or revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrAccountDeactivated  = errors.New("account is deactivated")
+	// ErrInvalidLoginChallenge dikembalikan untuk challenge 2FA yang tidak dikenal, kedaluwarsa atau sudah dipakai
+	ErrInvalidLoginChallenge = errors.New("login challenge is invalid or expired, please login again")
+	ErrPasswordLoginDisabled = errors.New("password login is disabled for this account, please sign in with SSO")
+)
+
+// LoginThrottledError dikembalikan jika percobaan login ditolak karena terlalu banyak gagal login,
+// baik dari akun yang sama maupun dari IP yang sama
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool // akun dikunci sementara, bukan sekadar diberi jeda
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return "account is temporarily locked due to too many failed login attempts"
+	}
+	return "too many failed login attempts, please try again later"
+}
+
+// RegisterInput dipakai registrasi mandiri mahasiswa: NIM + email harus cocok dengan data yang diimport admin
+type RegisterInput struct {
+	NIM   string
+	Email string
+}
+
+// ClientInfo adalah informasi perangkat yang disimpan di sesi login
+type ClientInfo struct {
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+}
+
+// TokenPair adalah access token berumur pendek beserta refresh token sesi (hanya ditampilkan sekali)
+type TokenPair struct {
+	AccessToken  string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresIn    int       `json:"expires_in"` // detik
+	SessionID    uuid.UUID `json:"session_id"`
+}
+
+// TwoFactorChallenge dikembalikan Login untuk akun dengan 2FA; challenge_token ditukar dengan token
+// lewat VerifyTwoFactor bersama kode TOTP atau recovery code
+type TwoFactorChallenge struct {
+	Token     string    `json:"challenge_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Methods   []string  `json:"methods"`
+}
+
+// LoginResult berisi Tokens jika login selesai, atau Challenge jika masih butuh kode 2FA
+type LoginResult struct {
+	Tokens    *TokenPair
+	User      *models.User
+	Challenge *TwoFactorChallenge
+}
+
 type AuthService interface {
-	Register(name, email, password string) (*models.User, error)
-	Login(email, password string) (string, *models.User, error)
+	Register(input RegisterInput) error
+	Login(email, password string, client ClientInfo) (*LoginResult, error)
+	VerifyTwoFactor(challengeToken, code string) (*TokenPair, *models.User, error)
+	Refresh(refreshToken string) (*TokenPair, error)
+	Logout(sessionID uuid.UUID) error
+	IsSessionActive(sessionID uuid.UUID) (bool, error)
+	ListSessions(userID uuid.UUID) ([]models.UserSession, error)
+	RevokeSession(userID, sessionID uuid.UUID) error
+	RevokeOtherSessions(userID, currentSessionID uuid.UUID) (int64, error)
+	LoginHistory(userID uuid.UUID, limit int) ([]models.LoginAttempt, error)
+	GetAccount(userID uuid.UUID) (*models.User, error)
+	UnlockAccount(userID uuid.UUID) error
+	StartOIDCLogin() (*OIDCAuthorization, error)
+	LoginWithOIDC(code, state string, client ClientInfo) (*LoginResult, error)
+	SetPasswordLogin(userID uuid.UUID, enabled bool) (*models.User, error)
 }
 
 type authService struct {
-	userRepo repository.UserRepository
+	userRepo         repository.UserRepository
+	invitationRepo   repository.InvitationRepository
+	sessionRepo      repository.SessionRepository
+	loginAttemptRepo repository.LoginAttemptRepository
+	twoFactorRepo    repository.TwoFactorRepository
+	roleService      RoleService
+	twoFactorService TwoFactorService
+	oidcRepo         repository.OIDCRepository
+	oidcProvider     *oidc.Provider // nil jika login OIDC nonaktif
+	mailer           mailer.Mailer
+	keys             *jwtauth.KeySet
+	cfg              config.AuthConfig
+	oidcCfg          config.OIDCConfig
 }
 
-func NewAuthService(userRepo repository.UserRepository) AuthService {
-	return &authService{userRepo: userRepo}
+func NewAuthService(userRepo repository.UserRepository, invitationRepo repository.InvitationRepository, sessionRepo repository.SessionRepository, loginAttemptRepo repository.LoginAttemptRepository, twoFactorRepo repository.TwoFactorRepository, oidcRepo repository.OIDCRepository, roleService RoleService, twoFactorService TwoFactorService, oidcProvider *oidc.Provider, m mailer.Mailer, keys *jwtauth.KeySet, cfg config.AuthConfig, oidcCfg config.OIDCConfig) AuthService {
+	return &authService{userRepo: userRepo, invitationRepo: invitationRepo, sessionRepo: sessionRepo, loginAttemptRepo: loginAttemptRepo, twoFactorRepo: twoFactorRepo, oidcRepo: oidcRepo, roleService: roleService, twoFactorService: twoFactorService, oidcProvider: oidcProvider, mailer: m, keys: keys, cfg: cfg, oidcCfg: oidcCfg}
 }
 
-func (s *authService) Register(name, email, password string) (*models.User, error) {
+// hashInitialPassword meng-hash password awal akun; password kosong berarti akun dibuat pending
+func hashInitialPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	if len(password) < MIN_PASSWORD_LENGTH {
+		return "", ErrPasswordTooShort
+	}
 	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+// Register mengirim link aktivasi sekali pakai ke email mahasiswa pending (hasil import / dibuat admin
+// tanpa password). Password diatur lewat POST /api/auth/activate dengan token dari email, sehingga
+// NIM dan email saja tidak cukup untuk mengambil alih akun. Role tidak bisa dipilih sendiri.
+// Selalu berhasil jika data tidak cocok atau akun sudah aktif agar tidak bisa dipakai menebak akun.
+func (s *authService) Register(input RegisterInput) error {
+	if s.cfg.RegistrationMode != config.REGISTRATION_MODE_MAHASISWA {
+		return ErrRegistrationDisabled
+	}
+	nim := strings.TrimSpace(input.NIM)
+	email := strings.TrimSpace(input.Email)
+	if nim == "" || email == "" {
+		return errors.New("nim and email are required")
+	}
+
+	user, err := s.userRepo.FindMahasiswaByNIM(nim)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if !strings.EqualFold(user.Email, email) || !user.IsPending {
+		return nil
+	}
+
+	count, err := s.invitationRepo.CountSince(user.ID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if count >= MAX_ACTIVATION_EMAILS_PER_HOUR {
+		return nil
+	}
+
+	token, tokenHash, err := newOneTimeToken()
+	if err != nil {
+		return err
+	}
+	invitation := &models.UserInvitation{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(s.cfg.InvitationTTL),
+	}
+	if err := s.invitationRepo.Create(invitation); err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Aktivasi akun Course Planner",
+		Body:    s.activationEmailBody(user, token),
+	}
+	// dikirim di background agar waktu respons tidak membedakan data yang cocok atau tidak
+	go func() {
+		if err := s.mailer.Send(msg); err != nil {
+			log.Printf("failed to send activation email to user %s: %v", user.ID, err)
+		}
+	}()
+	return nil
+}
+
+func (s *authService) activationEmailBody(user *models.User, token string) string {
+	link := s.cfg.ActivationURL + "?token=" + url.QueryEscape(token)
+	return fmt.Sprintf(`Halo %s,
+
+Kami menerima permintaan aktivasi akun Course Planner untuk NIM %s.
+Buka tautan berikut untuk membuat password (berlaku %d jam, hanya bisa dipakai sekali):
+
+%s
+
+Abaikan email ini jika Anda tidak meminta aktivasi akun.
+`, user.Name, user.NIM, int(s.cfg.InvitationTTL.Hours()), link)
+}
+
+// Login membuat sesi baru untuk perangkat client dan mengembalikan access + refresh token.
+// Akun dengan 2FA aktif mendapat challenge dan token baru diterbitkan oleh VerifyTwoFactor.
+// Setiap percobaan dicatat di riwayat login; gagal login berulang per akun / per IP
+// diberi jeda yang makin panjang lalu akun dikunci sementara.
+func (s *authService) Login(email, password string, client ClientInfo) (*LoginResult, error) {
+	now := time.Now()
+	attempt := &models.LoginAttempt{
+		Email:     truncate(email, 100),
+		IPAddress: truncate(client.IPAddress, 45),
+		UserAgent: truncate(client.UserAgent, 255),
+		Method:    LOGIN_METHOD_PASSWORD,
+	}
+
+	if throttled, err := s.ipThrottle(client.IPAddress, now); err != nil {
+		return nil, err
+	} else if throttled != nil {
+		return nil, s.failLogin(attempt, LOGIN_FAILURE_THROTTLED, throttled)
+	}
+
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		// tetap menjalankan bcrypt agar waktu respons tidak membocorkan email mana yang terdaftar
+		_ = bcrypt.CompareHashAndPassword([]byte(DUMMY_PASSWORD_HASH), []byte(password))
+		return nil, s.failLogin(attempt, LOGIN_FAILURE_INVALID_CREDENTIALS, ErrInvalidCredentials)
+	}
+	attempt.UserID = &user.ID
+
+	if throttled := accountThrottle(s.cfg, user, now); throttled != nil {
+		reason := LOGIN_FAILURE_THROTTLED
+		if throttled.Locked {
+			reason = LOGIN_FAILURE_ACCOUNT_LOCKED
+		}
+		return nil, s.failLogin(attempt, reason, throttled)
+	}
+
+	passwordHash := user.Password
+	if passwordHash == "" {
+		// akun pending belum punya password: bandingkan dengan hash dummy agar waktunya sama
+		passwordHash = DUMMY_PASSWORD_HASH
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)); err != nil || user.Password == "" {
+		return nil, s.recordFailure(user, attempt, LOGIN_FAILURE_INVALID_CREDENTIALS, ErrInvalidCredentials, now)
+	}
+
+	// Akun yang hanya boleh login lewat SSO; dicek setelah password benar agar tidak membocorkan status akun
+	if user.PasswordLoginDisabled {
+		return nil, s.failLogin(attempt, LOGIN_FAILURE_PASSWORD_DISABLED, ErrPasswordLoginDisabled)
+	}
+
+	if !user.IsActive {
+		return nil, s.failLogin(attempt, LOGIN_FAILURE_ACCOUNT_DEACTIVATED, ErrAccountDeactivated)
+	}
+
+	return s.finishLogin(user, client, attempt, now)
+}
+
+// finishLogin adalah langkah terakhir login (password atau OIDC): akun dengan 2FA aktif mendapat
+// challenge, selain itu sesi dan token langsung dibuat
+func (s *authService) finishLogin(user *models.User, client ClientInfo, attempt *models.LoginAttempt, now time.Time) (*LoginResult, error) {
+	enabled, err := s.twoFactorService.IsEnabled(user.ID)
 	if err != nil {
 		return nil, err
 	}
+	if enabled {
+		challenge, err := s.createChallenge(user, client, attempt.Method, now)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{User: user, Challenge: challenge}, nil
+	}
 
-	user := &models.User{
-		Name:     name,
-		Email:    email,
-		Password: string(hashed),
+	tokens, err := s.completeLogin(user, client, attempt, now)
+	if err != nil {
+		return nil, err
 	}
+	return &LoginResult{Tokens: tokens, User: user}, nil
+}
 
-	if err := s.userRepo.Create(user); err != nil {
+// VerifyTwoFactor menyelesaikan login 2FA: challenge dari Login ditukar dengan token jika kode TOTP
+// atau recovery code valid. Kode salah dihitung sebagai gagal login (ikut memicu jeda / kunci akun).
+func (s *authService) VerifyTwoFactor(challengeToken, code string) (*TokenPair, *models.User, error) {
+	now := time.Now()
+	challenge, err := s.twoFactorRepo.FindChallengeByHash(hashToken(challengeToken))
+	if err != nil || now.After(challenge.ExpiresAt) || challenge.Attempts >= MAX_TWO_FACTOR_ATTEMPTS {
+		return nil, nil, ErrInvalidLoginChallenge
+	}
+
+	user, err := s.userRepo.FindByID(challenge.UserID)
+	if err != nil {
+		return nil, nil, ErrInvalidLoginChallenge
+	}
+	client := ClientInfo{
+		DeviceName: challenge.DeviceName,
+		UserAgent:  challenge.UserAgent,
+		IPAddress:  challenge.IPAddress,
+	}
+	attempt := &models.LoginAttempt{
+		UserID:    &user.ID,
+		Email:     truncate(user.Email, 100),
+		IPAddress: challenge.IPAddress,
+		UserAgent: challenge.UserAgent,
+		Method:    challenge.Method,
+	}
+
+	if throttled := accountThrottle(s.cfg, user, now); throttled != nil {
+		reason := LOGIN_FAILURE_THROTTLED
+		if throttled.Locked {
+			reason = LOGIN_FAILURE_ACCOUNT_LOCKED
+			if _, err := s.twoFactorRepo.DeleteChallenge(challenge.ID); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, s.failLogin(attempt, reason, throttled)
+	}
+	if !user.IsActive {
+		if _, err := s.twoFactorRepo.DeleteChallenge(challenge.ID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, s.failLogin(attempt, LOGIN_FAILURE_ACCOUNT_DEACTIVATED, ErrAccountDeactivated)
+	}
+
+	ok, err := s.twoFactorService.Verify(user.ID, code)
+	if err != nil {
+		if errors.Is(err, ErrTwoFactorNotEnabled) {
+			// 2FA dinonaktifkan admin setelah challenge dibuat
+			return nil, nil, ErrInvalidLoginChallenge
+		}
+		return nil, nil, err
+	}
+	if !ok {
+		if err := s.twoFactorRepo.IncrementChallengeAttempts(challenge.ID); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, s.recordFailure(user, attempt, LOGIN_FAILURE_INVALID_TWO_FACTOR, ErrInvalidTwoFactorCode, now)
+	}
+
+	// challenge hanya sekali pakai: request bersamaan dengan challenge yang sama hanya satu yang berhasil
+	if deleted, err := s.twoFactorRepo.DeleteChallenge(challenge.ID); err != nil {
+		return nil, nil, err
+	} else if !deleted {
+		return nil, nil, ErrInvalidLoginChallenge
+	}
+
+	tokens, err := s.completeLogin(user, client, attempt, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, user, nil
+}
+
+// createChallenge membuat challenge 2FA berumur pendek setelah langkah pertama login (password / OIDC) berhasil
+func (s *authService) createChallenge(user *models.User, client ClientInfo, method string, now time.Time) (*TwoFactorChallenge, error) {
+	token, tokenHash, err := newOneTimeToken()
+	if err != nil {
 		return nil, err
 	}
 
+	challenge := &models.LoginChallenge{
+		UserID:     user.ID,
+		TokenHash:  tokenHash,
+		DeviceName: truncate(client.DeviceName, 100),
+		UserAgent:  truncate(client.UserAgent, 255),
+		IPAddress:  truncate(client.IPAddress, 45),
+		Method:     method,
+		ExpiresAt:  now.Add(TWO_FACTOR_CHALLENGE_TTL),
+	}
+	if err := s.twoFactorRepo.CreateChallenge(challenge); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorChallenge{
+		Token:     token,
+		ExpiresAt: challenge.ExpiresAt,
+		Methods:   []string{TWO_FACTOR_METHOD_TOTP, TWO_FACTOR_METHOD_RECOVERY_CODE},
+	}, nil
+}
+
+// completeLogin menghapus hitungan gagal login, membuat sesi untuk perangkat client,
+// menerbitkan token dan mencatat login berhasil
+func (s *authService) completeLogin(user *models.User, client ClientInfo, attempt *models.LoginAttempt, now time.Time) (*TokenPair, error) {
+	if user.FailedLoginCount > 0 || user.LockedUntil != nil {
+		if err := s.loginAttemptRepo.ResetFailures(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	refreshToken, refreshHash, err := newOneTimeToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session := &models.UserSession{
+		UserID:           user.ID,
+		RefreshTokenHash: refreshHash,
+		DeviceName:       truncate(client.DeviceName, 100),
+		UserAgent:        truncate(client.UserAgent, 255),
+		IPAddress:        truncate(client.IPAddress, 45),
+		LastUsedAt:       now,
+		ExpiresAt:        now.Add(s.cfg.RefreshTokenTTL),
+	}
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.issueAccessToken(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempt.Success = true
+	if err := s.loginAttemptRepo.Create(attempt); err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.cfg.AccessTokenTTL.Seconds()),
+		SessionID:    session.ID,
+	}, nil
+}
+
+// Refresh merotasi refresh token dan menerbitkan access token baru dengan role & permission terbaru.
+// Refresh token yang sudah pernah dirotasi dianggap bocor: seluruh sesinya dicabut.
+func (s *authService) Refresh(refreshToken string) (*TokenPair, error) {
+	tokenHash := hashToken(refreshToken)
+
+	session, err := s.sessionRepo.FindByRefreshTokenHash(tokenHash)
+	if err != nil {
+		if reused, err := s.sessionRepo.FindByPreviousTokenHash(tokenHash); err == nil {
+			if err := s.sessionRepo.Revoke(reused.ID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.userRepo.FindByID(session.UserID)
+	if err != nil || !user.IsActive {
+		if err := s.sessionRepo.Revoke(session.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	newToken, newHash, err := newOneTimeToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.sessionRepo.Rotate(session.ID, tokenHash, newHash, time.Now().Add(s.cfg.RefreshTokenTTL)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+
+	accessToken, err := s.issueAccessToken(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: newToken,
+		ExpiresIn:    int(s.cfg.AccessTokenTTL.Seconds()),
+		SessionID:    session.ID,
+	}, nil
+}
+
+// Logout mencabut sesi token yang sedang dipakai
+func (s *authService) Logout(sessionID uuid.UUID) error {
+	return s.sessionRepo.Revoke(sessionID)
+}
+
+func (s *authService) IsSessionActive(sessionID uuid.UUID) (bool, error) {
+	return s.sessionRepo.IsActive(sessionID)
+}
+
+func (s *authService) ListSessions(userID uuid.UUID) ([]models.UserSession, error) {
+	return s.sessionRepo.FindActiveByUser(userID)
+}
+
+// RevokeSession mencabut satu sesi milik user (misal logout dari perangkat lain)
+func (s *authService) RevokeSession(userID, sessionID uuid.UUID) error {
+	session, err := s.sessionRepo.FindByID(sessionID)
+	if err != nil || session.UserID != userID || session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+	return s.sessionRepo.Revoke(sessionID)
+}
+
+// RevokeOtherSessions mencabut semua sesi user kecuali sesi yang sedang dipakai
+func (s *authService) RevokeOtherSessions(userID, currentSessionID uuid.UUID) (int64, error) {
+	return s.sessionRepo.RevokeAllForUser(userID, &currentSessionID)
+}
+
+func (s *authService) LoginHistory(userID uuid.UUID, limit int) ([]models.LoginAttempt, error) {
+	if limit <= 0 {
+		limit = LOGIN_HISTORY_DEFAULT_LIMIT
+	}
+	if limit > LOGIN_HISTORY_MAX_LIMIT {
+		limit = LOGIN_HISTORY_MAX_LIMIT
+	}
+	return s.loginAttemptRepo.FindByUser(userID, limit)
+}
+
+func (s *authService) GetAccount(userID uuid.UUID) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
 	return user, nil
 }
 
-func (s *authService) Login(email, password string) (string, *models.User, error) {
-	user, err := s.userRepo.FindByEmail(email)
+// UnlockAccount membuka kunci akun dan menghapus hitungan gagal login (oleh admin)
+func (s *authService) UnlockAccount(userID uuid.UUID) error {
+	if err := s.loginAttemptRepo.ResetFailures(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("user not found")
+		}
+		return err
+	}
+	return nil
+}
+
+// recordFailure menambah hitungan gagal login akun (password atau kode 2FA salah) lalu mencatat
+// percobaannya; mengembalikan error akun dikunci jika hitungan mencapai LoginMaxFailures
+func (s *authService) recordFailure(user *models.User, attempt *models.LoginAttempt, reason string, loginErr error, now time.Time) error {
+	locked, err := recordAccountFailure(s.loginAttemptRepo, s.cfg, user.ID, now)
 	if err != nil {
-		return "", nil, errors.New("invalid email or password")
+		return err
+	}
+	if locked != nil {
+		return s.failLogin(attempt, reason, locked)
 	}
+	return s.failLogin(attempt, reason, loginErr)
+}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", nil, errors.New("invalid email or password")
+// recordAccountFailure menambah hitungan gagal login akun; mengembalikan error akun dikunci jika
+// hitungan mencapai LoginMaxFailures. Dipakai juga oleh 2FA service untuk kode / password salah.
+func recordAccountFailure(repo repository.LoginAttemptRepository, cfg config.AuthConfig, userID uuid.UUID, now time.Time) (*LoginThrottledError, error) {
+	updated, err := repo.RecordFailure(userID, now.Add(-cfg.LoginFailureWindow), cfg.LoginMaxFailures, now.Add(cfg.LoginLockoutDuration))
+	if err != nil {
+		return nil, err
 	}
+	if updated.LockedUntil != nil && updated.LockedUntil.After(now) {
+		return &LoginThrottledError{RetryAfter: updated.LockedUntil.Sub(now), Locked: true}, nil
+	}
+	return nil, nil
+}
 
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return "", nil, errors.New("JWT_SECRET is not set")
+// failLogin mencatat percobaan login yang gagal lalu mengembalikan loginErr
+func (s *authService) failLogin(attempt *models.LoginAttempt, reason string, loginErr error) error {
+	attempt.Success = false
+	attempt.FailureReason = reason
+	if err := s.loginAttemptRepo.Create(attempt); err != nil {
+		return err
 	}
+	return loginErr
+}
 
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"email":   user.Email,
-		"role":    user.Role,
-		"exp":     time.Now().Add(24 * time.Hour).Unix(),
+// ipThrottle memberi jeda bertingkat untuk IP yang gagal login lebih dari LoginIPMaxFailures
+// kali dalam LoginFailureWindow (misal tebak password ke banyak akun)
+func (s *authService) ipThrottle(ipAddress string, now time.Time) (*LoginThrottledError, error) {
+	failures, last, err := s.loginAttemptRepo.CountIPFailuresSince(ipAddress, LOGIN_FAILURE_INVALID_CREDENTIALS, now.Add(-s.cfg.LoginFailureWindow))
+	if err != nil || last == nil {
+		return nil, err
+	}
+
+	delay := progressiveDelay(int(failures)-s.cfg.LoginIPMaxFailures, s.cfg.LoginFailureWindow)
+	if retryAt := last.Add(delay); delay > 0 && now.Before(retryAt) {
+		return &LoginThrottledError{RetryAfter: retryAt.Sub(now)}, nil
+	}
+	return nil, nil
+}
+
+// accountThrottle menolak login ke akun yang sedang dikunci, atau yang baru saja gagal login
+// beberapa kali dan jedanya belum lewat
+func accountThrottle(cfg config.AuthConfig, user *models.User, now time.Time) *LoginThrottledError {
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		return &LoginThrottledError{RetryAfter: user.LockedUntil.Sub(now), Locked: true}
+	}
+	if user.LastFailedLoginAt == nil || user.LastFailedLoginAt.Before(now.Add(-cfg.LoginFailureWindow)) {
+		return nil
+	}
+
+	delay := progressiveDelay(user.FailedLoginCount-LOGIN_FREE_ATTEMPTS, MAX_LOGIN_DELAY)
+	if retryAt := user.LastFailedLoginAt.Add(delay); delay > 0 && now.Before(retryAt) {
+		return &LoginThrottledError{RetryAfter: retryAt.Sub(now)}
 	}
+	return nil
+}
+
+// progressiveDelay menghitung jeda 1s, 2s, 4s, ... untuk excess gagal login di atas batas, maksimal max
+func progressiveDelay(excess int, max time.Duration) time.Duration {
+	if excess < 0 {
+		return 0
+	}
+	if excess > 30 {
+		return max
+	}
+	delay := time.Second << excess
+	if delay > max {
+		return max
+	}
+	return delay
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString([]byte(secret))
+// issueAccessToken membuat access token berumur pendek yang terikat ke sesi (claim sid)
+func (s *authService) issueAccessToken(user *models.User, sessionID uuid.UUID) (string, error) {
+	roles, permissions, err := s.roleService.PermissionsForUser(user)
 	if err != nil {
-		return "", nil, err
+		return "", err
 	}
 
-	return signed, user, nil
+	claims := jwt.MapClaims{
+		"jti":         uuid.NewString(),
+		"sid":         sessionID.String(),
+		"user_id":     user.ID,
+		"email":       user.Email,
+		"role":        user.Role,
+		"roles":       roles,
+		"permissions": permissions,
+		"exp":         time.Now().Add(s.cfg.AccessTokenTTL).Unix(),
+	}
+	// Akun yang wajib ganti password hanya boleh mengakses endpoint ganti password (lihat router)
+	if user.MustChangePassword {
+		claims["must_change_password"] = true
+	}
+	// Akun dengan role yang mewajibkan 2FA tetapi belum mendaftar hanya boleh mengakses endpoint 2FA
+	if required, err := s.twoFactorEnrollmentRequired(user); err != nil {
+		return "", err
+	} else if required {
+		claims["two_factor_enrollment_required"] = true
+	}
+	// User yang terikat ke prodi dan memegang role non-global (admin prodi, kaprodi) hanya boleh
+	// mengelola data prodinya; permission dari role global-nya dibawa di global_permissions
+	if scoped, globalPermissions, err := s.roleService.ProgramScope(user); err != nil {
+		return "", err
+	} else if scoped {
+		claims["study_program_id"] = user.StudyProgramID.String()
+		claims["global_permissions"] = globalPermissions
+	}
+
+	return s.keys.Sign(claims)
+}
+
+func (s *authService) twoFactorEnrollmentRequired(user *models.User) (bool, error) {
+	required, err := s.roleService.RequiresTwoFactor(user)
+	if err != nil || !required {
+		return false, err
+	}
+	enabled, err := s.twoFactorService.IsEnabled(user.ID)
+	return !enabled, err
+}
+
+// truncate memotong string client (per karakter) agar muat di kolom varchar sesi
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
 }
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"course-planner-api/config"
+	"course-planner-api/internal/jwtauth"
+	"course-planner-api/internal/models"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fakeSessionRepository menyimpan sesi di memori dengan semantik yang sama seperti sessionRepository
+type fakeSessionRepository struct {
+	sessions map[uuid.UUID]*models.UserSession
+}
+
+func newFakeSessionRepository() *fakeSessionRepository {
+	return &fakeSessionRepository{sessions: make(map[uuid.UUID]*models.UserSession)}
+}
+
+func (r *fakeSessionRepository) Create(session *models.UserSession) error {
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *fakeSessionRepository) FindByID(id uuid.UUID) (*models.UserSession, error) {
+	if session, ok := r.sessions[id]; ok {
+		found := *session
+		return &found, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSessionRepository) find(match func(*models.UserSession) bool) (*models.UserSession, error) {
+	for _, session := range r.sessions {
+		if match(session) {
+			found := *session
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeSessionRepository) FindByRefreshTokenHash(tokenHash string) (*models.UserSession, error) {
+	return r.find(func(s *models.UserSession) bool { return s.RefreshTokenHash == tokenHash })
+}
+
+func (r *fakeSessionRepository) FindByPreviousTokenHash(tokenHash string) (*models.UserSession, error) {
+	return r.find(func(s *models.UserSession) bool { return s.PreviousTokenHash == tokenHash })
+}
+
+func (r *fakeSessionRepository) FindActiveByUser(userID uuid.UUID) ([]models.UserSession, error) {
+	sessions := make([]models.UserSession, 0)
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil && session.ExpiresAt.After(time.Now()) {
+			sessions = append(sessions, *session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *fakeSessionRepository) Rotate(id uuid.UUID, oldHash, newHash string, expiresAt time.Time) error {
+	session, ok := r.sessions[id]
+	if !ok || session.RefreshTokenHash != oldHash || session.RevokedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	session.PreviousTokenHash = oldHash
+	session.RefreshTokenHash = newHash
+	session.LastUsedAt = time.Now()
+	session.ExpiresAt = expiresAt
+	return nil
+}
+
+func (r *fakeSessionRepository) IsActive(id uuid.UUID) (bool, error) {
+	session, ok := r.sessions[id]
+	return ok && session.RevokedAt == nil && session.ExpiresAt.After(time.Now()), nil
+}
+
+func (r *fakeSessionRepository) Revoke(id uuid.UUID) error {
+	if session, ok := r.sessions[id]; ok && session.RevokedAt == nil {
+		now := time.Now()
+		session.RevokedAt = &now
+	}
+	return nil
+}
+
+func (r *fakeSessionRepository) RevokeAllForUser(userID uuid.UUID, exceptID *uuid.UUID) (int64, error) {
+	var count int64
+	for id, session := range r.sessions {
+		if session.UserID != userID || session.RevokedAt != nil || (exceptID != nil && id == *exceptID) {
+			continue
+		}
+		now := time.Now()
+		session.RevokedAt = &now
+		count++
+	}
+	return count, nil
+}
+
+type fakeUserRepository struct {
+	users map[uuid.UUID]*models.User
+}
+
+func (r *fakeUserRepository) Create(user *models.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *fakeUserRepository) FindByEmail(email string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) FindByID(id uuid.UUID) (*models.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepository) FindMahasiswaByNIM(nim string) (*models.User, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeRoleService hanya mengimplementasikan method yang dipakai saat menerbitkan access token
+type fakeRoleService struct {
+	RoleService
+}
+
+func (fakeRoleService) PermissionsForUser(user *models.User) ([]string, []string, error) {
+	return []string{user.Role}, EffectivePermissions(user.Role, nil), nil
+}
+
+func (fakeRoleService) ProgramScope(user *models.User) (bool, []string, error) {
+	return false, nil, nil
+}
+
+func (fakeRoleService) RequiresTwoFactor(user *models.User) (bool, error) {
+	return false, nil
+}
+
+// newTestKeySet membuat key set Ed25519 sementara untuk menandatangani access token
+func newTestKeySet(t *testing.T) *jwtauth.KeySet {
+	t.Helper()
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	dir := t.TempDir()
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, "test.pem"), data, 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+
+	keys, err := jwtauth.LoadKeySet(dir, "", "course-planner-api")
+	if err != nil {
+		t.Fatalf("load key set: %v", err)
+	}
+	return keys
+}
+
+type refreshFixture struct {
+	service  AuthService
+	sessions *fakeSessionRepository
+	user     *models.User
+}
+
+func newRefreshFixture(t *testing.T) *refreshFixture {
+	t.Helper()
+
+	user := &models.User{ID: uuid.New(), Email: "dosen@example.ac.id", Role: ROLE_DOSEN, IsActive: true}
+	users := &fakeUserRepository{users: map[uuid.UUID]*models.User{user.ID: user}}
+	sessions := newFakeSessionRepository()
+	cfg := config.AuthConfig{AccessTokenTTL: 15 * time.Minute, RefreshTokenTTL: 24 * time.Hour}
+
+	service := NewAuthService(users, nil, sessions, nil, nil, nil, fakeRoleService{}, nil, nil, nil, newTestKeySet(t), cfg, config.OIDCConfig{})
+	return &refreshFixture{service: service, sessions: sessions, user: user}
+}
+
+// newSession membuat sesi aktif dan mengembalikan refresh token mentahnya
+func (f *refreshFixture) newSession(t *testing.T, expiresAt time.Time) (uuid.UUID, string) {
+	t.Helper()
+
+	token, tokenHash, err := newOneTimeToken()
+	if err != nil {
+		t.Fatalf("new token: %v", err)
+	}
+	session := &models.UserSession{UserID: f.user.ID, RefreshTokenHash: tokenHash, LastUsedAt: time.Now(), ExpiresAt: expiresAt}
+	if err := f.sessions.Create(session); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	return session.ID, token
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	f := newRefreshFixture(t)
+	sessionID, token := f.newSession(t, time.Now().Add(time.Hour))
+
+	pair, err := f.service.Refresh(token)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if pair.SessionID != sessionID {
+		t.Errorf("session ID = %s, want %s", pair.SessionID, sessionID)
+	}
+	if pair.RefreshToken == "" || pair.RefreshToken == token {
+		t.Errorf("refresh token was not rotated")
+	}
+	if pair.AccessToken == "" {
+		t.Errorf("access token is empty")
+	}
+
+	session := f.sessions.sessions[sessionID]
+	if session.RefreshTokenHash != hashToken(pair.RefreshToken) || session.PreviousTokenHash != hashToken(token) {
+		t.Errorf("session hashes were not rotated")
+	}
+
+	// token baru tetap bisa dipakai untuk rotasi berikutnya
+	if _, err := f.service.Refresh(pair.RefreshToken); err != nil {
+		t.Errorf("Refresh() with rotated token error = %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	tests := []struct {
+		name string
+		// retry adalah token yang dipakai setelah reuse terdeteksi: "old" atau "new"
+		retry string
+	}{
+		{"stolen old token is replayed again", "old"},
+		{"legitimate new token is used after the replay", "new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRefreshFixture(t)
+			sessionID, oldToken := f.newSession(t, time.Now().Add(time.Hour))
+			otherID, _ := f.newSession(t, time.Now().Add(time.Hour))
+
+			pair, err := f.service.Refresh(oldToken)
+			if err != nil {
+				t.Fatalf("Refresh() error = %v", err)
+			}
+
+			// token lama dipakai lagi: tandanya bocor, seluruh sesi dicabut
+			if _, err := f.service.Refresh(oldToken); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Fatalf("Refresh() with reused token error = %v, want ErrInvalidRefreshToken", err)
+			}
+			if f.sessions.sessions[sessionID].RevokedAt == nil {
+				t.Fatalf("session was not revoked after refresh token reuse")
+			}
+			if f.sessions.sessions[otherID].RevokedAt != nil {
+				t.Errorf("other session of the same user was revoked")
+			}
+
+			retry := oldToken
+			if tt.retry == "new" {
+				retry = pair.RefreshToken
+			}
+			if _, err := f.service.Refresh(retry); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Errorf("Refresh() after revocation error = %v, want ErrInvalidRefreshToken", err)
+			}
+		})
+	}
+}
+
+func TestRefreshRejectsInvalidSessions(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T, f *refreshFixture) (uuid.UUID, string)
+		wantRevoke bool
+	}{
+		{
+			name: "unknown token",
+			setup: func(t *testing.T, f *refreshFixture) (uuid.UUID, string) {
+				id, _ := f.newSession(t, time.Now().Add(time.Hour))
+				return id, "not-a-known-token"
+			},
+		},
+		{
+			name: "expired session",
+			setup: func(t *testing.T, f *refreshFixture) (uuid.UUID, string) {
+				return f.newSession(t, time.Now().Add(-time.Minute))
+			},
+		},
+		{
+			name: "revoked session",
+			setup: func(t *testing.T, f *refreshFixture) (uuid.UUID, string) {
+				id, token := f.newSession(t, time.Now().Add(time.Hour))
+				_ = f.sessions.Revoke(id)
+				return id, token
+			},
+			wantRevoke: true,
+		},
+		{
+			name: "deactivated user",
+			setup: func(t *testing.T, f *refreshFixture) (uuid.UUID, string) {
+				f.user.IsActive = false
+				return f.newSession(t, time.Now().Add(time.Hour))
+			},
+			wantRevoke: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRefreshFixture(t)
+			sessionID, token := tt.setup(t, f)
+
+			if _, err := f.service.Refresh(token); !errors.Is(err, ErrInvalidRefreshToken) {
+				t.Fatalf("Refresh() error = %v, want ErrInvalidRefreshToken", err)
+			}
+			if revoked := f.sessions.sessions[sessionID].RevokedAt != nil; revoked != tt.wantRevoke {
+				t.Errorf("session revoked = %v, want %v", revoked, tt.wantRevoke)
+			}
+		})
+	}
+}
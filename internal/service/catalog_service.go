@@ -0,0 +1,201 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Struct catalog hanya berisi data publik; tidak ada data mahasiswa maupun kontak dosen.
+
+type CatalogStudyProgram struct {
+	Kode    string `json:"kode"`
+	Nama    string `json:"nama"`
+	Jenjang string `json:"jenjang"`
+}
+
+type CatalogPrerequisite struct {
+	ID   uuid.UUID `json:"id"`
+	Kode string    `json:"kode"`
+	Nama string    `json:"nama"`
+}
+
+type CatalogCourse struct {
+	ID            uuid.UUID             `json:"id"`
+	Kode          string                `json:"kode"`
+	Nama          string                `json:"nama"`
+	SKS           int                   `json:"sks"`
+	Deskripsi     string                `json:"deskripsi"`
+	StudyProgram  *CatalogStudyProgram  `json:"study_program"`
+	Prerequisites []CatalogPrerequisite `json:"prerequisites"`
+}
+
+type CatalogClass struct {
+	ID                uuid.UUID `json:"id"`
+	CourseKode        string    `json:"course_kode"`
+	CourseNama        string    `json:"course_nama"`
+	SKS               int       `json:"sks"`
+	NamaKelas         string    `json:"nama_kelas"`
+	Hari              string    `json:"hari"`
+	JamMulai          string    `json:"jam_mulai"`
+	JamSelesai        string    `json:"jam_selesai"`
+	Ruangan           string    `json:"ruangan"`
+	Dosen             []string  `json:"dosen"`
+	Kuota             int       `json:"kuota"`
+	SemesterPenawaran string    `json:"semester_penawaran"`
+}
+
+type CatalogCourseDetail struct {
+	CatalogCourse
+	TahunAkademik int                    `json:"tahun_akademik"`
+	Semester      string                 `json:"semester"`
+	Syllabus      *models.CourseSyllabus `json:"syllabus"` // null jika belum ada versi yang berlaku
+	Classes       []CatalogClass         `json:"classes"`
+}
+
+type CatalogService interface {
+	ListCourses(filter repository.CourseFilter, page repository.PageQuery) (*repository.Page[CatalogCourse], error)
+	GetCourse(kode string, tahunAkademik int, semester string) (*CatalogCourseDetail, error)
+	ListSchedule(filter repository.ClassFilter, page repository.PageQuery) (*repository.Page[CatalogClass], error)
+}
+
+type catalogService struct {
+	courseRepo   repository.CourseRepository
+	classRepo    repository.ClassRepository
+	syllabusRepo repository.SyllabusRepository
+}
+
+func NewCatalogService(courseRepo repository.CourseRepository, classRepo repository.ClassRepository, syllabusRepo repository.SyllabusRepository) CatalogService {
+	return &catalogService{courseRepo: courseRepo, classRepo: classRepo, syllabusRepo: syllabusRepo}
+}
+
+func (s *catalogService) ListCourses(filter repository.CourseFilter, page repository.PageQuery) (*repository.Page[CatalogCourse], error) {
+	filter.IncludeDeleted = false
+	courses, err := s.courseRepo.FindAll(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &repository.Page[CatalogCourse]{
+		Data:   make([]CatalogCourse, 0, len(courses.Data)),
+		Total:  courses.Total,
+		Limit:  courses.Limit,
+		Offset: courses.Offset,
+	}
+	for i := range courses.Data {
+		result.Data = append(result.Data, toCatalogCourse(&courses.Data[i]))
+	}
+	return result, nil
+}
+
+// GetCourse mengembalikan detail course beserta silabus yang berlaku dan seluruh kelas yang ditawarkan di semester tersebut
+func (s *catalogService) GetCourse(kode string, tahunAkademik int, semester string) (*CatalogCourseDetail, error) {
+	found, err := s.courseRepo.FindByKode(kode)
+	if err != nil {
+		return nil, err
+	}
+	course, err := s.courseRepo.FindByID(found.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	classes, err := s.classRepo.FindByCourse(course.ID, semester)
+	if err != nil {
+		return nil, err
+	}
+
+	syllabus, err := s.syllabusRepo.FindEffective(course.ID, SemesterOrder(tahunAkademik, semester))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if syllabus != nil {
+		syllabus.UpdatedByID = nil
+	}
+
+	detail := &CatalogCourseDetail{
+		CatalogCourse: toCatalogCourse(course),
+		TahunAkademik: tahunAkademik,
+		Semester:      semester,
+		Syllabus:      syllabus,
+		Classes:       make([]CatalogClass, 0, len(classes)),
+	}
+	for i := range classes {
+		detail.Classes = append(detail.Classes, toCatalogClass(&classes[i]))
+	}
+	return detail, nil
+}
+
+func (s *catalogService) ListSchedule(filter repository.ClassFilter, page repository.PageQuery) (*repository.Page[CatalogClass], error) {
+	filter.IncludeDeleted = false
+	classes, err := s.classRepo.FindAll(filter, page)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &repository.Page[CatalogClass]{
+		Data:   make([]CatalogClass, 0, len(classes.Data)),
+		Total:  classes.Total,
+		Limit:  classes.Limit,
+		Offset: classes.Offset,
+	}
+	for i := range classes.Data {
+		result.Data = append(result.Data, toCatalogClass(&classes.Data[i]))
+	}
+	return result, nil
+}
+
+func toCatalogCourse(course *models.Course) CatalogCourse {
+	result := CatalogCourse{
+		ID:            course.ID,
+		Kode:          course.Kode,
+		Nama:          course.Nama,
+		SKS:           course.SKS,
+		Deskripsi:     course.Deskripsi,
+		Prerequisites: make([]CatalogPrerequisite, 0, len(course.Prerequisites)),
+	}
+	if course.StudyProgram != nil {
+		result.StudyProgram = &CatalogStudyProgram{
+			Kode:    course.StudyProgram.Kode,
+			Nama:    course.StudyProgram.Nama,
+			Jenjang: course.StudyProgram.Jenjang,
+		}
+	}
+	for _, p := range course.Prerequisites {
+		// prasyarat yang sudah dihapus tidak ikut ter-preload
+		if p.Prerequisite.ID == uuid.Nil {
+			continue
+		}
+		result.Prerequisites = append(result.Prerequisites, CatalogPrerequisite{
+			ID:   p.Prerequisite.ID,
+			Kode: p.Prerequisite.Kode,
+			Nama: p.Prerequisite.Nama,
+		})
+	}
+	return result
+}
+
+func toCatalogClass(class *models.Class) CatalogClass {
+	dosens := effectiveClassDosens(class)
+	dosen := make([]string, 0, len(dosens))
+	for _, d := range dosens {
+		dosen = append(dosen, d.Dosen.Name)
+	}
+
+	return CatalogClass{
+		ID:                class.ID,
+		CourseKode:        class.Course.Kode,
+		CourseNama:        class.Course.Nama,
+		SKS:               class.Course.SKS,
+		NamaKelas:         class.NamaKelas,
+		Hari:              class.Hari,
+		JamMulai:          class.JamMulai.Format("15:04"),
+		JamSelesai:        class.JamSelesai.Format("15:04"),
+		Ruangan:           class.Room.Nama,
+		Dosen:             dosen,
+		Kuota:             class.Kuota,
+		SemesterPenawaran: class.SemesterPenawaran,
+	}
+}
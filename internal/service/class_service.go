@@ -4,16 +4,40 @@ import (
 	"course-planner-api/internal/models"
 	"course-planner-api/internal/repository"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-var ErrClassTimeConflict = errors.New("room is already used at the given time")
+const (
+	CLASS_DOSEN_ROLE_COORDINATOR = "coordinator"
+	CLASS_DOSEN_ROLE_MEMBER      = "member"
+
+	CLASS_CHANGE_RESCHEDULE = "reschedule"
+	CLASS_CHANGE_DELETE     = "delete"
+)
+
+var (
+	ErrClassTimeConflict  = errors.New("room is already used at the given time")
+	ErrDosenTimeConflict  = errors.New("dosen is already teaching at the given time")
+	ErrInvalidClassDosens = errors.New("invalid class dosens")
+)
+
+// ClassDosenInput adalah satu anggota tim pengajar kelas.
+// SKSShare opsional; jika kosong, sisa SKS dibagi rata ke anggota tanpa share.
+type ClassDosenInput struct {
+	DosenID  uuid.UUID
+	Role     string
+	SKSShare *float64
+}
 
 type CreateClassInput struct {
 	CourseID          uuid.UUID
 	DosenID           uuid.UUID
+	Dosens            []ClassDosenInput
 	NamaKelas         string
 	Hari              string
 	JamMulai          time.Time
@@ -26,6 +50,7 @@ type CreateClassInput struct {
 type UpdateClassInput struct {
 	CourseID          *uuid.UUID
 	DosenID           *uuid.UUID
+	Dosens            *[]ClassDosenInput
 	NamaKelas         *string
 	Hari              *string
 	JamMulai          *time.Time
@@ -38,20 +63,84 @@ type UpdateClassInput struct {
 type ClassService interface {
 	CreateClass(input CreateClassInput) (*models.Class, error)
 	GetClass(id uuid.UUID) (*models.Class, error)
-	ListClasses() ([]models.Class, error)
+	ListClasses(filter repository.ClassFilter, page repository.PageQuery) (*repository.Page[models.Class], error)
 	UpdateClass(id uuid.UUID, input UpdateClassInput) (*models.Class, error)
-	DeleteClass(id uuid.UUID) error
+	PreviewUpdateClass(id uuid.UUID, input UpdateClassInput) (*ClassChangeImpact, error)
+	DeleteClass(id uuid.UUID, force bool) error
+	PreviewDeleteClass(id uuid.UUID) (*ClassChangeImpact, error)
+	RestoreClass(id uuid.UUID) (*models.Class, error)
+	GetTeachingLoad(dosenID uuid.UUID, semester string) (*TeachingLoad, error)
+}
+
+// TeachingLoadItem adalah satu kelas yang diampu dosen beserta porsi SKS-nya
+type TeachingLoadItem struct {
+	Class models.Class `json:"class"`
+	Role  string       `json:"role"`
+	SKS   float64      `json:"sks"`
+}
+
+// TeachingLoad merangkum beban mengajar dosen pada satu semester
+type TeachingLoad struct {
+	DosenID  uuid.UUID          `json:"dosen_id"`
+	Semester string             `json:"semester"`
+	TotalSKS float64            `json:"total_sks"`
+	Classes  []TeachingLoadItem `json:"classes"`
+}
+
+// ClassClash adalah kelas lain di KRS mahasiswa yang bentrok dengan jadwal baru
+type ClassClash struct {
+	ClassID    uuid.UUID `json:"class_id"`
+	CourseKode string    `json:"course_kode"`
+	NamaKelas  string    `json:"nama_kelas"`
+	Hari       string    `json:"hari"`
+	JamMulai   time.Time `json:"jam_mulai"`
+	JamSelesai time.Time `json:"jam_selesai"`
+}
+
+// StudentImpact adalah dampak perubahan kelas untuk satu mahasiswa
+type StudentImpact struct {
+	MahasiswaID   uuid.UUID    `json:"mahasiswa_id"`
+	Name          string       `json:"name"`
+	NIM           string       `json:"nim"`
+	KRSItemStatus string       `json:"krs_item_status"`
+	Clashes       []ClassClash `json:"clashes"`
+}
+
+// ClassChangeImpact merangkum mahasiswa yang terdampak reschedule atau penghapusan kelas
+type ClassChangeImpact struct {
+	ClassID             uuid.UUID       `json:"class_id"`
+	Action              string          `json:"action"`
+	ScheduleChanged     bool            `json:"schedule_changed"`
+	AffectedStudents    int             `json:"affected_students"`
+	StudentsWithClashes int             `json:"students_with_clashes"`
+	Students            []StudentImpact `json:"students"`
 }
 
 type classService struct {
-	classRepo repository.ClassRepository
+	classRepo  repository.ClassRepository
+	courseRepo repository.CourseRepository
+	notifier   NotificationService
 }
 
-func NewClassService(classRepo repository.ClassRepository) ClassService {
-	return &classService{classRepo: classRepo}
+func NewClassService(classRepo repository.ClassRepository, courseRepo repository.CourseRepository, notifier NotificationService) ClassService {
+	return &classService{classRepo: classRepo, courseRepo: courseRepo, notifier: notifier}
 }
 
 func (s *classService) CreateClass(input CreateClassInput) (*models.Class, error) {
+	course, err := s.courseRepo.FindByID(input.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	dosenInputs := input.Dosens
+	if len(dosenInputs) == 0 {
+		dosenInputs = []ClassDosenInput{{DosenID: input.DosenID, Role: CLASS_DOSEN_ROLE_COORDINATOR}}
+	}
+	dosens, coordinatorID, err := buildClassDosens(dosenInputs, course.SKS)
+	if err != nil {
+		return nil, err
+	}
+
 	conflict, err := s.classRepo.HasTimeConflict(input.RoomID, input.Hari, input.JamMulai, input.JamSelesai, nil)
 	if err != nil {
 		return nil, err
@@ -60,9 +149,18 @@ func (s *classService) CreateClass(input CreateClassInput) (*models.Class, error
 		return nil, ErrClassTimeConflict
 	}
 
+	conflict, err = s.classRepo.HasDosenTimeConflict(classDosenIDs(dosens), input.Hari, input.SemesterPenawaran, input.JamMulai, input.JamSelesai, nil)
+	if err != nil {
+		return nil, err
+	}
+	if conflict {
+		return nil, ErrDosenTimeConflict
+	}
+
 	class := &models.Class{
 		CourseID:          input.CourseID,
-		DosenID:           input.DosenID,
+		DosenID:           coordinatorID,
+		Dosens:            dosens,
 		NamaKelas:         input.NamaKelas,
 		Hari:              input.Hari,
 		JamMulai:          input.JamMulai,
@@ -70,6 +168,7 @@ func (s *classService) CreateClass(input CreateClassInput) (*models.Class, error
 		RoomID:            input.RoomID,
 		Kuota:             input.Kuota,
 		SemesterPenawaran: input.SemesterPenawaran,
+		StudyProgramID:    course.StudyProgramID,
 	}
 
 	if err := s.classRepo.Create(class); err != nil {
@@ -83,26 +182,66 @@ func (s *classService) GetClass(id uuid.UUID) (*models.Class, error) {
 	return s.classRepo.FindByID(id)
 }
 
-func (s *classService) ListClasses() ([]models.Class, error) {
-	return s.classRepo.FindAll()
+func (s *classService) ListClasses(filter repository.ClassFilter, page repository.PageQuery) (*repository.Page[models.Class], error) {
+	return s.classRepo.FindAll(filter, page)
 }
 
 func (s *classService) UpdateClass(id uuid.UUID, input UpdateClassInput) (*models.Class, error) {
-	class, err := s.classRepo.FindByID(id)
+	class, dosens, impact, err := s.prepareUpdate(id, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Dosens == nil && input.DosenID == nil {
+		dosens = nil
+	}
+	if err := s.classRepo.Update(class, dosens); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.classRepo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
 
+	if impact.ScheduleChanged {
+		s.notifyReschedule(updated, impact)
+	}
+
+	return updated, nil
+}
+
+// PreviewUpdateClass menjalankan validasi UpdateClass dan menghitung dampaknya tanpa menyimpan perubahan
+func (s *classService) PreviewUpdateClass(id uuid.UUID, input UpdateClassInput) (*ClassChangeImpact, error) {
+	_, _, impact, err := s.prepareUpdate(id, input)
+	if err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
+
+// prepareUpdate menerapkan input ke kelas (di memori), memvalidasi bentrok, dan menghitung dampak ke mahasiswa
+func (s *classService) prepareUpdate(id uuid.UUID, input UpdateClassInput) (*models.Class, []models.ClassDosen, *ClassChangeImpact, error) {
+	class, err := s.classRepo.FindByID(id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	original := *class
+
 	finalRoomID := class.RoomID
 	finalHari := class.Hari
 	finalJamMulai := class.JamMulai
 	finalJamSelesai := class.JamSelesai
+	finalSKS := class.Course.SKS
 
 	if input.CourseID != nil {
+		course, err := s.courseRepo.FindByID(*input.CourseID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
 		class.CourseID = *input.CourseID
-	}
-	if input.DosenID != nil {
-		class.DosenID = *input.DosenID
+		class.StudyProgramID = course.StudyProgramID
+		finalSKS = course.SKS
 	}
 	if input.NamaKelas != nil {
 		class.NamaKelas = *input.NamaKelas
@@ -130,7 +269,107 @@ func (s *classService) UpdateClass(id uuid.UUID, input UpdateClassInput) (*model
 		class.SemesterPenawaran = *input.SemesterPenawaran
 	}
 
+	var dosenInputs []ClassDosenInput
+	switch {
+	case input.Dosens != nil:
+		dosenInputs = *input.Dosens
+	case input.DosenID != nil:
+		// Ganti koordinator, anggota tim lain tetap
+		dosenInputs = []ClassDosenInput{{DosenID: *input.DosenID, Role: CLASS_DOSEN_ROLE_COORDINATOR}}
+		for _, d := range effectiveClassDosens(class) {
+			if d.Role == CLASS_DOSEN_ROLE_COORDINATOR || d.DosenID == *input.DosenID {
+				continue
+			}
+			dosenInputs = append(dosenInputs, ClassDosenInput{DosenID: d.DosenID, Role: d.Role, SKSShare: d.SKSShare})
+		}
+	default:
+		for _, d := range effectiveClassDosens(class) {
+			dosenInputs = append(dosenInputs, ClassDosenInput{DosenID: d.DosenID, Role: d.Role, SKSShare: d.SKSShare})
+		}
+	}
+
+	dosens, coordinatorID, err := buildClassDosens(dosenInputs, finalSKS)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	class.DosenID = coordinatorID
+
 	conflict, err := s.classRepo.HasTimeConflict(finalRoomID, finalHari, finalJamMulai, finalJamSelesai, &id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if conflict {
+		return nil, nil, nil, ErrClassTimeConflict
+	}
+
+	conflict, err = s.classRepo.HasDosenTimeConflict(classDosenIDs(dosens), finalHari, class.SemesterPenawaran, finalJamMulai, finalJamSelesai, &id)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if conflict {
+		return nil, nil, nil, ErrDosenTimeConflict
+	}
+
+	impact := &ClassChangeImpact{ClassID: id, Action: CLASS_CHANGE_RESCHEDULE, Students: []StudentImpact{}}
+	if isScheduleChanged(&original, class) {
+		impact, err = s.computeImpact(class, CLASS_CHANGE_RESCHEDULE)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return class, dosens, impact, nil
+}
+
+// DeleteClass melakukan soft delete kelas. Jika masih ada mahasiswa yang mengambil kelas ini,
+// penghapusan ditolak kecuali force = true; dengan force item KRS mereka dibatalkan dan mereka diberi notifikasi.
+func (s *classService) DeleteClass(id uuid.UUID, force bool) error {
+	class, err := s.classRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	impact, err := s.computeImpact(class, CLASS_CHANGE_DELETE)
+	if err != nil {
+		return err
+	}
+
+	if impact.AffectedStudents == 0 {
+		return s.classRepo.Delete(id)
+	}
+
+	if !force {
+		return &DependencyError{
+			Entity:     "class",
+			Dependents: map[string]int64{"krs_items": int64(impact.AffectedStudents)},
+		}
+	}
+
+	excluded := []string{KRS_ITEM_STATUS_CANCELLED, KRS_ITEM_STATUS_REJECTED}
+	if err := s.classRepo.DeleteAndCancelEnrollments(id, KRS_ITEM_STATUS_CANCELLED, excluded); err != nil {
+		return err
+	}
+
+	s.notifyDelete(class, impact)
+	return nil
+}
+
+// RestoreClass mengembalikan kelas yang sudah dihapus setelah memastikan course, room,
+// dan jadwalnya masih valid
+func (s *classService) RestoreClass(id uuid.UUID) (*models.Class, error) {
+	class, err := s.classRepo.FindDeletedByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if class.Course.DeletedAt.Valid {
+		return nil, errors.New("course kelas ini sudah dihapus, restore course terlebih dahulu")
+	}
+	if class.Room.DeletedAt.Valid {
+		return nil, errors.New("room kelas ini sudah dihapus, restore room terlebih dahulu")
+	}
+
+	conflict, err := s.classRepo.HasTimeConflict(class.RoomID, class.Hari, class.JamMulai, class.JamSelesai, &id)
 	if err != nil {
 		return nil, err
 	}
@@ -138,13 +377,289 @@ func (s *classService) UpdateClass(id uuid.UUID, input UpdateClassInput) (*model
 		return nil, ErrClassTimeConflict
 	}
 
-	if err := s.classRepo.Update(class); err != nil {
+	conflict, err = s.classRepo.HasDosenTimeConflict(classDosenIDs(effectiveClassDosens(class)), class.Hari, class.SemesterPenawaran, class.JamMulai, class.JamSelesai, &id)
+	if err != nil {
+		return nil, err
+	}
+	if conflict {
+		return nil, ErrDosenTimeConflict
+	}
+
+	if err := s.classRepo.Restore(id); err != nil {
 		return nil, err
 	}
 
 	return s.classRepo.FindByID(id)
 }
 
-func (s *classService) DeleteClass(id uuid.UUID) error {
-	return s.classRepo.Delete(id)
+// PreviewDeleteClass menghitung mahasiswa yang terdampak jika kelas dihapus
+func (s *classService) PreviewDeleteClass(id uuid.UUID) (*ClassChangeImpact, error) {
+	class, err := s.classRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.computeImpact(class, CLASS_CHANGE_DELETE)
+}
+
+// computeImpact mencari mahasiswa yang mengambil kelas ini dan, untuk reschedule,
+// kelas lain di KRS mereka yang akan bentrok dengan jadwal baru
+func (s *classService) computeImpact(class *models.Class, action string) (*ClassChangeImpact, error) {
+	excluded := []string{KRS_ITEM_STATUS_CANCELLED, KRS_ITEM_STATUS_REJECTED}
+
+	enrolled, err := s.classRepo.FindEnrolledItems(class.ID, excluded)
+	if err != nil {
+		return nil, err
+	}
+
+	impact := &ClassChangeImpact{
+		ClassID:          class.ID,
+		Action:           action,
+		ScheduleChanged:  action == CLASS_CHANGE_RESCHEDULE,
+		AffectedStudents: len(enrolled),
+		Students:         make([]StudentImpact, 0, len(enrolled)),
+	}
+
+	otherItemsByKRS := make(map[uuid.UUID][]models.KRSItem)
+	if action == CLASS_CHANGE_RESCHEDULE && len(enrolled) > 0 {
+		krsIDs := make([]uuid.UUID, 0, len(enrolled))
+		for _, item := range enrolled {
+			krsIDs = append(krsIDs, item.KRSID)
+		}
+		items, err := s.classRepo.FindItemsByKRSIDs(krsIDs, excluded)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.ClassID == class.ID {
+				continue
+			}
+			otherItemsByKRS[item.KRSID] = append(otherItemsByKRS[item.KRSID], item)
+		}
+	}
+
+	for _, item := range enrolled {
+		student := StudentImpact{
+			MahasiswaID:   item.KRS.MahasiswaID,
+			Name:          item.KRS.Mahasiswa.Name,
+			NIM:           item.KRS.Mahasiswa.NIM,
+			KRSItemStatus: item.Status,
+			Clashes:       []ClassClash{},
+		}
+		for _, other := range otherItemsByKRS[item.KRSID] {
+			if !isClassOverlap(class, &other.Class) {
+				continue
+			}
+			student.Clashes = append(student.Clashes, ClassClash{
+				ClassID:    other.ClassID,
+				CourseKode: other.Class.Course.Kode,
+				NamaKelas:  other.Class.NamaKelas,
+				Hari:       other.Class.Hari,
+				JamMulai:   other.Class.JamMulai,
+				JamSelesai: other.Class.JamSelesai,
+			})
+		}
+		if len(student.Clashes) > 0 {
+			impact.StudentsWithClashes++
+		}
+		impact.Students = append(impact.Students, student)
+	}
+
+	return impact, nil
+}
+
+func (s *classService) notifyReschedule(class *models.Class, impact *ClassChangeImpact) {
+	if s.notifier == nil || len(impact.Students) == 0 {
+		return
+	}
+
+	title := fmt.Sprintf("Perubahan jadwal %s %s", class.Course.Kode, class.NamaKelas)
+	message := fmt.Sprintf("Kelas %s %s dipindahkan ke %s %s-%s di ruang %s.",
+		class.Course.Nama, class.NamaKelas, class.Hari,
+		class.JamMulai.Format("15:04"), class.JamSelesai.Format("15:04"), class.Room.Nama)
+
+	var withoutClash []uuid.UUID
+	for _, student := range impact.Students {
+		if len(student.Clashes) == 0 {
+			withoutClash = append(withoutClash, student.MahasiswaID)
+			continue
+		}
+
+		var clashNames []string
+		for _, clash := range student.Clashes {
+			clashNames = append(clashNames, clash.CourseKode+" "+clash.NamaKelas)
+		}
+		clashMessage := fmt.Sprintf("%s Jadwal baru bentrok dengan %s di KRS Anda, silakan hubungi Dosen PA.",
+			message, strings.Join(clashNames, ", "))
+		if err := s.notifier.Notify([]uuid.UUID{student.MahasiswaID}, NOTIFICATION_TYPE_CLASS_RESCHEDULED, title, clashMessage); err != nil {
+			log.Printf("failed to notify mahasiswa %s: %v", student.MahasiswaID, err)
+		}
+	}
+
+	if err := s.notifier.Notify(withoutClash, NOTIFICATION_TYPE_CLASS_RESCHEDULED, title, message); err != nil {
+		log.Printf("failed to notify reschedule of class %s: %v", class.ID, err)
+	}
+}
+
+func (s *classService) notifyDelete(class *models.Class, impact *ClassChangeImpact) {
+	if s.notifier == nil || len(impact.Students) == 0 {
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(impact.Students))
+	for _, student := range impact.Students {
+		userIDs = append(userIDs, student.MahasiswaID)
+	}
+
+	title := fmt.Sprintf("Kelas %s %s dihapus", class.Course.Kode, class.NamaKelas)
+	message := fmt.Sprintf("Kelas %s %s tidak lagi ditawarkan dan telah dibatalkan dari KRS Anda. Silakan pilih kelas pengganti.",
+		class.Course.Nama, class.NamaKelas)
+	if err := s.notifier.Notify(userIDs, NOTIFICATION_TYPE_CLASS_DELETED, title, message); err != nil {
+		log.Printf("failed to notify deletion of class %s: %v", class.ID, err)
+	}
+}
+
+func (s *classService) GetTeachingLoad(dosenID uuid.UUID, semester string) (*TeachingLoad, error) {
+	classes, err := s.classRepo.FindByDosen(dosenID, semester)
+	if err != nil {
+		return nil, err
+	}
+
+	load := &TeachingLoad{
+		DosenID:  dosenID,
+		Semester: semester,
+		Classes:  []TeachingLoadItem{},
+	}
+
+	for _, class := range classes {
+		for _, d := range effectiveClassDosens(&class) {
+			if d.DosenID != dosenID {
+				continue
+			}
+			sks := dosenSKSShare(&class, d)
+			load.Classes = append(load.Classes, TeachingLoadItem{
+				Class: class,
+				Role:  d.Role,
+				SKS:   sks,
+			})
+			load.TotalSKS += sks
+		}
+	}
+
+	return load, nil
+}
+
+// buildClassDosens memvalidasi tim pengajar dan mengembalikan ID dosen koordinator
+func buildClassDosens(inputs []ClassDosenInput, courseSKS int) ([]models.ClassDosen, uuid.UUID, error) {
+	if len(inputs) == 0 {
+		return nil, uuid.Nil, fmt.Errorf("%w: at least one dosen is required", ErrInvalidClassDosens)
+	}
+
+	var coordinatorID uuid.UUID
+	var totalShare float64
+	seen := make(map[uuid.UUID]bool)
+	dosens := make([]models.ClassDosen, 0, len(inputs))
+
+	for _, in := range inputs {
+		if in.DosenID == uuid.Nil {
+			return nil, uuid.Nil, fmt.Errorf("%w: dosen_id is required", ErrInvalidClassDosens)
+		}
+		if seen[in.DosenID] {
+			return nil, uuid.Nil, fmt.Errorf("%w: dosen %s listed more than once", ErrInvalidClassDosens, in.DosenID)
+		}
+		seen[in.DosenID] = true
+
+		role := in.Role
+		if role == "" {
+			role = CLASS_DOSEN_ROLE_MEMBER
+		}
+		switch role {
+		case CLASS_DOSEN_ROLE_COORDINATOR:
+			if coordinatorID != uuid.Nil {
+				return nil, uuid.Nil, fmt.Errorf("%w: only one coordinator is allowed", ErrInvalidClassDosens)
+			}
+			coordinatorID = in.DosenID
+		case CLASS_DOSEN_ROLE_MEMBER:
+		default:
+			return nil, uuid.Nil, fmt.Errorf("%w: role must be %s or %s", ErrInvalidClassDosens, CLASS_DOSEN_ROLE_COORDINATOR, CLASS_DOSEN_ROLE_MEMBER)
+		}
+
+		if in.SKSShare != nil {
+			if *in.SKSShare <= 0 {
+				return nil, uuid.Nil, fmt.Errorf("%w: sks_share must be greater than 0", ErrInvalidClassDosens)
+			}
+			totalShare += *in.SKSShare
+		}
+
+		dosens = append(dosens, models.ClassDosen{
+			DosenID:  in.DosenID,
+			Role:     role,
+			SKSShare: in.SKSShare,
+		})
+	}
+
+	if coordinatorID == uuid.Nil {
+		return nil, uuid.Nil, fmt.Errorf("%w: a coordinator is required", ErrInvalidClassDosens)
+	}
+	if totalShare > float64(courseSKS) {
+		return nil, uuid.Nil, fmt.Errorf("%w: total sks_share (%.2f) exceeds course sks (%d)", ErrInvalidClassDosens, totalShare, courseSKS)
+	}
+
+	return dosens, coordinatorID, nil
+}
+
+// effectiveClassDosens mengembalikan tim pengajar kelas. Kelas lama yang belum punya
+// data class_dosens dianggap diampu penuh oleh Class.DosenID sebagai koordinator.
+func effectiveClassDosens(class *models.Class) []models.ClassDosen {
+	if len(class.Dosens) > 0 {
+		return class.Dosens
+	}
+	return []models.ClassDosen{{
+		ClassID: class.ID,
+		DosenID: class.DosenID,
+		Dosen:   class.Dosen,
+		Role:    CLASS_DOSEN_ROLE_COORDINATOR,
+	}}
+}
+
+// dosenSKSShare menghitung porsi SKS seorang dosen di kelas. Dosen tanpa SKSShare
+// mendapat sisa SKS matakuliah yang dibagi rata.
+func dosenSKSShare(class *models.Class, target models.ClassDosen) float64 {
+	if target.SKSShare != nil {
+		return *target.SKSShare
+	}
+
+	remaining := float64(class.Course.SKS)
+	unassigned := 0
+	for _, d := range effectiveClassDosens(class) {
+		if d.SKSShare != nil {
+			remaining -= *d.SKSShare
+		} else {
+			unassigned++
+		}
+	}
+	if unassigned == 0 || remaining <= 0 {
+		return 0
+	}
+	return remaining / float64(unassigned)
+}
+
+// isScheduleChanged bernilai true jika hari, jam, ruang, atau semester kelas berubah
+func isScheduleChanged(before, after *models.Class) bool {
+	return before.Hari != after.Hari ||
+		!before.JamMulai.Equal(after.JamMulai) ||
+		!before.JamSelesai.Equal(after.JamSelesai) ||
+		before.RoomID != after.RoomID ||
+		before.SemesterPenawaran != after.SemesterPenawaran
+}
+
+func isClassOverlap(a, b *models.Class) bool {
+	return a.Hari == b.Hari && a.JamMulai.Before(b.JamSelesai) && a.JamSelesai.After(b.JamMulai)
+}
+
+func classDosenIDs(dosens []models.ClassDosen) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(dosens))
+	for _, d := range dosens {
+		ids = append(ids, d.DosenID)
+	}
+	return ids
 }
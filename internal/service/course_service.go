@@ -9,51 +9,92 @@ import (
 	"gorm.io/gorm"
 )
 
+var ErrPrerequisiteCycle = errors.New("prasyarat membentuk siklus")
+
+type CreateCourseInput struct {
+	Kode            string
+	Nama            string
+	SKS             int
+	Deskripsi       string
+	StudyProgramID  *uuid.UUID
+	PrerequisiteIDs []uuid.UUID
+}
+
+// UpdateCourseInput: field nil tidak diubah. PrerequisiteIDs non-nil mengganti seluruh prasyarat.
+type UpdateCourseInput struct {
+	Kode            *string
+	Nama            *string
+	SKS             *int
+	Deskripsi       *string
+	StudyProgramID  *uuid.UUID
+	PrerequisiteIDs *[]uuid.UUID
+}
+
 type CourseService interface {
-	CreateCourse(kode, nama string, sks int) (*models.Course, error)
+	CreateCourse(input CreateCourseInput) (*models.Course, error)
 	GetCourseByID(id uuid.UUID) (*models.Course, error)
-	GetAllCourses() ([]models.Course, error)
-	UpdateCourse(id uuid.UUID, kode, nama *string, sks *int) (*models.Course, error)
+	GetAllCourses(filter repository.CourseFilter, page repository.PageQuery) (*repository.Page[models.Course], error)
+	UpdateCourse(id uuid.UUID, input UpdateCourseInput) (*models.Course, error)
 	DeleteCourse(id uuid.UUID) error
+	RestoreCourse(id uuid.UUID) (*models.Course, error)
 }
 
 type courseService struct {
-	repo repository.CourseRepository
+	repo             repository.CourseRepository
+	studyProgramRepo repository.StudyProgramRepository
 }
 
-func NewCourseService(repo repository.CourseRepository) CourseService {
-	return &courseService{repo: repo}
+func NewCourseService(repo repository.CourseRepository, studyProgramRepo repository.StudyProgramRepository) CourseService {
+	return &courseService{repo: repo, studyProgramRepo: studyProgramRepo}
 }
 
-func (s *courseService) CreateCourse(kode, nama string, sks int) (*models.Course, error) {
+func (s *courseService) CreateCourse(input CreateCourseInput) (*models.Course, error) {
 	// Check if kode already exists
-	existing, err := s.repo.FindByKode(kode)
+	existing, err := s.repo.FindByKode(input.Kode)
 	if err == nil && existing != nil {
 		return nil, errors.New("course dengan kode tersebut sudah ada")
 	}
 
+	if input.StudyProgramID != nil {
+		if _, err := s.studyProgramRepo.FindByID(*input.StudyProgramID); err != nil {
+			return nil, errors.New("study program not found")
+		}
+	}
+
 	course := &models.Course{
-		Kode: kode,
-		Nama: nama,
-		SKS:  sks,
+		Kode:           input.Kode,
+		Nama:           input.Nama,
+		SKS:            input.SKS,
+		Deskripsi:      input.Deskripsi,
+		StudyProgramID: input.StudyProgramID,
+	}
+
+	if err := s.validatePrerequisites(course.ID, input.PrerequisiteIDs); err != nil {
+		return nil, err
 	}
 
 	if err := s.repo.Create(course); err != nil {
 		return nil, err
 	}
 
-	return course, nil
+	if len(input.PrerequisiteIDs) > 0 {
+		if err := s.repo.ReplacePrerequisites(course.ID, input.PrerequisiteIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.FindByID(course.ID)
 }
 
 func (s *courseService) GetCourseByID(id uuid.UUID) (*models.Course, error) {
 	return s.repo.FindByID(id)
 }
 
-func (s *courseService) GetAllCourses() ([]models.Course, error) {
-	return s.repo.FindAll()
+func (s *courseService) GetAllCourses(filter repository.CourseFilter, page repository.PageQuery) (*repository.Page[models.Course], error) {
+	return s.repo.FindAll(filter, page)
 }
 
-func (s *courseService) UpdateCourse(id uuid.UUID, kode, nama *string, sks *int) (*models.Course, error) {
+func (s *courseService) UpdateCourse(id uuid.UUID, input UpdateCourseInput) (*models.Course, error) {
 	course, err := s.repo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -62,28 +103,51 @@ func (s *courseService) UpdateCourse(id uuid.UUID, kode, nama *string, sks *int)
 		return nil, err
 	}
 
-	if kode != nil {
+	if input.Kode != nil {
 		// Check if new kode already exists
-		existing, err := s.repo.FindByKode(*kode)
+		existing, err := s.repo.FindByKode(*input.Kode)
 		if err == nil && existing != nil && existing.ID != id {
 			return nil, errors.New("course dengan kode tersebut sudah ada")
 		}
-		course.Kode = *kode
+		course.Kode = *input.Kode
+	}
+
+	if input.Nama != nil {
+		course.Nama = *input.Nama
 	}
 
-	if nama != nil {
-		course.Nama = *nama
+	if input.SKS != nil {
+		course.SKS = *input.SKS
 	}
 
-	if sks != nil {
-		course.SKS = *sks
+	if input.Deskripsi != nil {
+		course.Deskripsi = *input.Deskripsi
+	}
+
+	if input.StudyProgramID != nil {
+		if _, err := s.studyProgramRepo.FindByID(*input.StudyProgramID); err != nil {
+			return nil, errors.New("study program not found")
+		}
+		course.StudyProgramID = input.StudyProgramID
+	}
+
+	if input.PrerequisiteIDs != nil {
+		if err := s.validatePrerequisites(id, *input.PrerequisiteIDs); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.repo.Update(course); err != nil {
 		return nil, err
 	}
 
-	return course, nil
+	if input.PrerequisiteIDs != nil {
+		if err := s.repo.ReplacePrerequisites(id, *input.PrerequisiteIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.FindByID(id)
 }
 
 func (s *courseService) DeleteCourse(id uuid.UUID) error {
@@ -95,5 +159,115 @@ func (s *courseService) DeleteCourse(id uuid.UUID) error {
 		return err
 	}
 
+	classCount, err := s.repo.CountActiveClasses(id)
+	if err != nil {
+		return err
+	}
+	krsItemCount, err := s.repo.CountKRSItems(id, []string{KRS_ITEM_STATUS_CANCELLED, KRS_ITEM_STATUS_REJECTED})
+	if err != nil {
+		return err
+	}
+	prerequisiteOfCount, err := s.repo.CountPrerequisiteOf(id)
+	if err != nil {
+		return err
+	}
+	curriculumCount, err := s.repo.CountCurriculumEntries(id)
+	if err != nil {
+		return err
+	}
+	if classCount > 0 || krsItemCount > 0 || prerequisiteOfCount > 0 || curriculumCount > 0 {
+		return &DependencyError{
+			Entity: "course",
+			Dependents: map[string]int64{
+				"classes":         classCount,
+				"krs_items":       krsItemCount,
+				"prerequisite_of": prerequisiteOfCount,
+				"curricula":       curriculumCount,
+			},
+		}
+	}
+
 	return s.repo.Delete(id)
 }
+
+func (s *courseService) RestoreCourse(id uuid.UUID) (*models.Course, error) {
+	deleted, err := s.repo.FindDeletedByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("deleted course not found")
+		}
+		return nil, err
+	}
+
+	// Kode bisa saja sudah dipakai course lain setelah course ini dihapus
+	existing, err := s.repo.FindByKode(deleted.Kode)
+	if err == nil && existing != nil {
+		return nil, errors.New("course dengan kode tersebut sudah ada")
+	}
+
+	if err := s.repo.Restore(id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(id)
+}
+
+// validatePrerequisites memastikan prasyarat ada, bukan course itu sendiri, dan tidak membentuk siklus
+func (s *courseService) validatePrerequisites(courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	if len(prerequisiteIDs) == 0 {
+		return nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(prerequisiteIDs))
+	for _, id := range prerequisiteIDs {
+		if id == courseID {
+			return errors.New("course tidak bisa menjadi prasyarat dirinya sendiri")
+		}
+		if seen[id] {
+			return errors.New("prasyarat tidak boleh duplikat")
+		}
+		seen[id] = true
+	}
+
+	found, err := s.repo.FindByIDs(prerequisiteIDs)
+	if err != nil {
+		return err
+	}
+	if len(found) != len(prerequisiteIDs) {
+		return errors.New("prerequisite course not found")
+	}
+
+	// course baru belum punya relasi, jadi tidak mungkin membentuk siklus
+	if courseID == uuid.Nil {
+		return nil
+	}
+
+	edges, err := s.repo.FindPrerequisiteEdges()
+	if err != nil {
+		return err
+	}
+	graph := make(map[uuid.UUID][]uuid.UUID)
+	for _, edge := range edges {
+		if edge.CourseID == courseID {
+			continue // akan diganti dengan prerequisiteIDs
+		}
+		graph[edge.CourseID] = append(graph[edge.CourseID], edge.PrerequisiteID)
+	}
+
+	// siklus terjadi jika courseID bisa dicapai dari salah satu prasyarat barunya
+	visited := make(map[uuid.UUID]bool)
+	stack := append([]uuid.UUID(nil), prerequisiteIDs...)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == courseID {
+			return ErrPrerequisiteCycle
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		stack = append(stack, graph[current]...)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	CURRICULUM_COURSE_WAJIB   = "wajib"
+	CURRICULUM_COURSE_PILIHAN = "pilihan"
+
+	MAX_CURRICULUM_SEMESTER = 14
+)
+
+// ErrCurriculumNotFound dikembalikan jika mahasiswa belum punya prodi / prodinya belum punya kurikulum yang berlaku
+var ErrCurriculumNotFound = errors.New("curriculum not found for this student")
+
+type CreateCurriculumInput struct {
+	StudyProgramID uuid.UUID
+	Tahun          int
+	Nama           string
+	MinSKSLulus    int
+	MinIPK         float64
+}
+
+type UpdateCurriculumInput struct {
+	Tahun       *int
+	Nama        *string
+	MinSKSLulus *int
+	MinIPK      *float64
+}
+
+type ElectiveGroupInput struct {
+	Nama   string
+	MinSKS int
+}
+
+type CurriculumCourseInput struct {
+	CourseID            uuid.UUID
+	Jenis               string
+	SemesterRekomendasi int
+	ElectiveGroup       string // nama kelompok pilihan di input yang sama (opsional, hanya untuk pilihan)
+}
+
+// CurriculumSemesterPlan adalah rencana satu semester berdasarkan semester rekomendasi kurikulum
+type CurriculumSemesterPlan struct {
+	Semester int                       `json:"semester"`
+	TotalSKS int                       `json:"total_sks"`
+	Courses  []models.CurriculumCourse `json:"courses"`
+}
+
+// StudentCurriculum adalah kurikulum yang berlaku untuk seorang mahasiswa beserta rencana per semesternya
+type StudentCurriculum struct {
+	Curriculum *models.Curriculum       `json:"curriculum"`
+	SemesterKe int                      `json:"semester_ke"`
+	Plan       []CurriculumSemesterPlan `json:"plan"`
+}
+
+type CurriculumService interface {
+	CreateCurriculum(input CreateCurriculumInput) (*models.Curriculum, error)
+	GetCurriculumByID(id uuid.UUID) (*models.Curriculum, error)
+	ListCurricula(studyProgramID *uuid.UUID) ([]models.Curriculum, error)
+	UpdateCurriculum(id uuid.UUID, input UpdateCurriculumInput) (*models.Curriculum, error)
+	DeleteCurriculum(id uuid.UUID) error
+	ReplaceStructure(id uuid.UUID, groups []ElectiveGroupInput, courses []CurriculumCourseInput) (*models.Curriculum, error)
+	GetStudentCurriculum(mahasiswaID uuid.UUID) (*StudentCurriculum, error)
+}
+
+type curriculumService struct {
+	repo             repository.CurriculumRepository
+	courseRepo       repository.CourseRepository
+	studyProgramRepo repository.StudyProgramRepository
+	mahasiswaRepo    repository.MahasiswaRepository
+}
+
+func NewCurriculumService(repo repository.CurriculumRepository, courseRepo repository.CourseRepository, studyProgramRepo repository.StudyProgramRepository, mahasiswaRepo repository.MahasiswaRepository) CurriculumService {
+	return &curriculumService{repo: repo, courseRepo: courseRepo, studyProgramRepo: studyProgramRepo, mahasiswaRepo: mahasiswaRepo}
+}
+
+// StudentSemester menghitung semester ke-berapa mahasiswa saat ini. Angkatan dianggap masuk di semester ganjil.
+func StudentSemester(angkatan int) int {
+	semester := SemesterOrder(GetCurrentTahunAkademik(), GetCurrentSemester()) - SemesterOrder(angkatan, SEMESTER_GANJIL) + 1
+	if semester < 1 {
+		return 1
+	}
+	return semester
+}
+
+func (s *curriculumService) CreateCurriculum(input CreateCurriculumInput) (*models.Curriculum, error) {
+	if _, err := s.studyProgramRepo.FindByID(input.StudyProgramID); err != nil {
+		return nil, errors.New("study program not found")
+	}
+
+	existing, err := s.repo.FindByProgramTahun(input.StudyProgramID, input.Tahun)
+	if err == nil && existing != nil {
+		return nil, errors.New("kurikulum prodi untuk tahun tersebut sudah ada")
+	}
+
+	curriculum := &models.Curriculum{
+		StudyProgramID: input.StudyProgramID,
+		Tahun:          input.Tahun,
+		Nama:           input.Nama,
+		MinSKSLulus:    input.MinSKSLulus,
+		MinIPK:         input.MinIPK,
+	}
+	if err := s.repo.Create(curriculum); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(curriculum.ID)
+}
+
+func (s *curriculumService) GetCurriculumByID(id uuid.UUID) (*models.Curriculum, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *curriculumService) ListCurricula(studyProgramID *uuid.UUID) ([]models.Curriculum, error) {
+	return s.repo.FindAll(studyProgramID)
+}
+
+func (s *curriculumService) UpdateCurriculum(id uuid.UUID, input UpdateCurriculumInput) (*models.Curriculum, error) {
+	curriculum, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("curriculum not found")
+		}
+		return nil, err
+	}
+
+	if input.Tahun != nil {
+		existing, err := s.repo.FindByProgramTahun(curriculum.StudyProgramID, *input.Tahun)
+		if err == nil && existing != nil && existing.ID != id {
+			return nil, errors.New("kurikulum prodi untuk tahun tersebut sudah ada")
+		}
+		curriculum.Tahun = *input.Tahun
+	}
+
+	if input.Nama != nil {
+		curriculum.Nama = *input.Nama
+	}
+
+	if input.MinSKSLulus != nil {
+		curriculum.MinSKSLulus = *input.MinSKSLulus
+	}
+
+	if input.MinIPK != nil {
+		curriculum.MinIPK = *input.MinIPK
+	}
+
+	if err := s.repo.Update(curriculum); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(id)
+}
+
+func (s *curriculumService) DeleteCurriculum(id uuid.UUID) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("curriculum not found")
+		}
+		return err
+	}
+	return s.repo.Delete(id)
+}
+
+// ReplaceStructure mengganti seluruh kelompok pilihan dan course kurikulum
+func (s *curriculumService) ReplaceStructure(id uuid.UUID, groupInputs []ElectiveGroupInput, courseInputs []CurriculumCourseInput) (*models.Curriculum, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("curriculum not found")
+		}
+		return nil, err
+	}
+
+	groups := make([]models.ElectiveGroup, 0, len(groupInputs))
+	groupIDs := make(map[string]uuid.UUID, len(groupInputs))
+	for _, in := range groupInputs {
+		if in.Nama == "" || in.MinSKS < 0 {
+			return nil, errors.New("elective group nama is required and min_sks must not be negative")
+		}
+		if _, ok := groupIDs[in.Nama]; ok {
+			return nil, fmt.Errorf("elective group %s listed more than once", in.Nama)
+		}
+		group := models.ElectiveGroup{ID: uuid.New(), Nama: in.Nama, MinSKS: in.MinSKS}
+		groupIDs[in.Nama] = group.ID
+		groups = append(groups, group)
+	}
+
+	courses := make([]models.CurriculumCourse, 0, len(courseInputs))
+	courseIDs := make([]uuid.UUID, 0, len(courseInputs))
+	seen := make(map[uuid.UUID]bool, len(courseInputs))
+	for _, in := range courseInputs {
+		if seen[in.CourseID] {
+			return nil, fmt.Errorf("course %s listed more than once", in.CourseID)
+		}
+		seen[in.CourseID] = true
+
+		if in.Jenis != CURRICULUM_COURSE_WAJIB && in.Jenis != CURRICULUM_COURSE_PILIHAN {
+			return nil, fmt.Errorf("jenis must be %s or %s", CURRICULUM_COURSE_WAJIB, CURRICULUM_COURSE_PILIHAN)
+		}
+		if in.SemesterRekomendasi < 1 || in.SemesterRekomendasi > MAX_CURRICULUM_SEMESTER {
+			return nil, fmt.Errorf("semester_rekomendasi must be between 1 and %d", MAX_CURRICULUM_SEMESTER)
+		}
+
+		course := models.CurriculumCourse{
+			CourseID:            in.CourseID,
+			Jenis:               in.Jenis,
+			SemesterRekomendasi: in.SemesterRekomendasi,
+		}
+		if in.ElectiveGroup != "" {
+			if in.Jenis != CURRICULUM_COURSE_PILIHAN {
+				return nil, errors.New("elective_group only applies to pilihan courses")
+			}
+			groupID, ok := groupIDs[in.ElectiveGroup]
+			if !ok {
+				return nil, fmt.Errorf("elective group %s not found", in.ElectiveGroup)
+			}
+			course.ElectiveGroupID = &groupID
+		}
+		courses = append(courses, course)
+		courseIDs = append(courseIDs, in.CourseID)
+	}
+
+	found, err := s.courseRepo.FindByIDs(courseIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) != len(courseIDs) {
+		return nil, errors.New("course not found")
+	}
+
+	if err := s.repo.ReplaceStructure(id, groups, courses); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByID(id)
+}
+
+// GetStudentCurriculum mengembalikan kurikulum prodi mahasiswa yang berlaku untuk angkatannya
+func (s *curriculumService) GetStudentCurriculum(mahasiswaID uuid.UUID) (*StudentCurriculum, error) {
+	mahasiswa, err := s.mahasiswaRepo.FindByID(mahasiswaID)
+	if err != nil {
+		return nil, err
+	}
+	if mahasiswa.StudyProgramID == nil {
+		return nil, ErrCurriculumNotFound
+	}
+
+	curriculum, err := s.repo.FindForAngkatan(*mahasiswa.StudyProgramID, mahasiswa.Angkatan)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCurriculumNotFound
+		}
+		return nil, err
+	}
+
+	result := &StudentCurriculum{
+		Curriculum: curriculum,
+		SemesterKe: StudentSemester(mahasiswa.Angkatan),
+		Plan:       []CurriculumSemesterPlan{},
+	}
+	// course sudah terurut berdasarkan semester rekomendasi
+	for _, cc := range curriculum.Courses {
+		last := len(result.Plan) - 1
+		if last < 0 || result.Plan[last].Semester != cc.SemesterRekomendasi {
+			result.Plan = append(result.Plan, CurriculumSemesterPlan{Semester: cc.SemesterRekomendasi})
+			last++
+		}
+		result.Plan[last].Courses = append(result.Plan[last].Courses, cc)
+		result.Plan[last].TotalSKS += cc.Course.SKS
+	}
+	return result, nil
+}
+
+// RecommendedCourseIDs mengembalikan course kurikulum yang direkomendasikan untuk semester mahasiswa saat ini
+func (sc *StudentCurriculum) RecommendedCourseIDs() []uuid.UUID {
+	var ids []uuid.UUID
+	for _, cc := range sc.Curriculum.Courses {
+		if cc.SemesterRekomendasi == sc.SemesterKe {
+			ids = append(ids, cc.CourseID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	REMAINING_COURSE_NOT_TAKEN   = "not_taken"
+	REMAINING_COURSE_IN_PROGRESS = "in_progress" // sedang diambil, belum dinilai
+	REMAINING_COURSE_FAILED      = "failed"
+)
+
+// ErrNotAdvisee dikembalikan jika dosen bukan dosen PA mahasiswa
+var ErrNotAdvisee = errors.New("Mahasiswa ini bukan bimbingan Anda.")
+
+// RemainingCourse adalah course wajib kurikulum yang belum lulus
+type RemainingCourse struct {
+	Course              models.Course `json:"course"`
+	SemesterRekomendasi int           `json:"semester_rekomendasi"`
+	Status              string        `json:"status"`
+}
+
+// ElectiveGroupProgress adalah capaian SKS sebuah kelompok pilihan
+type ElectiveGroupProgress struct {
+	ID           uuid.UUID `json:"id"`
+	Nama         string    `json:"nama"`
+	MinSKS       int       `json:"min_sks"`
+	CompletedSKS int       `json:"completed_sks"`
+	Met          bool      `json:"met"`
+}
+
+// CompletedCourse adalah course yang sudah lulus beserta nilai terbaiknya
+type CompletedCourse struct {
+	CourseID uuid.UUID `json:"course_id"`
+	Kode     string    `json:"kode"`
+	Nama     string    `json:"nama"`
+	SKS      int       `json:"sks"`
+	Nilai    string    `json:"nilai"`
+}
+
+// DegreeAudit adalah audit kelulusan mahasiswa terhadap kurikulum yang berlaku untuknya
+type DegreeAudit struct {
+	MahasiswaID        uuid.UUID               `json:"mahasiswa_id"`
+	StudyProgramID     uuid.UUID               `json:"study_program_id"`
+	CurriculumID       uuid.UUID               `json:"curriculum_id"`
+	CurriculumNama     string                  `json:"curriculum_nama"`
+	CurriculumTahun    int                     `json:"curriculum_tahun"`
+	SemesterKe         int                     `json:"semester_ke"`
+	CompletedSKS       int                     `json:"completed_sks"`
+	MinSKSLulus        int                     `json:"min_sks_lulus"`
+	SKSMet             bool                    `json:"sks_met"`
+	IPK                float64                 `json:"ipk"`
+	MinIPK             float64                 `json:"min_ipk"`
+	IPKMet             bool                    `json:"ipk_met"`
+	RemainingMandatory []RemainingCourse       `json:"remaining_mandatory"`
+	ElectiveSKS        int                     `json:"elective_sks"`
+	ElectiveGroups     []ElectiveGroupProgress `json:"elective_groups"`
+	CompletedCourses   []CompletedCourse       `json:"completed_courses"`
+	Eligible           bool                    `json:"eligible"` // memenuhi seluruh syarat lulus
+}
+
+// EligibleStudent adalah satu baris laporan mahasiswa yang memenuhi syarat lulus
+type EligibleStudent struct {
+	Mahasiswa    models.User `json:"mahasiswa"`
+	CurriculumID uuid.UUID   `json:"curriculum_id"`
+	CompletedSKS int         `json:"completed_sks"`
+	IPK          float64     `json:"ipk"`
+}
+
+type DegreeAuditService interface {
+	GetDegreeAudit(mahasiswaID uuid.UUID) (*DegreeAudit, error)
+	GetAdviseeDegreeAudit(dosenID, mahasiswaID uuid.UUID) (*models.User, *DegreeAudit, error)
+	ListEligibleStudents(filter repository.MahasiswaFilter) ([]EligibleStudent, error)
+}
+
+type degreeAuditService struct {
+	gradeRepo      repository.GradeRepository
+	curriculumRepo repository.CurriculumRepository
+	mahasiswaRepo  repository.MahasiswaRepository
+}
+
+func NewDegreeAuditService(gradeRepo repository.GradeRepository, curriculumRepo repository.CurriculumRepository, mahasiswaRepo repository.MahasiswaRepository) DegreeAuditService {
+	return &degreeAuditService{gradeRepo: gradeRepo, curriculumRepo: curriculumRepo, mahasiswaRepo: mahasiswaRepo}
+}
+
+func (s *degreeAuditService) GetDegreeAudit(mahasiswaID uuid.UUID) (*DegreeAudit, error) {
+	mahasiswa, err := s.mahasiswaRepo.FindByID(mahasiswaID)
+	if err != nil {
+		return nil, err
+	}
+	return s.auditStudent(mahasiswa)
+}
+
+// GetAdviseeDegreeAudit hanya mengizinkan dosen PA mahasiswa yang bersangkutan
+func (s *degreeAuditService) GetAdviseeDegreeAudit(dosenID, mahasiswaID uuid.UUID) (*models.User, *DegreeAudit, error) {
+	mahasiswa, err := s.mahasiswaRepo.FindByID(mahasiswaID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if mahasiswa.DosenPAID == nil || *mahasiswa.DosenPAID != dosenID {
+		return nil, nil, ErrNotAdvisee
+	}
+
+	audit, err := s.auditStudent(mahasiswa)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mahasiswa, audit, nil
+}
+
+// ListEligibleStudents mengaudit seluruh mahasiswa yang cocok dengan filter dan mengembalikan yang memenuhi syarat lulus.
+// Mahasiswa tanpa prodi / kurikulum dilewati.
+func (s *degreeAuditService) ListEligibleStudents(filter repository.MahasiswaFilter) ([]EligibleStudent, error) {
+	students, err := s.mahasiswaRepo.FindAllMatching(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(students))
+	for _, m := range students {
+		ids = append(ids, m.ID)
+	}
+	records, err := s.gradeRepo.FindGradeRecords(ids, inactiveItemStatuses())
+	if err != nil {
+		return nil, err
+	}
+	recordsByStudent := make(map[uuid.UUID][]repository.GradeRecord, len(students))
+	for _, r := range records {
+		recordsByStudent[r.MahasiswaID] = append(recordsByStudent[r.MahasiswaID], r)
+	}
+
+	// kurikulum di-cache per prodi + angkatan karena banyak mahasiswa berbagi kurikulum yang sama
+	type curriculumKey struct {
+		studyProgramID uuid.UUID
+		angkatan       int
+	}
+	curricula := make(map[curriculumKey]*models.Curriculum)
+
+	eligible := make([]EligibleStudent, 0)
+	for _, m := range students {
+		if m.StudyProgramID == nil {
+			continue
+		}
+		key := curriculumKey{*m.StudyProgramID, m.Angkatan}
+		curriculum, ok := curricula[key]
+		if !ok {
+			curriculum, err = s.curriculumRepo.FindForAngkatan(*m.StudyProgramID, m.Angkatan)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			curricula[key] = curriculum
+		}
+		if curriculum == nil {
+			continue
+		}
+
+		audit := buildDegreeAudit(&m, curriculum, recordsByStudent[m.ID])
+		if audit.Eligible {
+			eligible = append(eligible, EligibleStudent{
+				Mahasiswa:    m,
+				CurriculumID: curriculum.ID,
+				CompletedSKS: audit.CompletedSKS,
+				IPK:          audit.IPK,
+			})
+		}
+	}
+	return eligible, nil
+}
+
+func (s *degreeAuditService) auditStudent(mahasiswa *models.User) (*DegreeAudit, error) {
+	if mahasiswa.StudyProgramID == nil {
+		return nil, ErrCurriculumNotFound
+	}
+	curriculum, err := s.curriculumRepo.FindForAngkatan(*mahasiswa.StudyProgramID, mahasiswa.Angkatan)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCurriculumNotFound
+		}
+		return nil, err
+	}
+
+	records, err := s.gradeRepo.FindGradeRecords([]uuid.UUID{mahasiswa.ID}, inactiveItemStatuses())
+	if err != nil {
+		return nil, err
+	}
+	return buildDegreeAudit(mahasiswa, curriculum, records), nil
+}
+
+func inactiveItemStatuses() []string {
+	return []string{KRS_ITEM_STATUS_CANCELLED, KRS_ITEM_STATUS_REJECTED}
+}
+
+// courseResult adalah rangkuman seluruh pengambilan satu course: nilai terbaik dan apakah masih berjalan
+type courseResult struct {
+	kode       string
+	nama       string
+	sks        int
+	bestGrade  string
+	graded     bool
+	inProgress bool
+}
+
+func (r courseResult) passed() bool {
+	return r.graded && GRADE_POINTS[r.bestGrade] >= MIN_PASSING_GRADE_POINT
+}
+
+// buildDegreeAudit menghitung audit kelulusan. Course yang diulang memakai nilai terbaik.
+// IPK dihitung dari seluruh course yang sudah dinilai (termasuk E).
+func buildDegreeAudit(mahasiswa *models.User, curriculum *models.Curriculum, records []repository.GradeRecord) *DegreeAudit {
+	results := make(map[uuid.UUID]*courseResult)
+	for _, rec := range records {
+		result, ok := results[rec.CourseID]
+		if !ok {
+			result = &courseResult{kode: rec.Kode, nama: rec.Nama, sks: rec.SKS}
+			results[rec.CourseID] = result
+		}
+		if rec.Nilai == nil {
+			result.inProgress = true
+			continue
+		}
+		if !result.graded || GRADE_POINTS[*rec.Nilai] > GRADE_POINTS[result.bestGrade] {
+			result.bestGrade = *rec.Nilai
+			result.graded = true
+		}
+	}
+
+	audit := &DegreeAudit{
+		MahasiswaID:        mahasiswa.ID,
+		StudyProgramID:     curriculum.StudyProgramID,
+		CurriculumID:       curriculum.ID,
+		CurriculumNama:     curriculum.Nama,
+		CurriculumTahun:    curriculum.Tahun,
+		SemesterKe:         StudentSemester(mahasiswa.Angkatan),
+		MinSKSLulus:        curriculum.MinSKSLulus,
+		MinIPK:             curriculum.MinIPK,
+		RemainingMandatory: []RemainingCourse{},
+		ElectiveGroups:     []ElectiveGroupProgress{},
+		CompletedCourses:   []CompletedCourse{},
+	}
+
+	var totalPoints float64
+	var gradedSKS int
+	for courseID, result := range results {
+		if result.graded {
+			totalPoints += GRADE_POINTS[result.bestGrade] * float64(result.sks)
+			gradedSKS += result.sks
+		}
+		if result.passed() {
+			audit.CompletedSKS += result.sks
+			audit.CompletedCourses = append(audit.CompletedCourses, CompletedCourse{
+				CourseID: courseID,
+				Kode:     result.kode,
+				Nama:     result.nama,
+				SKS:      result.sks,
+				Nilai:    result.bestGrade,
+			})
+		}
+	}
+	sort.Slice(audit.CompletedCourses, func(i, j int) bool {
+		return audit.CompletedCourses[i].Kode < audit.CompletedCourses[j].Kode
+	})
+	if gradedSKS > 0 {
+		audit.IPK = math.Round(totalPoints/float64(gradedSKS)*100) / 100
+	}
+
+	groupSKS := make(map[uuid.UUID]int)
+	for _, cc := range curriculum.Courses {
+		result := results[cc.CourseID]
+		passed := result != nil && result.passed()
+
+		switch cc.Jenis {
+		case CURRICULUM_COURSE_WAJIB:
+			if passed {
+				continue
+			}
+			status := REMAINING_COURSE_NOT_TAKEN
+			if result != nil && result.inProgress {
+				status = REMAINING_COURSE_IN_PROGRESS
+			} else if result != nil {
+				status = REMAINING_COURSE_FAILED
+			}
+			audit.RemainingMandatory = append(audit.RemainingMandatory, RemainingCourse{
+				Course:              cc.Course,
+				SemesterRekomendasi: cc.SemesterRekomendasi,
+				Status:              status,
+			})
+		case CURRICULUM_COURSE_PILIHAN:
+			if !passed {
+				continue
+			}
+			audit.ElectiveSKS += result.sks
+			if cc.ElectiveGroupID != nil {
+				groupSKS[*cc.ElectiveGroupID] += result.sks
+			}
+		}
+	}
+
+	electivesMet := true
+	for _, g := range curriculum.ElectiveGroups {
+		progress := ElectiveGroupProgress{
+			ID:           g.ID,
+			Nama:         g.Nama,
+			MinSKS:       g.MinSKS,
+			CompletedSKS: groupSKS[g.ID],
+			Met:          groupSKS[g.ID] >= g.MinSKS,
+		}
+		electivesMet = electivesMet && progress.Met
+		audit.ElectiveGroups = append(audit.ElectiveGroups, progress)
+	}
+
+	audit.SKSMet = audit.CompletedSKS >= curriculum.MinSKSLulus
+	audit.IPKMet = audit.IPK >= curriculum.MinIPK
+	audit.Eligible = audit.SKSMet && audit.IPKMet && electivesMet && len(audit.RemainingMandatory) == 0
+	return audit
+}
@@ -9,29 +9,94 @@ import (
 	"gorm.io/gorm"
 )
 
+type CreateDosenInput struct {
+	Name           string
+	Email          string
+	Password       string
+	NIDN           string
+	KapasitasPA    int
+	StudyProgramID *uuid.UUID
+}
+
+type UpdateDosenInput struct {
+	Name           *string
+	NIDN           *string
+	KapasitasPA    *int
+	StudyProgramID *uuid.UUID
+}
+
 type DosenManagementService interface {
+	CreateDosen(input CreateDosenInput) (*models.User, error)
 	GetDosenByID(id uuid.UUID) (*models.User, error)
-	GetAllDosen() ([]models.User, error)
-	UpdateDosen(id uuid.UUID, name, nidn *string) (*models.User, error)
+	GetAllDosen(filter repository.DosenFilter, page repository.PageQuery) (*repository.Page[models.User], error)
+	UpdateDosen(id uuid.UUID, input UpdateDosenInput) (*models.User, error)
 }
 
 type dosenManagementService struct {
-	repo repository.DosenRepository
+	repo             repository.DosenRepository
+	studyProgramRepo repository.StudyProgramRepository
 }
 
-func NewDosenManagementService(repo repository.DosenRepository) DosenManagementService {
-	return &dosenManagementService{repo: repo}
+func NewDosenManagementService(repo repository.DosenRepository, studyProgramRepo repository.StudyProgramRepository) DosenManagementService {
+	return &dosenManagementService{repo: repo, studyProgramRepo: studyProgramRepo}
+}
+
+func (s *dosenManagementService) CreateDosen(input CreateDosenInput) (*models.User, error) {
+	// Check if nidn already exists
+	existing, err := s.repo.FindByNIDN(input.NIDN)
+	if err == nil && existing != nil {
+		return nil, errors.New("dosen dengan NIDN tersebut sudah ada")
+	}
+
+	existing, err = s.repo.FindByEmail(input.Email)
+	if err == nil && existing != nil {
+		return nil, errors.New("email sudah digunakan")
+	}
+
+	if input.KapasitasPA < 0 {
+		return nil, errors.New("kapasitas_pa tidak boleh negatif")
+	}
+
+	if input.StudyProgramID != nil {
+		if _, err := s.studyProgramRepo.FindByID(*input.StudyProgramID); err != nil {
+			return nil, errors.New("study program not found")
+		}
+	}
+
+	password, err := hashInitialPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	dosen := &models.User{
+		Name:               input.Name,
+		Email:              input.Email,
+		Password:           password,
+		IsPending:          password == "",
+		MustChangePassword: password != "",
+		Role:               "dosen",
+		NIDN:               input.NIDN,
+		KapasitasPA:        input.KapasitasPA,
+		StudyProgramID:     input.StudyProgramID,
+		IsActive:           true,
+	}
+
+	if err := s.repo.Create(dosen); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(dosen.ID)
 }
 
 func (s *dosenManagementService) GetDosenByID(id uuid.UUID) (*models.User, error) {
 	return s.repo.FindByID(id)
 }
 
-func (s *dosenManagementService) GetAllDosen() ([]models.User, error) {
-	return s.repo.FindAllDosen()
+func (s *dosenManagementService) GetAllDosen(filter repository.DosenFilter, page repository.PageQuery) (*repository.Page[models.User], error) {
+	return s.repo.FindAllDosen(filter, page)
 }
 
-func (s *dosenManagementService) UpdateDosen(id uuid.UUID, name, nidn *string) (*models.User, error) {
+func (s *dosenManagementService) UpdateDosen(id uuid.UUID, input UpdateDosenInput) (*models.User, error) {
 	dosen, err := s.repo.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -40,22 +105,36 @@ func (s *dosenManagementService) UpdateDosen(id uuid.UUID, name, nidn *string) (
 		return nil, err
 	}
 
-	if name != nil {
-		dosen.Name = *name
+	if input.Name != nil {
+		dosen.Name = *input.Name
 	}
 
-	if nidn != nil {
+	if input.NIDN != nil {
 		// Check if new nidn already exists
-		existing, err := s.repo.FindByNIDN(*nidn)
+		existing, err := s.repo.FindByNIDN(*input.NIDN)
 		if err == nil && existing != nil && existing.ID != id {
 			return nil, errors.New("dosen dengan NIDN tersebut sudah ada")
 		}
-		dosen.NIDN = *nidn
+		dosen.NIDN = *input.NIDN
+	}
+
+	if input.KapasitasPA != nil {
+		if *input.KapasitasPA < 0 {
+			return nil, errors.New("kapasitas_pa tidak boleh negatif")
+		}
+		dosen.KapasitasPA = *input.KapasitasPA
+	}
+
+	if input.StudyProgramID != nil {
+		if _, err := s.studyProgramRepo.FindByID(*input.StudyProgramID); err != nil {
+			return nil, errors.New("study program not found")
+		}
+		dosen.StudyProgramID = input.StudyProgramID
 	}
 
 	if err := s.repo.Update(dosen); err != nil {
 		return nil, err
 	}
 
-	return dosen, nil
+	return s.repo.FindByID(id)
 }
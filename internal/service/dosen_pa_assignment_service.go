@@ -0,0 +1,253 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// AdviseeAssignmentResult adalah hasil penugasan mahasiswa ke seorang dosen PA
+type AdviseeAssignmentResult struct {
+	DosenID        uuid.UUID `json:"dosen_id"`
+	Assigned       int       `json:"assigned"`
+	Reassigned     int       `json:"reassigned"`
+	PendingReviews int64     `json:"pending_reviews"`
+	AdviseeCount   int64     `json:"advisee_count"`
+	OverCapacity   bool      `json:"over_capacity"`
+}
+
+// AutoBalanceAssignment adalah jumlah mahasiswa baru yang diberikan ke satu dosen saat auto-balance
+type AutoBalanceAssignment struct {
+	DosenID      uuid.UUID `json:"dosen_id"`
+	Name         string    `json:"name"`
+	Added        int       `json:"added"`
+	AdviseeCount int64     `json:"advisee_count"`
+	KapasitasPA  int       `json:"kapasitas_pa"`
+}
+
+type AutoBalanceResult struct {
+	Assigned    int                     `json:"assigned"`
+	Unassigned  int                     `json:"unassigned"`
+	Assignments []AutoBalanceAssignment `json:"assignments"`
+}
+
+type DosenPAAssignmentService interface {
+	AssignAdvisees(dosenID uuid.UUID, mahasiswaIDs []uuid.UUID) (*AdviseeAssignmentResult, error)
+	GetWorkload() ([]repository.AdviseeCount, int, error)
+	AutoBalance() (*AutoBalanceResult, error)
+}
+
+type dosenPAAssignmentService struct {
+	repo      repository.DosenPAAssignmentRepository
+	dosenRepo repository.DosenRepository
+	notifier  NotificationService
+}
+
+func NewDosenPAAssignmentService(repo repository.DosenPAAssignmentRepository, dosenRepo repository.DosenRepository, notifier NotificationService) DosenPAAssignmentService {
+	return &dosenPAAssignmentService{repo: repo, dosenRepo: dosenRepo, notifier: notifier}
+}
+
+// AssignAdvisees menugaskan (atau memindahkan) mahasiswa ke dosen PA. Review KRS yang masih
+// pending otomatis berpindah ke dosen PA baru karena akses dosen PA selalu dicek dari users.dosen_pa_id.
+func (s *dosenPAAssignmentService) AssignAdvisees(dosenID uuid.UUID, mahasiswaIDs []uuid.UUID) (*AdviseeAssignmentResult, error) {
+	dosen, err := s.dosenRepo.FindByID(dosenID)
+	if err != nil {
+		return nil, errors.New("dosen PA not found")
+	}
+
+	ids := uniqueUUIDs(mahasiswaIDs)
+	if len(ids) == 0 {
+		return nil, errors.New("mahasiswa_ids tidak boleh kosong")
+	}
+
+	students, err := s.repo.FindMahasiswaByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(students) != len(ids) {
+		return nil, fmt.Errorf("mahasiswa tidak ditemukan: %s", strings.Join(missingUserIDs(ids, students), ", "))
+	}
+
+	result := &AdviseeAssignmentResult{DosenID: dosenID}
+	var movedIDs []uuid.UUID
+	for _, student := range students {
+		switch {
+		case student.DosenPAID == nil:
+			result.Assigned++
+		case *student.DosenPAID != dosenID:
+			result.Reassigned++
+		default:
+			continue
+		}
+		movedIDs = append(movedIDs, student.ID)
+	}
+
+	if len(movedIDs) == 0 {
+		return s.withAdviseeCount(result, dosen)
+	}
+
+	if _, err := s.repo.AssignDosenPA(movedIDs, dosenID); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.repo.CountPendingReviews(movedIDs, GetCurrentSemester(),
+		[]string{KRS_ITEM_STATUS_ACTIVE, KRS_ITEM_STATUS_CANCELLATION_REQUEST})
+	if err != nil {
+		return nil, err
+	}
+	result.PendingReviews = pending
+
+	message := fmt.Sprintf("Anda mendapat %d mahasiswa bimbingan baru.", len(movedIDs))
+	if pending > 0 {
+		message += fmt.Sprintf(" Terdapat %d item KRS yang menunggu review Anda.", pending)
+	}
+	s.notify(dosenID, message)
+
+	return s.withAdviseeCount(result, dosen)
+}
+
+// GetWorkload mengembalikan jumlah bimbingan setiap dosen dan jumlah mahasiswa aktif yang belum punya PA
+func (s *dosenPAAssignmentService) GetWorkload() ([]repository.AdviseeCount, int, error) {
+	counts, err := s.repo.CountAdviseesPerDosen()
+	if err != nil {
+		return nil, 0, err
+	}
+	unassigned, err := s.repo.FindUnassignedMahasiswa()
+	if err != nil {
+		return nil, 0, err
+	}
+	return counts, len(unassigned), nil
+}
+
+// AutoBalance membagikan mahasiswa yang belum punya dosen PA ke dosen dengan sisa kapasitas.
+// Setiap mahasiswa diberikan ke dosen dengan rasio bimbingan/kapasitas terendah.
+// Dosen dengan kapasitas_pa = 0 tidak ikut dibagi.
+func (s *dosenPAAssignmentService) AutoBalance() (*AutoBalanceResult, error) {
+	counts, err := s.repo.CountAdviseesPerDosen()
+	if err != nil {
+		return nil, err
+	}
+	unassigned, err := s.repo.FindUnassignedMahasiswa()
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []*AutoBalanceAssignment
+	for _, c := range counts {
+		if c.KapasitasPA <= 0 || c.AdviseeCount >= int64(c.KapasitasPA) {
+			continue
+		}
+		candidates = append(candidates, &AutoBalanceAssignment{
+			DosenID:      c.DosenID,
+			Name:         c.Name,
+			AdviseeCount: c.AdviseeCount,
+			KapasitasPA:  c.KapasitasPA,
+		})
+	}
+
+	assignments := make(map[uuid.UUID][]uuid.UUID)
+	result := &AutoBalanceResult{Assignments: []AutoBalanceAssignment{}}
+
+	for _, student := range unassigned {
+		var best *AutoBalanceAssignment
+		for _, c := range candidates {
+			if c.AdviseeCount >= int64(c.KapasitasPA) {
+				continue
+			}
+			if best == nil || loadRatio(c) < loadRatio(best) {
+				best = c
+			}
+		}
+		if best == nil {
+			break
+		}
+
+		assignments[best.DosenID] = append(assignments[best.DosenID], student.ID)
+		best.AdviseeCount++
+		best.Added++
+		result.Assigned++
+	}
+	result.Unassigned = len(unassigned) - result.Assigned
+
+	if result.Assigned == 0 {
+		return result, nil
+	}
+
+	if err := s.repo.AssignBatch(assignments); err != nil {
+		return nil, err
+	}
+
+	for _, c := range candidates {
+		if c.Added == 0 {
+			continue
+		}
+		result.Assignments = append(result.Assignments, *c)
+		s.notify(c.DosenID, fmt.Sprintf("Anda mendapat %d mahasiswa bimbingan baru dari pembagian otomatis.", c.Added))
+	}
+	sort.Slice(result.Assignments, func(i, j int) bool {
+		return result.Assignments[i].Name < result.Assignments[j].Name
+	})
+
+	return result, nil
+}
+
+func (s *dosenPAAssignmentService) withAdviseeCount(result *AdviseeAssignmentResult, dosen *models.User) (*AdviseeAssignmentResult, error) {
+	counts, err := s.repo.CountAdviseesPerDosen()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		if c.DosenID == dosen.ID {
+			result.AdviseeCount = c.AdviseeCount
+			break
+		}
+	}
+	result.OverCapacity = dosen.KapasitasPA > 0 && result.AdviseeCount > int64(dosen.KapasitasPA)
+	return result, nil
+}
+
+func (s *dosenPAAssignmentService) notify(dosenID uuid.UUID, message string) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.Notify([]uuid.UUID{dosenID}, NOTIFICATION_TYPE_ADVISEE_ASSIGNED, "Mahasiswa bimbingan baru", message); err != nil {
+		log.Printf("failed to notify dosen PA %s: %v", dosenID, err)
+	}
+}
+
+func loadRatio(c *AutoBalanceAssignment) float64 {
+	return float64(c.AdviseeCount) / float64(c.KapasitasPA)
+}
+
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	result := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+func missingUserIDs(ids []uuid.UUID, found []models.User) []string {
+	foundSet := make(map[uuid.UUID]bool, len(found))
+	for _, u := range found {
+		foundSet[u.ID] = true
+	}
+	var missing []string
+	for _, id := range ids {
+		if !foundSet[id] {
+			missing = append(missing, id.String())
+		}
+	}
+	return missing
+}
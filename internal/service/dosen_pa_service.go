@@ -20,8 +20,8 @@ func NewDosenPAService(repo *repository.KRSRepository) *DosenPAService {
 }
 
 // ListMahasiswa mengembalikan daftar mahasiswa bimbingan dosen PA
-func (s *DosenPAService) ListMahasiswa(dosenID uuid.UUID) ([]models.User, error) {
-	return s.Repo.ListMahasiswaByDosenPA(dosenID)
+func (s *DosenPAService) ListMahasiswa(dosenID uuid.UUID, filter repository.MahasiswaFilter, page repository.PageQuery) (*repository.Page[models.User], error) {
+	return s.Repo.ListMahasiswaByDosenPA(dosenID, filter, page)
 }
 
 // GetMahasiswaKRS mengambil KRS mahasiswa bimbingan di semester berjalan
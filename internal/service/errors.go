@@ -0,0 +1,25 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DependencyError dikembalikan saat sebuah data tidak bisa dihapus karena
+// masih direferensikan data lain. Dependents berisi jumlah per jenis data.
+type DependencyError struct {
+	Entity     string
+	Dependents map[string]int64
+}
+
+func (e *DependencyError) Error() string {
+	var parts []string
+	for name, count := range e.Dependents {
+		if count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", count, name))
+		}
+	}
+	sort.Strings(parts)
+	return fmt.Sprintf("%s masih digunakan oleh %s", e.Entity, strings.Join(parts, ", "))
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"course-planner-api/internal/export"
+	"course-planner-api/internal/repository"
+	"io"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// ExportService menulis data export langsung ke io.Writer baris per baris
+type ExportService interface {
+	ExportClassRoster(w io.Writer, format string, classID uuid.UUID) error
+	ExportKRSItems(w io.Writer, format string, filter repository.KRSItemExportFilter) error
+	ExportRoomSchedules(w io.Writer, format string, filter repository.ScheduleExportFilter) error
+	ExportTeachingList(w io.Writer, format string, filter repository.ScheduleExportFilter) error
+}
+
+type exportService struct {
+	repo repository.ExportRepository
+}
+
+func NewExportService(repo repository.ExportRepository) ExportService {
+	return &exportService{repo: repo}
+}
+
+func (s *exportService) ExportClassRoster(w io.Writer, format string, classID uuid.UUID) error {
+	return writeExport(w, format, "Roster",
+		[]string{"NIM", "Nama", "Email", "Angkatan", "Status", "Dosen PA"},
+		func(out export.Writer) error {
+			excluded := []string{KRS_ITEM_STATUS_CANCELLED, KRS_ITEM_STATUS_REJECTED}
+			return s.repo.StreamClassRoster(classID, excluded, func(row repository.RosterExportRow) error {
+				return out.Write([]string{row.NIM, row.Name, row.Email, formatAngkatan(row.Angkatan), row.Status, row.DosenPAName})
+			})
+		})
+}
+
+func (s *exportService) ExportKRSItems(w io.Writer, format string, filter repository.KRSItemExportFilter) error {
+	return writeExport(w, format, "KRS "+filter.Semester,
+		[]string{"Semester", "NIM", "Nama Mahasiswa", "Kode MK", "Mata Kuliah", "SKS", "Kelas", "Status Item", "Status KRS", "Dosen PA", "NIDN Dosen PA"},
+		func(out export.Writer) error {
+			return s.repo.StreamKRSItems(filter, func(row repository.KRSItemExportRow) error {
+				return out.Write([]string{
+					row.Semester, row.NIM, row.MahasiswaName, row.CourseKode, row.CourseNama, strconv.Itoa(row.SKS),
+					row.NamaKelas, row.ItemStatus, row.KRSStatus, row.DosenPAName, row.DosenPANIDN,
+				})
+			})
+		})
+}
+
+func (s *exportService) ExportRoomSchedules(w io.Writer, format string, filter repository.ScheduleExportFilter) error {
+	return writeExport(w, format, "Jadwal Ruangan",
+		[]string{"Ruangan", "Hari", "Jam Mulai", "Jam Selesai", "Kode MK", "Mata Kuliah", "Kelas", "Dosen", "Semester"},
+		func(out export.Writer) error {
+			return s.repo.StreamRoomSchedules(filter, func(row repository.ScheduleExportRow) error {
+				return out.Write([]string{
+					row.RoomNama, row.Hari, row.JamMulai.Format("15:04"), row.JamSelesai.Format("15:04"),
+					row.CourseKode, row.CourseNama, row.NamaKelas, row.DosenName, row.SemesterPenawaran,
+				})
+			})
+		})
+}
+
+func (s *exportService) ExportTeachingList(w io.Writer, format string, filter repository.ScheduleExportFilter) error {
+	return writeExport(w, format, "Daftar Mengajar",
+		[]string{"Dosen", "NIDN", "Peran", "Porsi SKS", "Kode MK", "Mata Kuliah", "SKS", "Kelas", "Hari", "Jam Mulai", "Jam Selesai", "Ruangan", "Semester"},
+		func(out export.Writer) error {
+			return s.repo.StreamTeachingList(filter, func(row repository.TeachingExportRow) error {
+				share := ""
+				if row.SKSShare != nil {
+					share = strconv.FormatFloat(*row.SKSShare, 'f', -1, 64)
+				}
+				return out.Write([]string{
+					row.DosenName, row.NIDN, row.Role, share, row.CourseKode, row.CourseNama, strconv.Itoa(row.SKS),
+					row.NamaKelas, row.Hari, row.JamMulai.Format("15:04"), row.JamSelesai.Format("15:04"),
+					row.RoomNama, row.SemesterPenawaran,
+				})
+			})
+		})
+}
+
+// writeExport menulis header lalu baris-baris dari stream, kemudian menutup writer
+func writeExport(w io.Writer, format, sheetName string, header []string, stream func(out export.Writer) error) error {
+	out, err := export.NewWriter(format, w, sheetName)
+	if err != nil {
+		return err
+	}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	if err := stream(out); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func formatAngkatan(angkatan int) string {
+	if angkatan == 0 {
+		return ""
+	}
+	return strconv.Itoa(angkatan)
+}
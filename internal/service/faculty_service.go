@@ -0,0 +1,102 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FacultyService interface {
+	CreateFaculty(kode, nama string) (*models.Faculty, error)
+	GetFacultyByID(id uuid.UUID) (*models.Faculty, error)
+	GetAllFaculties() ([]models.Faculty, error)
+	UpdateFaculty(id uuid.UUID, kode, nama *string) (*models.Faculty, error)
+	DeleteFaculty(id uuid.UUID) error
+}
+
+type facultyService struct {
+	repo repository.FacultyRepository
+}
+
+func NewFacultyService(repo repository.FacultyRepository) FacultyService {
+	return &facultyService{repo: repo}
+}
+
+func (s *facultyService) CreateFaculty(kode, nama string) (*models.Faculty, error) {
+	existing, err := s.repo.FindByKode(kode)
+	if err == nil && existing != nil {
+		return nil, errors.New("fakultas dengan kode tersebut sudah ada")
+	}
+
+	faculty := &models.Faculty{
+		Kode: kode,
+		Nama: nama,
+	}
+
+	if err := s.repo.Create(faculty); err != nil {
+		return nil, err
+	}
+
+	return faculty, nil
+}
+
+func (s *facultyService) GetFacultyByID(id uuid.UUID) (*models.Faculty, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *facultyService) GetAllFaculties() ([]models.Faculty, error) {
+	return s.repo.FindAll()
+}
+
+func (s *facultyService) UpdateFaculty(id uuid.UUID, kode, nama *string) (*models.Faculty, error) {
+	faculty, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("faculty not found")
+		}
+		return nil, err
+	}
+
+	if kode != nil {
+		existing, err := s.repo.FindByKode(*kode)
+		if err == nil && existing != nil && existing.ID != id {
+			return nil, errors.New("fakultas dengan kode tersebut sudah ada")
+		}
+		faculty.Kode = *kode
+	}
+
+	if nama != nil {
+		faculty.Nama = *nama
+	}
+
+	if err := s.repo.Update(faculty); err != nil {
+		return nil, err
+	}
+
+	return faculty, nil
+}
+
+func (s *facultyService) DeleteFaculty(id uuid.UUID) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("faculty not found")
+		}
+		return err
+	}
+
+	programCount, err := s.repo.CountStudyPrograms(id)
+	if err != nil {
+		return err
+	}
+	if programCount > 0 {
+		return &DependencyError{
+			Entity:     "faculty",
+			Dependents: map[string]int64{"study_programs": programCount},
+		}
+	}
+
+	return s.repo.Delete(id)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"course-planner-api/internal/repository"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	MAX_IPK = 4.0
+	// MIN_PASSING_GRADE_POINT adalah bobot minimal nilai huruf agar course dianggap lulus (D)
+	MIN_PASSING_GRADE_POINT = 1.0
+)
+
+// GRADE_POINTS memetakan nilai huruf ke bobotnya
+var GRADE_POINTS = map[string]float64{
+	"A":  4,
+	"AB": 3.5,
+	"B":  3,
+	"BC": 2.5,
+	"C":  2,
+	"D":  1,
+	"E":  0,
+}
+
+// GRADEABLE_ITEM_STATUSES adalah status item KRS yang boleh diberi nilai
+var GRADEABLE_ITEM_STATUSES = []string{KRS_ITEM_STATUS_ACTIVE, KRS_ITEM_STATUS_APPROVED}
+
+type GradeInput struct {
+	MahasiswaID uuid.UUID
+	Nilai       string
+}
+
+type GradeService interface {
+	ListClassGrades(classID uuid.UUID) ([]repository.ClassGrade, error)
+	SubmitGrades(classID uuid.UUID, grades []GradeInput) ([]repository.ClassGrade, error)
+	IsClassLecturer(classID, dosenID uuid.UUID) (bool, error)
+}
+
+type gradeService struct {
+	repo repository.GradeRepository
+}
+
+func NewGradeService(repo repository.GradeRepository) GradeService {
+	return &gradeService{repo: repo}
+}
+
+func (s *gradeService) ListClassGrades(classID uuid.UUID) ([]repository.ClassGrade, error) {
+	return s.repo.FindClassGrades(classID, GRADEABLE_ITEM_STATUSES)
+}
+
+// SubmitGrades menyimpan nilai beberapa mahasiswa sekaligus. Semua mahasiswa harus peserta kelas.
+func (s *gradeService) SubmitGrades(classID uuid.UUID, grades []GradeInput) ([]repository.ClassGrade, error) {
+	if len(grades) == 0 {
+		return nil, errors.New("grades must not be empty")
+	}
+
+	participants, err := s.repo.FindClassGrades(classID, GRADEABLE_ITEM_STATUSES)
+	if err != nil {
+		return nil, err
+	}
+	enrolled := make(map[uuid.UUID]bool, len(participants))
+	for _, p := range participants {
+		enrolled[p.MahasiswaID] = true
+	}
+
+	values := make(map[uuid.UUID]string, len(grades))
+	for _, g := range grades {
+		if _, ok := GRADE_POINTS[g.Nilai]; !ok {
+			return nil, fmt.Errorf("invalid nilai %q (allowed: A, AB, B, BC, C, D, E)", g.Nilai)
+		}
+		if !enrolled[g.MahasiswaID] {
+			return nil, fmt.Errorf("mahasiswa %s is not enrolled in this class", g.MahasiswaID)
+		}
+		if _, ok := values[g.MahasiswaID]; ok {
+			return nil, fmt.Errorf("mahasiswa %s listed more than once", g.MahasiswaID)
+		}
+		values[g.MahasiswaID] = g.Nilai
+	}
+
+	if err := s.repo.SetGrades(classID, values, GRADEABLE_ITEM_STATUSES); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("some mahasiswa are no longer enrolled in this class")
+		}
+		return nil, err
+	}
+	return s.repo.FindClassGrades(classID, GRADEABLE_ITEM_STATUSES)
+}
+
+func (s *gradeService) IsClassLecturer(classID, dosenID uuid.UUID) (bool, error) {
+	return s.repo.IsClassLecturer(classID, dosenID)
+}
@@ -0,0 +1,434 @@
+package service
+
+import (
+	"course-planner-api/internal/repository"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	IMPORT_ENTITY_COURSES   = "courses"
+	IMPORT_ENTITY_ROOMS     = "rooms"
+	IMPORT_ENTITY_DOSEN     = "dosen"
+	IMPORT_ENTITY_MAHASISWA = "mahasiswa"
+	IMPORT_ENTITY_CLASSES   = "classes"
+)
+
+var (
+	ErrUnknownImportEntity = errors.New("unknown import entity")
+	ErrInvalidImportFile   = errors.New("invalid import file")
+
+	// errImportRollback dipakai untuk membatalkan transaksi pada dry-run atau jika ada baris gagal
+	errImportRollback = errors.New("import rolled back")
+)
+
+// ImportRowError adalah error validasi untuk satu baris CSV.
+// Row mengikuti nomor baris di file (header = baris 1).
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+type ImportReport struct {
+	Entity    string           `json:"entity"`
+	DryRun    bool             `json:"dry_run"`
+	TotalRows int              `json:"total_rows"`
+	ValidRows int              `json:"valid_rows"`
+	Applied   bool             `json:"applied"`
+	Errors    []ImportRowError `json:"errors"`
+}
+
+type ImportService interface {
+	Import(entity string, file io.Reader, dryRun bool) (*ImportReport, error)
+}
+
+type importService struct {
+	db       *gorm.DB
+	notifier NotificationService
+}
+
+func NewImportService(db *gorm.DB, notifier NotificationService) ImportService {
+	return &importService{db: db, notifier: notifier}
+}
+
+// importRow adalah satu baris CSV yang sudah dipetakan ke nama kolom header
+type importRow struct {
+	line   int
+	values map[string]string
+}
+
+func (r importRow) get(column string) string {
+	return strings.TrimSpace(r.values[column])
+}
+
+// importSpec mendefinisikan kolom wajib (di header dan di setiap baris) dan cara menyimpan satu baris untuk sebuah entity.
+// build dipanggil dengan koneksi transaksi sehingga aturan validasi service yang sama
+// (termasuk cek duplikat / bentrok jadwal antar baris di file yang sama) ikut berlaku.
+type importSpec struct {
+	required []string
+	build    func(tx *gorm.DB, notifier NotificationService) func(row importRow) error
+}
+
+var importSpecs = map[string]importSpec{
+	IMPORT_ENTITY_COURSES: {
+		required: []string{"kode", "nama", "sks"},
+		build:    buildCourseImporter,
+	},
+	IMPORT_ENTITY_ROOMS: {
+		required: []string{"nama"},
+		build:    buildRoomImporter,
+	},
+	IMPORT_ENTITY_DOSEN: {
+		required: []string{"name", "email", "nidn"}, // tanpa password: akun pending, aktivasi lewat undangan
+		build:    buildDosenImporter,
+	},
+	IMPORT_ENTITY_MAHASISWA: {
+		required: []string{"name", "email", "nim"}, // tanpa password: akun pending, aktivasi lewat undangan / registrasi NIM
+		build:    buildMahasiswaImporter,
+	},
+	IMPORT_ENTITY_CLASSES: {
+		required: []string{"course_kode", "dosen_nidn", "nama_kelas", "hari", "jam_mulai", "jam_selesai", "room_nama", "kuota", "semester_penawaran"},
+		build:    buildClassImporter,
+	},
+}
+
+// Import memvalidasi seluruh baris CSV lalu menyimpannya dalam satu transaksi.
+// Jika dryRun bernilai true atau ada baris yang gagal, transaksi di-rollback dan
+// hanya laporan per baris yang dikembalikan.
+func (s *importService) Import(entity string, file io.Reader, dryRun bool) (*ImportReport, error) {
+	spec, ok := importSpecs[entity]
+	if !ok {
+		return nil, ErrUnknownImportEntity
+	}
+
+	rows, err := readImportCSV(file, spec.required)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		Entity:    entity,
+		DryRun:    dryRun,
+		TotalRows: len(rows),
+		Errors:    []ImportRowError{},
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		apply := spec.build(tx, s.notifier)
+		for _, row := range rows {
+			// savepoint per baris supaya error database tidak membatalkan baris berikutnya
+			if err := tx.SavePoint("import_row").Error; err != nil {
+				return err
+			}
+			err := requireColumns(row, spec.required...)
+			if err == nil {
+				err = apply(row)
+			}
+			if err != nil {
+				if rbErr := tx.RollbackTo("import_row").Error; rbErr != nil {
+					return rbErr
+				}
+				report.Errors = append(report.Errors, ImportRowError{Row: row.line, Error: err.Error()})
+				continue
+			}
+			report.ValidRows++
+		}
+
+		if dryRun || len(report.Errors) > 0 {
+			return errImportRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRollback) {
+		return nil, err
+	}
+
+	report.Applied = err == nil
+	return report, nil
+}
+
+func readImportCSV(file io.Reader, required []string) ([]importRow, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: file is empty", ErrInvalidImportFile)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+	}
+
+	columns := make([]string, len(header))
+	present := make(map[string]bool, len(header))
+	for i, h := range header {
+		// buang BOM dari file CSV hasil export Excel
+		col := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		columns[i] = col
+		present[col] = true
+	}
+
+	var missing []string
+	for _, col := range required {
+		if !present[col] {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: missing columns %s", ErrInvalidImportFile, strings.Join(missing, ", "))
+	}
+
+	var rows []importRow
+	line := 1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+		}
+
+		values := make(map[string]string, len(columns))
+		empty := true
+		for i, col := range columns {
+			if i < len(record) {
+				values[col] = record[i]
+				if strings.TrimSpace(record[i]) != "" {
+					empty = false
+				}
+			}
+		}
+		if empty {
+			continue
+		}
+		rows = append(rows, importRow{line: line, values: values})
+	}
+
+	return rows, nil
+}
+
+func requireColumns(row importRow, columns ...string) error {
+	var missing []string
+	for _, col := range columns {
+		if row.get(col) == "" {
+			missing = append(missing, col)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s wajib diisi", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func parseImportInt(row importRow, column string) (int, error) {
+	value := row.get(column)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s harus berupa angka", column)
+	}
+	return n, nil
+}
+
+// prerequisite_kode boleh berisi beberapa kode dipisah ";" dan harus merujuk course yang sudah ada
+// (termasuk baris sebelumnya di file yang sama).
+func buildCourseImporter(tx *gorm.DB, _ NotificationService) func(row importRow) error {
+	studyProgramRepo := repository.NewStudyProgramRepository(tx)
+	courseRepo := repository.NewCourseRepository(tx)
+	courseService := NewCourseService(courseRepo, studyProgramRepo)
+
+	return func(row importRow) error {
+		sks, err := parseImportInt(row, "sks")
+		if err != nil {
+			return err
+		}
+		if sks <= 0 {
+			return errors.New("sks harus lebih dari 0")
+		}
+		studyProgramID, err := lookupStudyProgram(studyProgramRepo, row)
+		if err != nil {
+			return err
+		}
+		var prerequisiteIDs []uuid.UUID
+		for _, kode := range strings.Split(row.get("prerequisite_kode"), ";") {
+			kode = strings.TrimSpace(kode)
+			if kode == "" {
+				continue
+			}
+			prerequisite, err := courseRepo.FindByKode(kode)
+			if err != nil {
+				return fmt.Errorf("prasyarat dengan kode %s tidak ditemukan", kode)
+			}
+			prerequisiteIDs = append(prerequisiteIDs, prerequisite.ID)
+		}
+		_, err = courseService.CreateCourse(CreateCourseInput{
+			Kode:            row.get("kode"),
+			Nama:            row.get("nama"),
+			SKS:             sks,
+			Deskripsi:       row.get("deskripsi"),
+			StudyProgramID:  studyProgramID,
+			PrerequisiteIDs: prerequisiteIDs,
+		})
+		return err
+	}
+}
+
+func buildRoomImporter(tx *gorm.DB, _ NotificationService) func(row importRow) error {
+	roomService := NewRoomService(repository.NewRoomRepository(tx))
+
+	return func(row importRow) error {
+		_, err := roomService.CreateRoom(row.get("nama"))
+		return err
+	}
+}
+
+func buildDosenImporter(tx *gorm.DB, _ NotificationService) func(row importRow) error {
+	studyProgramRepo := repository.NewStudyProgramRepository(tx)
+	dosenService := NewDosenManagementService(repository.NewDosenRepository(tx), studyProgramRepo)
+
+	return func(row importRow) error {
+		kapasitasPA, err := parseImportInt(row, "kapasitas_pa")
+		if err != nil {
+			return err
+		}
+		studyProgramID, err := lookupStudyProgram(studyProgramRepo, row)
+		if err != nil {
+			return err
+		}
+		_, err = dosenService.CreateDosen(CreateDosenInput{
+			Name:           row.get("name"),
+			Email:          row.get("email"),
+			Password:       row.get("password"),
+			NIDN:           row.get("nidn"),
+			KapasitasPA:    kapasitasPA,
+			StudyProgramID: studyProgramID,
+		})
+		return err
+	}
+}
+
+func buildMahasiswaImporter(tx *gorm.DB, _ NotificationService) func(row importRow) error {
+	studyProgramRepo := repository.NewStudyProgramRepository(tx)
+	dosenRepo := repository.NewDosenRepository(tx)
+	mahasiswaService := NewMahasiswaManagementService(repository.NewMahasiswaRepository(tx), dosenRepo, studyProgramRepo, repository.NewSessionRepository(tx))
+
+	return func(row importRow) error {
+		angkatan, err := parseImportInt(row, "angkatan")
+		if err != nil {
+			return err
+		}
+		studyProgramID, err := lookupStudyProgram(studyProgramRepo, row)
+		if err != nil {
+			return err
+		}
+
+		input := CreateMahasiswaInput{
+			Name:           row.get("name"),
+			Email:          row.get("email"),
+			Password:       row.get("password"),
+			NIM:            row.get("nim"),
+			Angkatan:       angkatan,
+			StudyProgramID: studyProgramID,
+		}
+		if nidn := row.get("dosen_pa_nidn"); nidn != "" {
+			dosen, err := dosenRepo.FindByNIDN(nidn)
+			if err != nil {
+				return fmt.Errorf("dosen PA dengan NIDN %s tidak ditemukan", nidn)
+			}
+			input.DosenPAID = &dosen.ID
+		}
+
+		_, err = mahasiswaService.CreateMahasiswa(input)
+		return err
+	}
+}
+
+// buildClassImporter mereferensikan course, dosen dan ruangan lewat kode / NIDN / nama.
+// dosen_nidn boleh berisi beberapa NIDN dipisah ";" untuk team teaching; NIDN pertama menjadi koordinator.
+func buildClassImporter(tx *gorm.DB, notifier NotificationService) func(row importRow) error {
+	courseRepo := repository.NewCourseRepository(tx)
+	dosenRepo := repository.NewDosenRepository(tx)
+	roomRepo := repository.NewRoomRepository(tx)
+	classService := NewClassService(repository.NewClassRepository(tx), courseRepo, notifier)
+
+	return func(row importRow) error {
+
+		course, err := courseRepo.FindByKode(row.get("course_kode"))
+		if err != nil {
+			return fmt.Errorf("course dengan kode %s tidak ditemukan", row.get("course_kode"))
+		}
+		room, err := roomRepo.FindByNama(row.get("room_nama"))
+		if err != nil {
+			return fmt.Errorf("room %s tidak ditemukan", row.get("room_nama"))
+		}
+
+		var dosens []ClassDosenInput
+		for i, nidn := range strings.Split(row.get("dosen_nidn"), ";") {
+			nidn = strings.TrimSpace(nidn)
+			if nidn == "" {
+				continue
+			}
+			dosen, err := dosenRepo.FindByNIDN(nidn)
+			if err != nil {
+				return fmt.Errorf("dosen dengan NIDN %s tidak ditemukan", nidn)
+			}
+			role := CLASS_DOSEN_ROLE_MEMBER
+			if i == 0 {
+				role = CLASS_DOSEN_ROLE_COORDINATOR
+			}
+			dosens = append(dosens, ClassDosenInput{DosenID: dosen.ID, Role: role})
+		}
+
+		jamMulai, err := time.Parse("15:04", row.get("jam_mulai"))
+		if err != nil {
+			return errors.New("jam_mulai harus berformat HH:MM")
+		}
+		jamSelesai, err := time.Parse("15:04", row.get("jam_selesai"))
+		if err != nil {
+			return errors.New("jam_selesai harus berformat HH:MM")
+		}
+		kuota, err := parseImportInt(row, "kuota")
+		if err != nil {
+			return err
+		}
+
+		_, err = classService.CreateClass(CreateClassInput{
+			CourseID:          course.ID,
+			Dosens:            dosens,
+			NamaKelas:         row.get("nama_kelas"),
+			Hari:              row.get("hari"),
+			JamMulai:          jamMulai,
+			JamSelesai:        jamSelesai,
+			RoomID:            room.ID,
+			Kuota:             kuota,
+			SemesterPenawaran: row.get("semester_penawaran"),
+		})
+		return err
+	}
+}
+
+// lookupStudyProgram mencari prodi dari kolom opsional study_program_kode
+func lookupStudyProgram(repo repository.StudyProgramRepository, row importRow) (*uuid.UUID, error) {
+	kode := row.get("study_program_kode")
+	if kode == "" {
+		return nil, nil
+	}
+	program, err := repo.FindByKode(kode)
+	if err != nil {
+		return nil, fmt.Errorf("study program dengan kode %s tidak ditemukan", kode)
+	}
+	return &program.ID, nil
+}
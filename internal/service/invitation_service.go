@@ -0,0 +1,270 @@
+package service
+
+import (
+	"course-planner-api/config"
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidInvitation = errors.New("invitation token is invalid, expired, or already used")
+
+// CreateInvitationInput membuat akun pending baru dengan role tertentu, atau (UserID diisi)
+// mengundang akun pending yang sudah ada, misalnya mahasiswa hasil import tanpa password.
+// Untuk akun yang sudah ada, StudyProgramID (jika diisi) membatasi prodi akun yang boleh diundang.
+type CreateInvitationInput struct {
+	UserID         *uuid.UUID
+	Role           string
+	Name           string
+	Email          string
+	NIM            string
+	NIDN           string
+	Angkatan       int
+	KapasitasPA    int
+	StudyProgramID *uuid.UUID
+	DosenPAID      *uuid.UUID
+	CreatedByID    *uuid.UUID
+}
+
+// IssuedInvitation berisi token undangan dalam bentuk asli. Token hanya ditampilkan sekali.
+type IssuedInvitation struct {
+	Invitation *models.UserInvitation `json:"invitation"`
+	Token      string                 `json:"token"`
+}
+
+type InvitationService interface {
+	CreateInvitation(input CreateInvitationInput) (*IssuedInvitation, error)
+	GetInvitationByID(id uuid.UUID) (*models.UserInvitation, error)
+	ListInvitations(filter repository.InvitationFilter) ([]models.UserInvitation, error)
+	ResendInvitation(id uuid.UUID) (*IssuedInvitation, error)
+	RevokeInvitation(id uuid.UUID) error
+	AcceptInvitation(token, password string) (*models.User, error)
+}
+
+type invitationService struct {
+	db   *gorm.DB
+	repo repository.InvitationRepository
+	cfg  config.AuthConfig
+}
+
+func NewInvitationService(db *gorm.DB, repo repository.InvitationRepository, cfg config.AuthConfig) InvitationService {
+	return &invitationService{db: db, repo: repo, cfg: cfg}
+}
+
+// newOneTimeToken membuat token acak (hex) beserta hash SHA-256 yang disimpan di database
+func newOneTimeToken() (token string, tokenHash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateInvitation membuat akun pending (jika belum ada) dan undangannya dalam satu transaksi
+func (s *invitationService) CreateInvitation(input CreateInvitationInput) (*IssuedInvitation, error) {
+	token, tokenHash, err := newOneTimeToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var invitationID uuid.UUID
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		userID, err := s.pendingUser(tx, input)
+		if err != nil {
+			return err
+		}
+
+		invitation := &models.UserInvitation{
+			UserID:      userID,
+			TokenHash:   tokenHash,
+			ExpiresAt:   time.Now().Add(s.cfg.InvitationTTL),
+			CreatedByID: input.CreatedByID,
+		}
+		if err := repository.NewInvitationRepository(tx).Create(invitation); err != nil {
+			return err
+		}
+		invitationID = invitation.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.repo.FindByID(invitationID)
+	if err != nil {
+		return nil, err
+	}
+	return &IssuedInvitation{Invitation: invitation, Token: token}, nil
+}
+
+// pendingUser mengembalikan akun pending yang diundang; membuat akun baru lewat service manajemen
+// dosen / mahasiswa agar validasi NIM, NIDN, email dan prodi sama dengan pembuatan akun biasa
+func (s *invitationService) pendingUser(tx *gorm.DB, input CreateInvitationInput) (uuid.UUID, error) {
+	if input.UserID != nil {
+		var user models.User
+		if err := tx.First(&user, "id = ?", *input.UserID).Error; err != nil {
+			return uuid.Nil, errors.New("user not found")
+		}
+		if input.StudyProgramID != nil && (user.StudyProgramID == nil || *user.StudyProgramID != *input.StudyProgramID) {
+			return uuid.Nil, errors.New("user not found")
+		}
+		if !user.IsPending {
+			return uuid.Nil, errors.New("user is already activated")
+		}
+		return user.ID, nil
+	}
+
+	if input.Name == "" || input.Email == "" {
+		return uuid.Nil, errors.New("name and email are required")
+	}
+
+	studyProgramRepo := repository.NewStudyProgramRepository(tx)
+	dosenRepo := repository.NewDosenRepository(tx)
+
+	switch input.Role {
+	case ROLE_MAHASISWA:
+		if input.NIM == "" {
+			return uuid.Nil, errors.New("nim is required for mahasiswa")
+		}
+		user, err := NewMahasiswaManagementService(repository.NewMahasiswaRepository(tx), dosenRepo, studyProgramRepo, repository.NewSessionRepository(tx)).CreateMahasiswa(CreateMahasiswaInput{
+			Name:           input.Name,
+			Email:          input.Email,
+			NIM:            input.NIM,
+			Angkatan:       input.Angkatan,
+			StudyProgramID: input.StudyProgramID,
+			DosenPAID:      input.DosenPAID,
+		})
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return user.ID, nil
+	case ROLE_DOSEN:
+		if input.NIDN == "" {
+			return uuid.Nil, errors.New("nidn is required for dosen")
+		}
+		user, err := NewDosenManagementService(dosenRepo, studyProgramRepo).CreateDosen(CreateDosenInput{
+			Name:           input.Name,
+			Email:          input.Email,
+			NIDN:           input.NIDN,
+			KapasitasPA:    input.KapasitasPA,
+			StudyProgramID: input.StudyProgramID,
+		})
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return user.ID, nil
+	case ROLE_ADMIN:
+		userRepo := repository.NewUserRepository(tx)
+		if existing, err := userRepo.FindByEmail(input.Email); err == nil && existing != nil {
+			return uuid.Nil, errors.New("email sudah digunakan")
+		}
+		if input.StudyProgramID != nil {
+			if _, err := studyProgramRepo.FindByID(*input.StudyProgramID); err != nil {
+				return uuid.Nil, errors.New("study program not found")
+			}
+		}
+		user := &models.User{
+			Name:           input.Name,
+			Email:          input.Email,
+			Role:           ROLE_ADMIN,
+			StudyProgramID: input.StudyProgramID,
+			IsActive:       true,
+			IsPending:      true,
+		}
+		if err := userRepo.Create(user); err != nil {
+			return uuid.Nil, err
+		}
+		return user.ID, nil
+	default:
+		return uuid.Nil, fmt.Errorf("role must be %s, %s, or %s", ROLE_ADMIN, ROLE_DOSEN, ROLE_MAHASISWA)
+	}
+}
+
+func (s *invitationService) GetInvitationByID(id uuid.UUID) (*models.UserInvitation, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *invitationService) ListInvitations(filter repository.InvitationFilter) ([]models.UserInvitation, error) {
+	return s.repo.FindAll(filter)
+}
+
+// ResendInvitation membuat token baru dan memperpanjang masa berlaku; token lama tidak berlaku lagi
+func (s *invitationService) ResendInvitation(id uuid.UUID) (*IssuedInvitation, error) {
+	invitation, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("invitation not found")
+	}
+	if invitation.UsedAt != nil || !invitation.User.IsPending {
+		return nil, errors.New("invitation has already been used")
+	}
+
+	token, tokenHash, err := newOneTimeToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateToken(id, tokenHash, time.Now().Add(s.cfg.InvitationTTL)); err != nil {
+		return nil, err
+	}
+
+	invitation, err = s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return &IssuedInvitation{Invitation: invitation, Token: token}, nil
+}
+
+// RevokeInvitation membatalkan undangan yang belum dipakai. Akun pending tetap ada dan bisa diundang ulang.
+func (s *invitationService) RevokeInvitation(id uuid.UUID) error {
+	invitation, err := s.repo.FindByID(id)
+	if err != nil {
+		return errors.New("invitation not found")
+	}
+	if invitation.UsedAt != nil {
+		return errors.New("invitation has already been used")
+	}
+	return s.repo.Delete(id)
+}
+
+// AcceptInvitation mengaktifkan akun pending dengan token undangan dan password baru
+func (s *invitationService) AcceptInvitation(token, password string) (*models.User, error) {
+	if len(password) < MIN_PASSWORD_LENGTH {
+		return nil, ErrPasswordTooShort
+	}
+
+	invitation, err := s.repo.FindByTokenHash(hashToken(token))
+	if err != nil || invitation.UsedAt != nil || time.Now().After(invitation.ExpiresAt) {
+		return nil, ErrInvalidInvitation
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ActivateUser(invitation.UserID, string(hashed), &invitation.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, err
+	}
+
+	invitation, err = s.repo.FindByID(invitation.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &invitation.User, nil
+}
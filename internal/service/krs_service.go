@@ -19,14 +19,30 @@ const (
 	KRS_ITEM_STATUS_CANCELLED            = "CANCELLED"
 	KRS_ITEM_STATUS_APPROVED             = "APPROVED"
 	KRS_ITEM_STATUS_REJECTED             = "REJECTED"
+
+	SEMESTER_GANJIL = "ganjil"
+	SEMESTER_GENAP  = "genap"
 )
 
+// AvailableClass adalah kelas yang bisa diambil beserta sisa kuota dan statusnya terhadap KRS mahasiswa
+type AvailableClass struct {
+	models.Class
+	Enrolled          int          `json:"enrolled"`
+	RemainingSeats    int          `json:"remaining_seats"`
+	HasClash          bool         `json:"has_clash"`
+	Clashes           []ClassClash `json:"clashes"`
+	CourseInKRS       bool         `json:"course_in_krs"`       // matakuliah sudah diambil di kelas lain semester ini
+	CourseTakenBefore bool         `json:"course_taken_before"` // matakuliah pernah diambil di semester sebelumnya
+	Recommended       bool         `json:"recommended"`         // direkomendasikan kurikulum untuk semester mahasiswa saat ini
+}
+
 type KRSService struct {
-	Repo *repository.KRSRepository
+	Repo              *repository.KRSRepository
+	CurriculumService CurriculumService
 }
 
-func NewKRSService(repo *repository.KRSRepository) *KRSService {
-	return &KRSService{Repo: repo}
+func NewKRSService(repo *repository.KRSRepository, curriculumService CurriculumService) *KRSService {
+	return &KRSService{Repo: repo, CurriculumService: curriculumService}
 }
 
 // GetCurrentSemester otomatis berdasarkan bulan
@@ -34,10 +50,24 @@ func GetCurrentSemester() string {
 	now := time.Now()
 	month := now.Month()
 	if month >= time.January && month <= time.June {
-		return "ganjil"
+		return SEMESTER_GANJIL
 	} else {
-		return "genap"
+		return SEMESTER_GENAP
+	}
+}
+
+// GetCurrentTahunAkademik mengikuti GetCurrentSemester: ganjil dan genap berada di tahun kalender yang sama
+func GetCurrentTahunAkademik() int {
+	return time.Now().Year()
+}
+
+// SemesterOrder mengubah tahun akademik + semester menjadi angka yang bisa dibandingkan (ganjil sebelum genap)
+func SemesterOrder(tahunAkademik int, semester string) int {
+	order := tahunAkademik * 2
+	if semester == SEMESTER_GENAP {
+		order++
 	}
+	return order
 }
 
 // CheckScheduleConflict memeriksa apakah ada jadwal bentrok antara kelas baru dan yang sudah ada
@@ -61,21 +91,110 @@ func CheckScheduleConflict(selected []models.Class, existing []models.KRSItem) e
 }
 
 // ListAvailableClasses: Menampilkan matkul yang tersedia (Req. 1)
-func (s *KRSService) ListAvailableClasses(mahasiswaID uuid.UUID) ([]models.Class, error) {
+// minSeats (opsional) menyaring kelas dengan sisa kuota minimal sebanyak itu.
+// prioritizeRecommended menampilkan kelas dari course rekomendasi kurikulum semester ini lebih dulu.
+func (s *KRSService) ListAvailableClasses(mahasiswaID uuid.UUID, filter repository.ClassFilter, minSeats *int, prioritizeRecommended bool, page repository.PageQuery) (*repository.Page[AvailableClass], error) {
 	semester := GetCurrentSemester()
 	krs, err := s.Repo.GetOrCreateKRS(mahasiswaID, semester, KRS_STATUS_DRAFT, KRS_STATUS_VERIFIED)
 	if err != nil {
 		return nil, err
 	}
 
+	inactive := []string{KRS_ITEM_STATUS_CANCELLED, KRS_ITEM_STATUS_REJECTED}
+
 	var excludedClassIDs []uuid.UUID
+	var activeItems []models.KRSItem
 	for _, item := range krs.Items {
 		if item.Status != KRS_ITEM_STATUS_CANCELLED && item.Status != KRS_ITEM_STATUS_REJECTED {
 			excludedClassIDs = append(excludedClassIDs, item.ClassID)
+			activeItems = append(activeItems, item)
+		}
+	}
+
+	// mahasiswa tanpa kurikulum tetap bisa melihat kelas, hanya tanpa rekomendasi
+	var recommendedIDs []uuid.UUID
+	curriculum, err := s.CurriculumService.GetStudentCurriculum(mahasiswaID)
+	if err == nil {
+		recommendedIDs = curriculum.RecommendedCourseIDs()
+	} else if !errors.Is(err, ErrCurriculumNotFound) {
+		return nil, err
+	}
+	recommended := make(map[uuid.UUID]bool, len(recommendedIDs))
+	for _, id := range recommendedIDs {
+		recommended[id] = true
+	}
+
+	query := repository.AvailableClassQuery{
+		Semester:         semester,
+		ExcludedClassIDs: excludedClassIDs,
+		InactiveStatuses: inactive,
+		MinSeats:         minSeats,
+		Filter:           filter,
+	}
+	if prioritizeRecommended {
+		query.PriorityCourseIDs = recommendedIDs
+	}
+	classes, err := s.Repo.ListAvailableClasses(query, page)
+	if err != nil {
+		return nil, err
+	}
+
+	classIDs := make([]uuid.UUID, 0, len(classes.Data))
+	for _, class := range classes.Data {
+		classIDs = append(classIDs, class.ID)
+	}
+	enrolled, err := s.Repo.CountEnrolled(classIDs, inactive)
+	if err != nil {
+		return nil, err
+	}
+
+	takenBefore, err := s.Repo.FindTakenCourseIDs(mahasiswaID, krs.ID, inactive)
+	if err != nil {
+		return nil, err
+	}
+	takenCourses := make(map[uuid.UUID]bool, len(takenBefore))
+	for _, courseID := range takenBefore {
+		takenCourses[courseID] = true
+	}
+
+	result := &repository.Page[AvailableClass]{
+		Data:   make([]AvailableClass, 0, len(classes.Data)),
+		Total:  classes.Total,
+		Limit:  classes.Limit,
+		Offset: classes.Offset,
+	}
+	for _, class := range classes.Data {
+		available := AvailableClass{
+			Class:             class,
+			Enrolled:          enrolled[class.ID],
+			RemainingSeats:    class.Kuota - enrolled[class.ID],
+			Clashes:           []ClassClash{},
+			CourseTakenBefore: takenCourses[class.CourseID],
+			Recommended:       recommended[class.CourseID],
+		}
+		if available.RemainingSeats < 0 {
+			available.RemainingSeats = 0
+		}
+		for _, item := range activeItems {
+			if item.Class.CourseID == class.CourseID {
+				available.CourseInKRS = true
+			}
+			if isClassOverlap(&class, &item.Class) {
+				available.Clashes = append(available.Clashes, ClassClash{
+					ClassID:    item.ClassID,
+					CourseKode: item.Class.Course.Kode,
+					NamaKelas:  item.Class.NamaKelas,
+					Hari:       item.Class.Hari,
+					JamMulai:   item.Class.JamMulai,
+					JamSelesai: item.Class.JamSelesai,
+				})
+			}
 		}
+		available.HasClash = len(available.Clashes) > 0
+		result.Data = append(result.Data, available)
 	}
 
-	return s.Repo.ListAvailableClasses(semester, excludedClassIDs)
+	return result, nil
 }
 
 // TakeClass: Mahasiswa mengambil 1 atau banyak matkul sekaligus
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CreateMahasiswaInput struct {
+	Name           string
+	Email          string
+	Password       string
+	NIM            string
+	Angkatan       int
+	StudyProgramID *uuid.UUID
+	DosenPAID      *uuid.UUID
+}
+
+type UpdateMahasiswaInput struct {
+	Name           *string
+	Email          *string
+	NIM            *string
+	Angkatan       *int
+	StudyProgramID *uuid.UUID
+}
+
+type MahasiswaManagementService interface {
+	CreateMahasiswa(input CreateMahasiswaInput) (*models.User, error)
+	GetMahasiswaByID(id uuid.UUID) (*models.User, error)
+	GetAllMahasiswa(filter repository.MahasiswaFilter, page repository.PageQuery) (*repository.Page[models.User], error)
+	UpdateMahasiswa(id uuid.UUID, input UpdateMahasiswaInput) (*models.User, error)
+	SetMahasiswaActive(id uuid.UUID, active bool) (*models.User, error)
+}
+
+type mahasiswaManagementService struct {
+	repo             repository.MahasiswaRepository
+	dosenRepo        repository.DosenRepository
+	studyProgramRepo repository.StudyProgramRepository
+	sessionRepo      repository.SessionRepository
+}
+
+func NewMahasiswaManagementService(repo repository.MahasiswaRepository, dosenRepo repository.DosenRepository, studyProgramRepo repository.StudyProgramRepository, sessionRepo repository.SessionRepository) MahasiswaManagementService {
+	return &mahasiswaManagementService{repo: repo, dosenRepo: dosenRepo, studyProgramRepo: studyProgramRepo, sessionRepo: sessionRepo}
+}
+
+func (s *mahasiswaManagementService) CreateMahasiswa(input CreateMahasiswaInput) (*models.User, error) {
+	// Check if nim already exists
+	existing, err := s.repo.FindByNIM(input.NIM)
+	if err == nil && existing != nil {
+		return nil, errors.New("mahasiswa dengan NIM tersebut sudah ada")
+	}
+
+	existing, err = s.repo.FindByEmail(input.Email)
+	if err == nil && existing != nil {
+		return nil, errors.New("email sudah digunakan")
+	}
+
+	if input.DosenPAID != nil {
+		if _, err := s.dosenRepo.FindByID(*input.DosenPAID); err != nil {
+			return nil, errors.New("dosen PA not found")
+		}
+	}
+
+	if input.StudyProgramID != nil {
+		if _, err := s.studyProgramRepo.FindByID(*input.StudyProgramID); err != nil {
+			return nil, errors.New("study program not found")
+		}
+	}
+
+	password, err := hashInitialPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	mahasiswa := &models.User{
+		Name:               input.Name,
+		Email:              input.Email,
+		Password:           password,
+		IsPending:          password == "",
+		MustChangePassword: password != "",
+		Role:               "mahasiswa",
+		NIM:                input.NIM,
+		Angkatan:           input.Angkatan,
+		StudyProgramID:     input.StudyProgramID,
+		DosenPAID:          input.DosenPAID,
+		IsActive:           true,
+	}
+
+	if err := s.repo.Create(mahasiswa); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(mahasiswa.ID)
+}
+
+func (s *mahasiswaManagementService) GetMahasiswaByID(id uuid.UUID) (*models.User, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *mahasiswaManagementService) GetAllMahasiswa(filter repository.MahasiswaFilter, page repository.PageQuery) (*repository.Page[models.User], error) {
+	return s.repo.FindAll(filter, page)
+}
+
+func (s *mahasiswaManagementService) UpdateMahasiswa(id uuid.UUID, input UpdateMahasiswaInput) (*models.User, error) {
+	mahasiswa, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("mahasiswa not found")
+		}
+		return nil, err
+	}
+
+	if input.Name != nil {
+		mahasiswa.Name = *input.Name
+	}
+
+	if input.Email != nil {
+		existing, err := s.repo.FindByEmail(*input.Email)
+		if err == nil && existing != nil && existing.ID != id {
+			return nil, errors.New("email sudah digunakan")
+		}
+		mahasiswa.Email = *input.Email
+	}
+
+	if input.NIM != nil {
+		// Check if new nim already exists
+		existing, err := s.repo.FindByNIM(*input.NIM)
+		if err == nil && existing != nil && existing.ID != id {
+			return nil, errors.New("mahasiswa dengan NIM tersebut sudah ada")
+		}
+		mahasiswa.NIM = *input.NIM
+	}
+
+	if input.Angkatan != nil {
+		mahasiswa.Angkatan = *input.Angkatan
+	}
+
+	if input.StudyProgramID != nil {
+		if _, err := s.studyProgramRepo.FindByID(*input.StudyProgramID); err != nil {
+			return nil, errors.New("study program not found")
+		}
+		mahasiswa.StudyProgramID = input.StudyProgramID
+	}
+
+	if err := s.repo.Update(mahasiswa); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(id)
+}
+
+// SetMahasiswaActive mengaktifkan atau menonaktifkan akun mahasiswa. Akun nonaktif tidak bisa login,
+// dan semua sesinya dicabut sehingga access token dan refresh token yang sudah terbit langsung ditolak.
+func (s *mahasiswaManagementService) SetMahasiswaActive(id uuid.UUID, active bool) (*models.User, error) {
+	if err := s.repo.SetActive(id, active); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("mahasiswa not found")
+		}
+		return nil, err
+	}
+	if !active {
+		if _, err := s.sessionRepo.RevokeAllForUser(id, nil); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.FindByID(id)
+}
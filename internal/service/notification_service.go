@@ -0,0 +1,53 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	NOTIFICATION_TYPE_CLASS_RESCHEDULED = "CLASS_RESCHEDULED"
+	NOTIFICATION_TYPE_CLASS_DELETED     = "CLASS_DELETED"
+	NOTIFICATION_TYPE_ADVISEE_ASSIGNED  = "ADVISEE_ASSIGNED"
+)
+
+type NotificationService interface {
+	Notify(userIDs []uuid.UUID, notifType, title, message string) error
+	ListNotifications(userID uuid.UUID, unreadOnly bool) ([]models.Notification, error)
+	MarkAsRead(id uuid.UUID, userID uuid.UUID) error
+}
+
+type notificationService struct {
+	repo repository.NotificationRepository
+}
+
+func NewNotificationService(repo repository.NotificationRepository) NotificationService {
+	return &notificationService{repo: repo}
+}
+
+// Notify mengirim notifikasi yang sama ke beberapa user sekaligus
+func (s *notificationService) Notify(userIDs []uuid.UUID, notifType, title, message string) error {
+	now := time.Now()
+	notifications := make([]models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		notifications = append(notifications, models.Notification{
+			UserID:    userID,
+			Type:      notifType,
+			Title:     title,
+			Message:   message,
+			CreatedAt: now,
+		})
+	}
+	return s.repo.CreateBatch(notifications)
+}
+
+func (s *notificationService) ListNotifications(userID uuid.UUID, unreadOnly bool) ([]models.Notification, error) {
+	return s.repo.FindByUser(userID, unreadOnly)
+}
+
+func (s *notificationService) MarkAsRead(id uuid.UUID, userID uuid.UUID) error {
+	return s.repo.MarkRead(id, userID)
+}
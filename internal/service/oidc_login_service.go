@@ -0,0 +1,255 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/oidc"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OIDC_STATE_TTL adalah batas waktu menyelesaikan login di halaman IdP
+const OIDC_STATE_TTL = 10 * time.Minute
+
+var (
+	ErrOIDCDisabled = errors.New("SSO login is not configured")
+	// ErrOIDCProviderUnavailable dikembalikan jika discovery IdP gagal (IdP mati / konfigurasi salah)
+	ErrOIDCProviderUnavailable = errors.New("identity provider is unavailable")
+	// ErrInvalidOIDCState dikembalikan untuk state yang tidak dikenal, kedaluwarsa atau sudah dipakai
+	ErrInvalidOIDCState = errors.New("SSO login state is invalid or expired, please start again")
+	// ErrOIDCLoginFailed dikembalikan jika penukaran code atau verifikasi ID token gagal
+	ErrOIDCLoginFailed     = errors.New("SSO login failed")
+	ErrOIDCAccountNotFound = errors.New("no account matches this SSO identity, please contact an admin")
+	// ErrOIDCAccountConflict dikembalikan jika akun yang cocok sudah terhubung ke identitas IdP lain
+	ErrOIDCAccountConflict = errors.New("the matching account is already linked to another SSO identity")
+	ErrOIDCNotLinked       = errors.New("account is not linked to SSO, sign in with SSO once before disabling password login")
+	ErrPasswordNotSet      = errors.New("account has no password, set one with password reset before enabling password login")
+)
+
+// OIDCAuthorization adalah URL halaman login IdP; client mengarahkan user ke sana lalu mengirim
+// code dan state yang diterima di redirect URL ke callback
+type OIDCAuthorization struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+// StartOIDCLogin membuat state, nonce dan code_verifier PKCE baru lalu mengembalikan URL authorize IdP
+func (s *authService) StartOIDCLogin() (*OIDCAuthorization, error) {
+	if s.oidcProvider == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	state, stateHash, err := newOneTimeToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, _, err := newOneTimeToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	authorizationURL, err := s.oidcProvider.AuthCodeURL(state, nonce, verifier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCProviderUnavailable, err)
+	}
+
+	loginState := &models.OIDCLoginState{
+		StateHash:    stateHash,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(OIDC_STATE_TTL),
+	}
+	if err := s.oidcRepo.CreateState(loginState); err != nil {
+		return nil, err
+	}
+
+	return &OIDCAuthorization{
+		AuthorizationURL: authorizationURL,
+		State:            state,
+		ExpiresAt:        loginState.ExpiresAt,
+	}, nil
+}
+
+// LoginWithOIDC menyelesaikan login OIDC: code dari IdP ditukar dengan ID token, lalu identitasnya
+// dipetakan ke akun (subject yang sudah terhubung, email terverifikasi, NIM atau NIDN) atau dibuat
+// baru jika OIDC_AUTO_PROVISION aktif. Akun dengan 2FA tetap mendapat challenge seperti Login.
+func (s *authService) LoginWithOIDC(code, state string, client ClientInfo) (*LoginResult, error) {
+	if s.oidcProvider == nil {
+		return nil, ErrOIDCDisabled
+	}
+
+	now := time.Now()
+	attempt := &models.LoginAttempt{
+		IPAddress: truncate(client.IPAddress, 45),
+		UserAgent: truncate(client.UserAgent, 255),
+		Method:    LOGIN_METHOD_OIDC,
+	}
+
+	if throttled, err := s.ipThrottle(client.IPAddress, now); err != nil {
+		return nil, err
+	} else if throttled != nil {
+		return nil, s.failLogin(attempt, LOGIN_FAILURE_THROTTLED, throttled)
+	}
+
+	loginState, err := s.oidcRepo.ConsumeState(hashToken(state))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidOIDCState
+		}
+		return nil, err
+	}
+	if now.After(loginState.ExpiresAt) {
+		return nil, ErrInvalidOIDCState
+	}
+
+	token, err := s.oidcProvider.Exchange(code, loginState.CodeVerifier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+	claims, err := s.oidcProvider.VerifyIDToken(token.IDToken, loginState.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOIDCLoginFailed, err)
+	}
+	attempt.Email = truncate(claims.Email(), 100)
+
+	user, err := s.resolveOIDCUser(claims)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrOIDCAccountNotFound):
+			return nil, s.failLogin(attempt, LOGIN_FAILURE_OIDC_NOT_LINKED, err)
+		case errors.Is(err, ErrOIDCAccountConflict):
+			return nil, s.failLogin(attempt, LOGIN_FAILURE_OIDC_CONFLICT, err)
+		default:
+			return nil, err
+		}
+	}
+	attempt.UserID = &user.ID
+
+	// Kunci akun hanya melindungi dari tebak password; identitas sudah diverifikasi IdP
+	if !user.IsActive {
+		return nil, s.failLogin(attempt, LOGIN_FAILURE_ACCOUNT_DEACTIVATED, ErrAccountDeactivated)
+	}
+
+	return s.finishLogin(user, client, attempt, now)
+}
+
+// SetPasswordLogin mengaktifkan / menonaktifkan login dengan password untuk akun sendiri. Password
+// hanya bisa dinonaktifkan jika akun sudah terhubung ke SSO agar user tidak terkunci dari akunnya.
+func (s *authService) SetPasswordLogin(userID uuid.UUID, enabled bool) (*models.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !enabled && user.OIDCSubject == nil {
+		return nil, ErrOIDCNotLinked
+	}
+	if enabled && user.Password == "" {
+		return nil, ErrPasswordNotSet
+	}
+
+	if err := s.oidcRepo.SetPasswordLoginDisabled(userID, !enabled); err != nil {
+		return nil, err
+	}
+	return s.userRepo.FindByID(userID)
+}
+
+// resolveOIDCUser mencari akun untuk identitas IdP. Akun yang cocok lewat email / NIM / NIDN
+// dihubungkan ke subject IdP sehingga login berikutnya cukup mencocokkan subject.
+func (s *authService) resolveOIDCUser(claims oidc.Claims) (*models.User, error) {
+	subject := claims.Subject()
+	if user, err := s.oidcRepo.FindUserBySubject(subject); err == nil {
+		return user, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	user, err := s.matchOIDCUser(claims)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if !s.oidcCfg.AutoProvision {
+			return nil, ErrOIDCAccountNotFound
+		}
+		return s.provisionOIDCUser(claims)
+	}
+
+	if user.OIDCSubject != nil {
+		return nil, ErrOIDCAccountConflict
+	}
+	if err := s.oidcRepo.LinkSubject(user.ID, subject); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOIDCAccountConflict
+		}
+		return nil, err
+	}
+	user.OIDCSubject = &subject
+	user.IsPending = false
+	return user, nil
+}
+
+// matchOIDCUser mencocokkan claim dengan akun yang ada: email (hanya jika diverifikasi IdP),
+// lalu NIM mahasiswa, lalu NIDN dosen
+func (s *authService) matchOIDCUser(claims oidc.Claims) (*models.User, error) {
+	if email := claims.Email(); email != "" && claims.EmailVerified() {
+		if user, err := s.oidcRepo.FindUserByEmail(email); !errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, err
+		}
+	}
+	if nim := claims.String(s.oidcCfg.NIMClaim); nim != "" {
+		if user, err := s.userRepo.FindMahasiswaByNIM(nim); !errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, err
+		}
+	}
+	if nidn := claims.String(s.oidcCfg.NIDNClaim); nidn != "" {
+		if user, err := s.oidcRepo.FindDosenByNIDN(nidn); !errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, err
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// provisionOIDCUser membuat akun baru dari claim IdP: mahasiswa jika ada claim NIM, dosen jika ada
+// claim NIDN. Akun baru hanya bisa login lewat SSO sampai user membuat password sendiri.
+func (s *authService) provisionOIDCUser(claims oidc.Claims) (*models.User, error) {
+	email := claims.Email()
+	if email == "" || !claims.EmailVerified() {
+		return nil, ErrOIDCAccountNotFound
+	}
+
+	subject := claims.Subject()
+	name := claims.String("name")
+	if name == "" {
+		name = email
+	}
+	user := &models.User{
+		Name:                  truncate(name, 100),
+		Email:                 truncate(email, 100),
+		IsActive:              true,
+		PasswordLoginDisabled: true,
+		OIDCSubject:           &subject,
+	}
+
+	if nim := claims.String(s.oidcCfg.NIMClaim); nim != "" {
+		user.Role = ROLE_MAHASISWA
+		user.NIM = truncate(nim, 20)
+	} else if nidn := claims.String(s.oidcCfg.NIDNClaim); nidn != "" {
+		user.Role = ROLE_DOSEN
+		user.NIDN = truncate(nidn, 20)
+	} else {
+		return nil, ErrOIDCAccountNotFound
+	}
+
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"course-planner-api/config"
+	"course-planner-api/internal/mailer"
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// MAX_RESET_EMAILS_PER_HOUR membatasi email reset per akun agar endpoint lupa password
+// tidak bisa dipakai untuk membanjiri inbox user
+const MAX_RESET_EMAILS_PER_HOUR = 3
+
+var (
+	ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+	ErrSamePassword           = errors.New("new password must be different from the current password")
+	// ErrInvalidResetToken dikembalikan untuk token reset yang tidak dikenal, kedaluwarsa atau sudah dipakai
+	ErrInvalidResetToken = errors.New("reset token is invalid, expired, or already used")
+)
+
+type PasswordService interface {
+	ChangePassword(userID, sessionID uuid.UUID, currentPassword, newPassword string) error
+	ForgotPassword(email, ipAddress string) error
+	ResetPassword(token, newPassword string) error
+}
+
+type passwordService struct {
+	repo        repository.PasswordRepository
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	mailer      mailer.Mailer
+	cfg         config.AuthConfig
+}
+
+func NewPasswordService(repo repository.PasswordRepository, userRepo repository.UserRepository, sessionRepo repository.SessionRepository, m mailer.Mailer, cfg config.AuthConfig) PasswordService {
+	return &passwordService{repo: repo, userRepo: userRepo, sessionRepo: sessionRepo, mailer: m, cfg: cfg}
+}
+
+// ChangePassword mengganti password user yang sedang login dan menghapus kewajiban ganti password.
+// Sesi lain dicabut; sesi saat ini tetap aktif dan client perlu memanggil /auth/refresh
+// untuk mendapat access token tanpa claim must_change_password.
+func (s *passwordService) ChangePassword(userID, sessionID uuid.UUID, currentPassword, newPassword string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return ErrInvalidCurrentPassword
+	}
+	if len(newPassword) < MIN_PASSWORD_LENGTH {
+		return ErrPasswordTooShort
+	}
+	if newPassword == currentPassword {
+		return ErrSamePassword
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePassword(user.ID, string(hashed)); err != nil {
+		return err
+	}
+
+	_, err = s.sessionRepo.RevokeAllForUser(user.ID, &sessionID)
+	return err
+}
+
+// ForgotPassword membuat token reset dan mengirimnya lewat email. Selalu berhasil untuk email
+// yang tidak terdaftar, akun pending atau nonaktif agar tidak bisa dipakai menebak akun.
+func (s *passwordService) ForgotPassword(email, ipAddress string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.IsPending || !user.IsActive {
+		return nil
+	}
+
+	count, err := s.repo.CountResetTokensSince(user.ID, time.Now().Add(-time.Hour))
+	if err != nil {
+		return err
+	}
+	if count >= MAX_RESET_EMAILS_PER_HOUR {
+		return nil
+	}
+
+	token, tokenHash, err := newOneTimeToken()
+	if err != nil {
+		return err
+	}
+	resetToken := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(s.cfg.PasswordResetTTL),
+		IPAddress: truncate(ipAddress, 45),
+	}
+	if err := s.repo.CreateResetToken(resetToken); err != nil {
+		return err
+	}
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset password Course Planner",
+		Body:    s.resetEmailBody(user, token),
+	}
+	// dikirim di background agar waktu respons tidak membedakan email terdaftar atau tidak
+	go func() {
+		if err := s.mailer.Send(msg); err != nil {
+			log.Printf("failed to send password reset email to user %s: %v", user.ID, err)
+		}
+	}()
+	return nil
+}
+
+// ResetPassword memakai token reset (sekali pakai) untuk mengganti password
+// lalu mencabut semua sesi user
+func (s *passwordService) ResetPassword(token, newPassword string) error {
+	if token == "" {
+		return ErrInvalidResetToken
+	}
+	if len(newPassword) < MIN_PASSWORD_LENGTH {
+		return ErrPasswordTooShort
+	}
+
+	resetToken, err := s.repo.FindResetTokenByHash(hashToken(token))
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	if resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.userRepo.FindByID(resetToken.UserID)
+	if err != nil || !user.IsActive {
+		return ErrInvalidResetToken
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.ResetPassword(resetToken.ID, user.ID, string(hashed)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+
+	_, err = s.sessionRepo.RevokeAllForUser(user.ID, nil)
+	return err
+}
+
+func (s *passwordService) resetEmailBody(user *models.User, token string) string {
+	link := s.cfg.PasswordResetURL + "?token=" + url.QueryEscape(token)
+	return fmt.Sprintf(`Halo %s,
+
+Kami menerima permintaan reset password untuk akun Course Planner Anda.
+Buka tautan berikut untuk membuat password baru (berlaku %d menit, hanya bisa dipakai sekali):
+
+%s
+
+Abaikan email ini jika Anda tidak meminta reset password.
+`, user.Name, int(s.cfg.PasswordResetTTL.Minutes()), link)
+}
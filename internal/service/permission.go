@@ -0,0 +1,166 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"sort"
+)
+
+// Permission yang dicek per route. Format <resource>:<aksi>.
+const (
+	PERM_CLASS_READ          = "class:read"
+	PERM_CLASS_WRITE         = "class:write"
+	PERM_COURSE_READ         = "course:read"
+	PERM_COURSE_WRITE        = "course:write"
+	PERM_CURRICULUM_READ     = "curriculum:read"
+	PERM_CURRICULUM_WRITE    = "curriculum:write"
+	PERM_ROOM_READ           = "room:read"
+	PERM_ROOM_WRITE          = "room:write"
+	PERM_DOSEN_READ          = "dosen:read"
+	PERM_DOSEN_WRITE         = "dosen:write"
+	PERM_MAHASISWA_READ      = "mahasiswa:read"
+	PERM_MAHASISWA_WRITE     = "mahasiswa:write"
+	PERM_DOSEN_PA_ASSIGN     = "dosen_pa:assign"
+	PERM_FACULTY_READ        = "faculty:read"
+	PERM_FACULTY_WRITE       = "faculty:write"
+	PERM_STUDY_PROGRAM_READ  = "study_program:read"
+	PERM_STUDY_PROGRAM_WRITE = "study_program:write"
+	PERM_CPL_WRITE           = "cpl:write"
+	PERM_GRADE_MANAGE        = "grade:manage"
+	PERM_DEGREE_AUDIT_READ   = "degree_audit:read"
+	PERM_IMPORT_RUN          = "import:run"
+	PERM_EXPORT_RUN          = "export:run"
+	PERM_AUDIT_READ          = "audit:read"
+	PERM_INVITATION_MANAGE   = "invitation:manage"
+	PERM_ROLE_MANAGE         = "role:manage"
+
+	// permission dosen: hanya berlaku untuk akun dosen
+	PERM_ADVISEE_READ   = "advisee:read"
+	PERM_KRS_VERIFY     = "krs:verify"
+	PERM_SYLLABUS_WRITE = "syllabus:write"
+	PERM_GRADE_WRITE    = "grade:write"
+
+	// permission mahasiswa: hanya berlaku untuk akun mahasiswa
+	PERM_KRS_WRITE       = "krs:write"
+	PERM_STUDY_PLAN_READ = "study_plan:read"
+)
+
+// PermissionInfo menjelaskan satu permission. AccountRole diisi jika permission hanya berlaku
+// untuk jenis akun tertentu (misal KRS milik sendiri hanya untuk mahasiswa).
+type PermissionInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	AccountRole string `json:"account_role,omitempty"`
+}
+
+// PERMISSIONS adalah katalog semua permission yang dikenal sistem
+var PERMISSIONS = []PermissionInfo{
+	{Name: PERM_CLASS_READ, Description: "Lihat kelas"},
+	{Name: PERM_CLASS_WRITE, Description: "Kelola kelas"},
+	{Name: PERM_COURSE_READ, Description: "Lihat mata kuliah dan silabus"},
+	{Name: PERM_COURSE_WRITE, Description: "Kelola mata kuliah dan silabus"},
+	{Name: PERM_CURRICULUM_READ, Description: "Lihat kurikulum"},
+	{Name: PERM_CURRICULUM_WRITE, Description: "Kelola kurikulum"},
+	{Name: PERM_ROOM_READ, Description: "Lihat ruangan"},
+	{Name: PERM_ROOM_WRITE, Description: "Kelola ruangan"},
+	{Name: PERM_DOSEN_READ, Description: "Lihat data dosen dan beban mengajar"},
+	{Name: PERM_DOSEN_WRITE, Description: "Kelola data dosen"},
+	{Name: PERM_MAHASISWA_READ, Description: "Lihat data mahasiswa"},
+	{Name: PERM_MAHASISWA_WRITE, Description: "Kelola data mahasiswa"},
+	{Name: PERM_DOSEN_PA_ASSIGN, Description: "Atur dosen PA mahasiswa"},
+	{Name: PERM_FACULTY_READ, Description: "Lihat fakultas"},
+	{Name: PERM_FACULTY_WRITE, Description: "Kelola fakultas"},
+	{Name: PERM_STUDY_PROGRAM_READ, Description: "Lihat prodi dan CPL"},
+	{Name: PERM_STUDY_PROGRAM_WRITE, Description: "Kelola prodi"},
+	{Name: PERM_CPL_WRITE, Description: "Kelola CPL prodi"},
+	{Name: PERM_GRADE_MANAGE, Description: "Lihat dan input nilai semua kelas"},
+	{Name: PERM_DEGREE_AUDIT_READ, Description: "Lihat audit kelulusan mahasiswa"},
+	{Name: PERM_IMPORT_RUN, Description: "Bulk import CSV"},
+	{Name: PERM_EXPORT_RUN, Description: "Export laporan CSV / XLSX"},
+	{Name: PERM_AUDIT_READ, Description: "Lihat audit log"},
+	{Name: PERM_INVITATION_MANAGE, Description: "Kelola undangan akun"},
+	{Name: PERM_ROLE_MANAGE, Description: "Kelola role dan permission user"},
+	{Name: PERM_ADVISEE_READ, Description: "Lihat mahasiswa bimbingan PA", AccountRole: ROLE_DOSEN},
+	{Name: PERM_KRS_VERIFY, Description: "Verifikasi KRS mahasiswa bimbingan", AccountRole: ROLE_DOSEN},
+	{Name: PERM_SYLLABUS_WRITE, Description: "Kelola silabus course yang dikoordinasi", AccountRole: ROLE_DOSEN},
+	{Name: PERM_GRADE_WRITE, Description: "Input nilai kelas yang diampu", AccountRole: ROLE_DOSEN},
+	{Name: PERM_KRS_WRITE, Description: "Isi KRS sendiri", AccountRole: ROLE_MAHASISWA},
+	{Name: PERM_STUDY_PLAN_READ, Description: "Lihat kurikulum dan audit kelulusan sendiri", AccountRole: ROLE_MAHASISWA},
+}
+
+// DEFAULT_ROLE_PERMISSIONS adalah permission awal role sistem saat pertama kali dibuat
+// SYSTEM_ROLE_GLOBAL menentukan scope prodi role sistem (tidak bisa diubah admin): admin yang punya
+// study_program_id adalah admin prodi, sedangkan permission dosen dan mahasiswa hanya untuk data miliknya sendiri
+var SYSTEM_ROLE_GLOBAL = map[string]bool{
+	ROLE_ADMIN:     false,
+	ROLE_DOSEN:     true,
+	ROLE_MAHASISWA: true,
+}
+
+var DEFAULT_ROLE_PERMISSIONS = map[string][]string{
+	ROLE_ADMIN: {
+		PERM_CLASS_READ, PERM_CLASS_WRITE,
+		PERM_COURSE_READ, PERM_COURSE_WRITE,
+		PERM_CURRICULUM_READ, PERM_CURRICULUM_WRITE,
+		PERM_ROOM_READ, PERM_ROOM_WRITE,
+		PERM_DOSEN_READ, PERM_DOSEN_WRITE,
+		PERM_MAHASISWA_READ, PERM_MAHASISWA_WRITE,
+		PERM_DOSEN_PA_ASSIGN,
+		PERM_FACULTY_READ, PERM_FACULTY_WRITE,
+		PERM_STUDY_PROGRAM_READ, PERM_STUDY_PROGRAM_WRITE,
+		PERM_CPL_WRITE,
+		PERM_GRADE_MANAGE,
+		PERM_DEGREE_AUDIT_READ,
+		PERM_IMPORT_RUN, PERM_EXPORT_RUN,
+		PERM_AUDIT_READ,
+		PERM_INVITATION_MANAGE,
+		PERM_ROLE_MANAGE,
+	},
+	ROLE_DOSEN: {
+		PERM_ADVISEE_READ,
+		PERM_KRS_VERIFY,
+		PERM_SYLLABUS_WRITE,
+		PERM_GRADE_WRITE,
+	},
+	ROLE_MAHASISWA: {
+		PERM_KRS_WRITE,
+		PERM_STUDY_PLAN_READ,
+	},
+}
+
+// SYSTEM_ROLE_DESCRIPTIONS adalah deskripsi role sistem
+var SYSTEM_ROLE_DESCRIPTIONS = map[string]string{
+	ROLE_ADMIN:     "Administrator akademik",
+	ROLE_DOSEN:     "Dosen pengampu dan dosen PA",
+	ROLE_MAHASISWA: "Mahasiswa",
+}
+
+func findPermission(name string) (PermissionInfo, bool) {
+	for _, p := range PERMISSIONS {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return PermissionInfo{}, false
+}
+
+// EffectivePermissions menggabungkan permission dari semua role user. Permission yang tidak dikenal
+// atau terikat ke jenis akun lain (misal krs:write untuk akun dosen) diabaikan.
+func EffectivePermissions(accountRole string, roles []models.Role) []string {
+	seen := make(map[string]bool)
+	permissions := make([]string, 0)
+	for _, role := range roles {
+		for _, name := range role.Permissions {
+			info, ok := findPermission(name)
+			if !ok || seen[name] {
+				continue
+			}
+			if info.AccountRole != "" && info.AccountRole != accountRole {
+				continue
+			}
+			seen[name] = true
+			permissions = append(permissions, name)
+		}
+	}
+	sort.Strings(permissions)
+	return permissions
+}
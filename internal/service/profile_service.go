@@ -0,0 +1,258 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"course-planner-api/internal/storage"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MAX_PROFILE_PHOTO_SIZE adalah ukuran maksimal foto profil (2 MB)
+	MAX_PROFILE_PHOTO_SIZE = 2 << 20
+	MAX_ADDRESS_LENGTH     = 500
+)
+
+// PROFILE_PHOTO_TYPES memetakan content type foto yang diterima ke ekstensi file di storage
+var PROFILE_PHOTO_TYPES = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// phonePattern: angka dengan awalan + opsional, boleh dipisah spasi atau '-', misal +62 812-3456-7890
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 -]{5,19}$`)
+
+var (
+	ErrPhotoTooLarge        = fmt.Errorf("photo must be at most %d MB", MAX_PROFILE_PHOTO_SIZE>>20)
+	ErrUnsupportedPhotoType = errors.New("photo must be a JPEG, PNG, or WebP image")
+)
+
+// ProfileDosenPA adalah ringkasan dosen PA yang ditampilkan di profil mahasiswa
+type ProfileDosenPA struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	NIDN  string    `json:"nidn"`
+	Phone string    `json:"phone"`
+}
+
+// SemesterStatus adalah status user pada semester berjalan
+type SemesterStatus struct {
+	Semester        string                 `json:"semester"`
+	TahunAkademik   int                    `json:"tahun_akademik"`
+	SemesterKe      int                    `json:"semester_ke,omitempty"`      // mahasiswa
+	KRS             *repository.KRSSummary `json:"krs,omitempty"`              // mahasiswa; tidak ada jika belum mengisi KRS
+	TeachingClasses *int                   `json:"teaching_classes,omitempty"` // dosen
+	TeachingSKS     *float64               `json:"teaching_sks,omitempty"`     // dosen
+}
+
+// Profile adalah data lengkap user yang sedang login untuk GET /api/me
+type Profile struct {
+	ID                   uuid.UUID            `json:"id"`
+	Name                 string               `json:"name"`
+	Email                string               `json:"email"`
+	Role                 string               `json:"role"`
+	Roles                []string             `json:"roles"`
+	Permissions          []string             `json:"permissions"`
+	NIM                  string               `json:"nim,omitempty"`
+	NIDN                 string               `json:"nidn,omitempty"`
+	Angkatan             int                  `json:"angkatan,omitempty"`
+	Phone                string               `json:"phone"`
+	Address              string               `json:"address"`
+	PhotoURL             string               `json:"photo_url"`
+	StudyProgram         *models.StudyProgram `json:"study_program"`
+	DosenPA              *ProfileDosenPA      `json:"dosen_pa,omitempty"`      // mahasiswa
+	AdviseeCount         *int64               `json:"advisee_count,omitempty"` // dosen
+	KapasitasPA          *int                 `json:"kapasitas_pa,omitempty"`  // dosen
+	Semester             SemesterStatus       `json:"semester"`
+	MustChangePassword   bool                 `json:"must_change_password"`
+	SSOLinked            bool                 `json:"sso_linked"` // akun sudah terhubung ke identity provider kampus
+	PasswordLoginEnabled bool                 `json:"password_login_enabled"`
+	CreatedAt            time.Time            `json:"created_at"`
+}
+
+// UpdateContactInput berisi data kontak yang boleh diubah user sendiri; nil berarti tidak diubah
+type UpdateContactInput struct {
+	Phone   *string
+	Address *string
+}
+
+type ProfileService interface {
+	GetProfile(userID uuid.UUID) (*Profile, error)
+	UpdateContact(userID uuid.UUID, input UpdateContactInput) (*Profile, error)
+	UploadPhoto(userID uuid.UUID, data []byte) (*Profile, error)
+	DeletePhoto(userID uuid.UUID) (*Profile, error)
+}
+
+type profileService struct {
+	repo         repository.ProfileRepository
+	roleService  RoleService
+	classService ClassService
+	storage      storage.Storage
+}
+
+func NewProfileService(repo repository.ProfileRepository, roleService RoleService, classService ClassService, store storage.Storage) ProfileService {
+	return &profileService{repo: repo, roleService: roleService, classService: classService, storage: store}
+}
+
+func (s *profileService) GetProfile(userID uuid.UUID) (*Profile, error) {
+	user, err := s.repo.FindUser(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	roles, permissions, err := s.roleService.PermissionsForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &Profile{
+		ID:                   user.ID,
+		Name:                 user.Name,
+		Email:                user.Email,
+		Role:                 user.Role,
+		Roles:                roles,
+		Permissions:          permissions,
+		NIM:                  user.NIM,
+		NIDN:                 user.NIDN,
+		Phone:                user.Phone,
+		Address:              user.Address,
+		StudyProgram:         user.StudyProgram,
+		MustChangePassword:   user.MustChangePassword,
+		SSOLinked:            user.OIDCSubject != nil,
+		PasswordLoginEnabled: !user.PasswordLoginDisabled,
+		CreatedAt:            user.CreatedAt,
+		Semester: SemesterStatus{
+			Semester:      GetCurrentSemester(),
+			TahunAkademik: GetCurrentTahunAkademik(),
+		},
+	}
+	if user.PhotoKey != "" {
+		profile.PhotoURL = s.storage.URL(user.PhotoKey)
+	}
+
+	switch user.Role {
+	case ROLE_MAHASISWA:
+		profile.Angkatan = user.Angkatan
+		profile.Semester.SemesterKe = StudentSemester(user.Angkatan)
+
+		if user.DosenPAID != nil {
+			if pa, err := s.repo.FindUser(*user.DosenPAID); err == nil {
+				profile.DosenPA = &ProfileDosenPA{ID: pa.ID, Name: pa.Name, Email: pa.Email, NIDN: pa.NIDN, Phone: pa.Phone}
+			}
+		}
+
+		krs, err := s.repo.FindKRSSummary(user.ID, profile.Semester.Semester, inactiveItemStatuses())
+		if err != nil {
+			return nil, err
+		}
+		profile.Semester.KRS = krs
+
+	case ROLE_DOSEN:
+		adviseeCount, err := s.repo.CountAdvisees(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		profile.AdviseeCount = &adviseeCount
+		profile.KapasitasPA = &user.KapasitasPA
+
+		load, err := s.classService.GetTeachingLoad(user.ID, profile.Semester.Semester)
+		if err != nil {
+			return nil, err
+		}
+		classCount := len(load.Classes)
+		profile.Semester.TeachingClasses = &classCount
+		profile.Semester.TeachingSKS = &load.TotalSKS
+	}
+
+	return profile, nil
+}
+
+// UpdateContact mengubah nomor telepon dan alamat; string kosong menghapus data
+func (s *profileService) UpdateContact(userID uuid.UUID, input UpdateContactInput) (*Profile, error) {
+	fields := make(map[string]interface{})
+	if input.Phone != nil {
+		phone := strings.TrimSpace(*input.Phone)
+		if phone != "" && !phonePattern.MatchString(phone) {
+			return nil, errors.New("phone must be 6-20 digits, optionally starting with + and separated by spaces or '-'")
+		}
+		fields["phone"] = phone
+	}
+	if input.Address != nil {
+		address := strings.TrimSpace(*input.Address)
+		if utf8.RuneCountInString(address) > MAX_ADDRESS_LENGTH {
+			return nil, fmt.Errorf("address must be at most %d characters", MAX_ADDRESS_LENGTH)
+		}
+		fields["address"] = address
+	}
+
+	if len(fields) > 0 {
+		if err := s.repo.UpdateContact(userID, fields); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetProfile(userID)
+}
+
+// UploadPhoto menyimpan foto profil baru dengan key acak (agar URL lama tidak ter-cache)
+// lalu menghapus foto sebelumnya
+func (s *profileService) UploadPhoto(userID uuid.UUID, data []byte) (*Profile, error) {
+	if len(data) > MAX_PROFILE_PHOTO_SIZE {
+		return nil, ErrPhotoTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := PROFILE_PHOTO_TYPES[contentType]
+	if !ok {
+		return nil, ErrUnsupportedPhotoType
+	}
+
+	user, err := s.repo.FindUser(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	key := fmt.Sprintf("profile-photos/%s/%s%s", user.ID, uuid.NewString(), ext)
+	if err := s.storage.Put(key, data, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdatePhotoKey(user.ID, key); err != nil {
+		s.removePhoto(key)
+		return nil, err
+	}
+	if user.PhotoKey != "" {
+		s.removePhoto(user.PhotoKey)
+	}
+
+	return s.GetProfile(user.ID)
+}
+
+func (s *profileService) DeletePhoto(userID uuid.UUID) (*Profile, error) {
+	user, err := s.repo.FindUser(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.PhotoKey != "" {
+		if err := s.repo.UpdatePhotoKey(user.ID, ""); err != nil {
+			return nil, err
+		}
+		s.removePhoto(user.PhotoKey)
+	}
+	return s.GetProfile(user.ID)
+}
+
+// removePhoto menghapus file foto lama; kegagalan hanya dicatat karena data user sudah konsisten
+func (s *profileService) removePhoto(key string) {
+	if err := s.storage.Delete(key); err != nil {
+		log.Printf("failed to delete profile photo %s: %v", key, err)
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UpdateProgramOutcomeInput struct {
+	Kode      *string
+	Deskripsi *string
+}
+
+// ProgramOutcomeService mengelola CPL (capaian pembelajaran lulusan) per prodi
+type ProgramOutcomeService interface {
+	CreateProgramOutcome(studyProgramID uuid.UUID, kode, deskripsi string) (*models.ProgramOutcome, error)
+	GetProgramOutcomeByID(id uuid.UUID) (*models.ProgramOutcome, error)
+	ListProgramOutcomes(studyProgramID uuid.UUID) ([]models.ProgramOutcome, error)
+	UpdateProgramOutcome(id uuid.UUID, input UpdateProgramOutcomeInput) (*models.ProgramOutcome, error)
+	DeleteProgramOutcome(id uuid.UUID) error
+}
+
+type programOutcomeService struct {
+	repo             repository.ProgramOutcomeRepository
+	studyProgramRepo repository.StudyProgramRepository
+}
+
+func NewProgramOutcomeService(repo repository.ProgramOutcomeRepository, studyProgramRepo repository.StudyProgramRepository) ProgramOutcomeService {
+	return &programOutcomeService{repo: repo, studyProgramRepo: studyProgramRepo}
+}
+
+func (s *programOutcomeService) CreateProgramOutcome(studyProgramID uuid.UUID, kode, deskripsi string) (*models.ProgramOutcome, error) {
+	if _, err := s.studyProgramRepo.FindByID(studyProgramID); err != nil {
+		return nil, errors.New("study program not found")
+	}
+
+	existing, err := s.repo.FindByKode(studyProgramID, kode)
+	if err == nil && existing != nil {
+		return nil, errors.New("CPL dengan kode tersebut sudah ada di prodi ini")
+	}
+
+	outcome := &models.ProgramOutcome{
+		StudyProgramID: studyProgramID,
+		Kode:           kode,
+		Deskripsi:      deskripsi,
+	}
+	if err := s.repo.Create(outcome); err != nil {
+		return nil, err
+	}
+	return outcome, nil
+}
+
+func (s *programOutcomeService) GetProgramOutcomeByID(id uuid.UUID) (*models.ProgramOutcome, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *programOutcomeService) ListProgramOutcomes(studyProgramID uuid.UUID) ([]models.ProgramOutcome, error) {
+	return s.repo.FindByStudyProgram(studyProgramID)
+}
+
+func (s *programOutcomeService) UpdateProgramOutcome(id uuid.UUID, input UpdateProgramOutcomeInput) (*models.ProgramOutcome, error) {
+	outcome, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("program outcome not found")
+		}
+		return nil, err
+	}
+
+	if input.Kode != nil {
+		existing, err := s.repo.FindByKode(outcome.StudyProgramID, *input.Kode)
+		if err == nil && existing != nil && existing.ID != id {
+			return nil, errors.New("CPL dengan kode tersebut sudah ada di prodi ini")
+		}
+		outcome.Kode = *input.Kode
+	}
+
+	if input.Deskripsi != nil {
+		outcome.Deskripsi = *input.Deskripsi
+	}
+
+	if err := s.repo.Update(outcome); err != nil {
+		return nil, err
+	}
+	return outcome, nil
+}
+
+func (s *programOutcomeService) DeleteProgramOutcome(id uuid.UUID) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("program outcome not found")
+		}
+		return err
+	}
+
+	mappingCount, err := s.repo.CountMappings(id)
+	if err != nil {
+		return err
+	}
+	if mappingCount > 0 {
+		return &DependencyError{
+			Entity:     "program outcome",
+			Dependents: map[string]int64{"cpmk": mappingCount},
+		}
+	}
+
+	return s.repo.Delete(id)
+}
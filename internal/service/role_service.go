@@ -0,0 +1,343 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// roleNamePattern: huruf kecil, angka, '_' atau '-', misal kaprodi atau staf_ruangan
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+
+type CreateRoleInput struct {
+	Name             string
+	Description      string
+	Permissions      []string
+	RequireTwoFactor bool
+	Global           bool
+}
+
+type UpdateRoleInput struct {
+	Name             *string
+	Description      *string
+	Permissions      *[]string
+	RequireTwoFactor *bool
+	Global           *bool
+}
+
+// UserRoles adalah ringkasan role dan permission efektif seorang user
+type UserRoles struct {
+	UserID      uuid.UUID     `json:"user_id"`
+	AccountRole string        `json:"account_role"` // users.role, menentukan role sistem yang otomatis dimiliki
+	Roles       []models.Role `json:"roles"`        // role tambahan
+	Permissions []string      `json:"permissions"`
+}
+
+type RoleService interface {
+	EnsureSystemRoles() error
+	ListPermissions() []PermissionInfo
+	ListRoles() ([]models.Role, error)
+	GetRole(id uuid.UUID) (*models.Role, error)
+	CreateRole(input CreateRoleInput) (*models.Role, error)
+	UpdateRole(id uuid.UUID, input UpdateRoleInput) (*models.Role, error)
+	DeleteRole(id uuid.UUID) error
+	GetUserRoles(userID uuid.UUID) (*UserRoles, error)
+	ReplaceUserRoles(userID uuid.UUID, roleIDs []uuid.UUID) (*UserRoles, error)
+	PermissionsForUser(user *models.User) (roles []string, permissions []string, err error)
+	ProgramScope(user *models.User) (scoped bool, globalPermissions []string, err error)
+	RequiresTwoFactor(user *models.User) (bool, error)
+}
+
+type roleService struct {
+	repo     repository.RoleRepository
+	userRepo repository.UserRepository
+}
+
+func NewRoleService(repo repository.RoleRepository, userRepo repository.UserRepository) RoleService {
+	return &roleService{repo: repo, userRepo: userRepo}
+}
+
+// EnsureSystemRoles membuat role admin, dosen dan mahasiswa jika belum ada.
+// Permission role yang sudah ada tidak diubah agar yang diatur admin tetap; hanya scope prodi
+// yang disamakan dengan SYSTEM_ROLE_GLOBAL.
+func (s *roleService) EnsureSystemRoles() error {
+	for _, name := range []string{ROLE_ADMIN, ROLE_DOSEN, ROLE_MAHASISWA} {
+		if existing, err := s.repo.FindByName(name); err == nil {
+			if existing.Global != SYSTEM_ROLE_GLOBAL[name] {
+				existing.Global = SYSTEM_ROLE_GLOBAL[name]
+				if err := s.repo.Update(existing); err != nil {
+					return err
+				}
+			}
+			continue
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		role := &models.Role{
+			Name:        name,
+			Description: SYSTEM_ROLE_DESCRIPTIONS[name],
+			Permissions: models.StringList(DEFAULT_ROLE_PERMISSIONS[name]),
+			IsSystem:    true,
+			Global:      SYSTEM_ROLE_GLOBAL[name],
+		}
+		if err := s.repo.Create(role); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *roleService) ListPermissions() []PermissionInfo {
+	return PERMISSIONS
+}
+
+func (s *roleService) ListRoles() ([]models.Role, error) {
+	return s.repo.FindAll()
+}
+
+func (s *roleService) GetRole(id uuid.UUID) (*models.Role, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *roleService) CreateRole(input CreateRoleInput) (*models.Role, error) {
+	if !roleNamePattern.MatchString(input.Name) {
+		return nil, errors.New("name must be 2-50 lowercase letters, digits, '_' or '-'")
+	}
+	if existing, err := s.repo.FindByName(input.Name); err == nil && existing != nil {
+		return nil, errors.New("role dengan nama tersebut sudah ada")
+	}
+	permissions, err := validatePermissions(input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{
+		Name:             input.Name,
+		Description:      input.Description,
+		Permissions:      permissions,
+		RequireTwoFactor: input.RequireTwoFactor,
+		Global:           input.Global,
+	}
+	if err := s.repo.Create(role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// UpdateRole mengubah role. Nama role sistem tidak bisa diganti, dan role admin
+// harus tetap punya role:manage agar pengelolaan role tidak terkunci.
+func (s *roleService) UpdateRole(id uuid.UUID, input UpdateRoleInput) (*models.Role, error) {
+	role, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("role not found")
+		}
+		return nil, err
+	}
+
+	if input.Name != nil && *input.Name != role.Name {
+		if role.IsSystem {
+			return nil, errors.New("system role cannot be renamed")
+		}
+		if !roleNamePattern.MatchString(*input.Name) {
+			return nil, errors.New("name must be 2-50 lowercase letters, digits, '_' or '-'")
+		}
+		if existing, err := s.repo.FindByName(*input.Name); err == nil && existing != nil && existing.ID != id {
+			return nil, errors.New("role dengan nama tersebut sudah ada")
+		}
+		role.Name = *input.Name
+	}
+	if input.Description != nil {
+		role.Description = *input.Description
+	}
+	if input.Permissions != nil {
+		permissions, err := validatePermissions(*input.Permissions)
+		if err != nil {
+			return nil, err
+		}
+		if role.IsSystem && role.Name == ROLE_ADMIN && !containsString(permissions, PERM_ROLE_MANAGE) {
+			return nil, fmt.Errorf("role %s must keep permission %s", ROLE_ADMIN, PERM_ROLE_MANAGE)
+		}
+		role.Permissions = permissions
+	}
+	if input.RequireTwoFactor != nil {
+		role.RequireTwoFactor = *input.RequireTwoFactor
+	}
+	if input.Global != nil && *input.Global != role.Global {
+		if role.IsSystem {
+			return nil, errors.New("program scope of a system role cannot be changed")
+		}
+		role.Global = *input.Global
+	}
+
+	if err := s.repo.Update(role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *roleService) DeleteRole(id uuid.UUID) error {
+	role, err := s.repo.FindByID(id)
+	if err != nil {
+		return errors.New("role not found")
+	}
+	if role.IsSystem {
+		return errors.New("system role cannot be deleted")
+	}
+
+	userCount, err := s.repo.CountUsers(id)
+	if err != nil {
+		return err
+	}
+	if userCount > 0 {
+		return &DependencyError{
+			Entity:     "role",
+			Dependents: map[string]int64{"users": userCount},
+		}
+	}
+	return s.repo.Delete(id)
+}
+
+func (s *roleService) GetUserRoles(userID uuid.UUID) (*UserRoles, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	extra, err := s.repo.FindUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+	_, permissions, err := s.PermissionsForUser(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserRoles{
+		UserID:      user.ID,
+		AccountRole: user.Role,
+		Roles:       extra,
+		Permissions: permissions,
+	}, nil
+}
+
+// ReplaceUserRoles mengganti role tambahan user. Role sistem tidak bisa diberikan lewat sini
+// karena ditentukan oleh jenis akun (users.role).
+func (s *roleService) ReplaceUserRoles(userID uuid.UUID, roleIDs []uuid.UUID) (*UserRoles, error) {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	unique := make([]uuid.UUID, 0, len(roleIDs))
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range roleIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	roles, err := s.repo.FindByIDs(unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) != len(unique) {
+		return nil, errors.New("role not found")
+	}
+	for _, role := range roles {
+		if role.IsSystem {
+			return nil, fmt.Errorf("system role %s is determined by the account type and cannot be assigned", role.Name)
+		}
+	}
+
+	if err := s.repo.ReplaceUserRoles(userID, unique); err != nil {
+		return nil, err
+	}
+	return s.GetUserRoles(userID)
+}
+
+// PermissionsForUser mengembalikan nama role dan permission efektif user untuk claim JWT
+func (s *roleService) PermissionsForUser(user *models.User) ([]string, []string, error) {
+	roles, err := s.repo.FindEffectiveRoles(user.ID, user.Role)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names, EffectivePermissions(user.Role, roles), nil
+}
+
+// ProgramScope menentukan apakah token user dibatasi ke prodinya: ya jika user punya study_program_id
+// dan memegang role yang tidak global. globalPermissions adalah permission dari role global milik user
+// yang tetap berlaku lintas prodi (misal staf_ruangan untuk dosen yang juga kaprodi).
+func (s *roleService) ProgramScope(user *models.User) (bool, []string, error) {
+	if user.StudyProgramID == nil {
+		return false, nil, nil
+	}
+	roles, err := s.repo.FindEffectiveRoles(user.ID, user.Role)
+	if err != nil {
+		return false, nil, err
+	}
+
+	scoped := false
+	global := make([]models.Role, 0, len(roles))
+	for _, role := range roles {
+		if role.Global {
+			global = append(global, role)
+		} else {
+			scoped = true
+		}
+	}
+	if !scoped {
+		return false, nil, nil
+	}
+	return true, EffectivePermissions(user.Role, global), nil
+}
+
+// RequiresTwoFactor true jika salah satu role efektif user mewajibkan 2FA
+func (s *roleService) RequiresTwoFactor(user *models.User) (bool, error) {
+	roles, err := s.repo.FindEffectiveRoles(user.ID, user.Role)
+	if err != nil {
+		return false, err
+	}
+	for _, role := range roles {
+		if role.RequireTwoFactor {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validatePermissions menolak permission yang tidak dikenal dan membuang duplikat
+func validatePermissions(permissions []string) (models.StringList, error) {
+	result := make(models.StringList, 0, len(permissions))
+	seen := make(map[string]bool)
+	for _, name := range permissions {
+		if _, ok := findPermission(name); !ok {
+			return nil, fmt.Errorf("unknown permission: %s", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	return result, nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -12,9 +12,10 @@ import (
 type RoomService interface {
 	CreateRoom(nama string) (*models.Room, error)
 	GetRoomByID(id uuid.UUID) (*models.Room, error)
-	GetAllRooms() ([]models.Room, error)
+	GetAllRooms(filter repository.RoomFilter, page repository.PageQuery) (*repository.Page[models.Room], error)
 	UpdateRoom(id uuid.UUID, nama *string) (*models.Room, error)
 	DeleteRoom(id uuid.UUID) error
+	RestoreRoom(id uuid.UUID) (*models.Room, error)
 }
 
 type roomService struct {
@@ -47,8 +48,8 @@ func (s *roomService) GetRoomByID(id uuid.UUID) (*models.Room, error) {
 	return s.repo.FindByID(id)
 }
 
-func (s *roomService) GetAllRooms() ([]models.Room, error) {
-	return s.repo.FindAll()
+func (s *roomService) GetAllRooms(filter repository.RoomFilter, page repository.PageQuery) (*repository.Page[models.Room], error) {
+	return s.repo.FindAll(filter, page)
 }
 
 func (s *roomService) UpdateRoom(id uuid.UUID, nama *string) (*models.Room, error) {
@@ -85,5 +86,38 @@ func (s *roomService) DeleteRoom(id uuid.UUID) error {
 		return err
 	}
 
+	classCount, err := s.repo.CountActiveClasses(id)
+	if err != nil {
+		return err
+	}
+	if classCount > 0 {
+		return &DependencyError{
+			Entity:     "room",
+			Dependents: map[string]int64{"classes": classCount},
+		}
+	}
+
 	return s.repo.Delete(id)
 }
+
+func (s *roomService) RestoreRoom(id uuid.UUID) (*models.Room, error) {
+	deleted, err := s.repo.FindDeletedByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("deleted room not found")
+		}
+		return nil, err
+	}
+
+	// Nama bisa saja sudah dipakai room lain setelah room ini dihapus
+	existing, err := s.repo.FindByNama(deleted.Nama)
+	if err == nil && existing != nil {
+		return nil, errors.New("room dengan nama tersebut sudah ada")
+	}
+
+	if err := s.repo.Restore(id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(id)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CreateStudyProgramInput struct {
+	Kode      string
+	Nama      string
+	Jenjang   string
+	FacultyID uuid.UUID
+}
+
+type UpdateStudyProgramInput struct {
+	Kode      *string
+	Nama      *string
+	Jenjang   *string
+	FacultyID *uuid.UUID
+}
+
+type StudyProgramService interface {
+	CreateStudyProgram(input CreateStudyProgramInput) (*models.StudyProgram, error)
+	GetStudyProgramByID(id uuid.UUID) (*models.StudyProgram, error)
+	GetAllStudyPrograms(facultyID *uuid.UUID) ([]models.StudyProgram, error)
+	UpdateStudyProgram(id uuid.UUID, input UpdateStudyProgramInput) (*models.StudyProgram, error)
+	DeleteStudyProgram(id uuid.UUID) error
+}
+
+type studyProgramService struct {
+	repo        repository.StudyProgramRepository
+	facultyRepo repository.FacultyRepository
+}
+
+func NewStudyProgramService(repo repository.StudyProgramRepository, facultyRepo repository.FacultyRepository) StudyProgramService {
+	return &studyProgramService{repo: repo, facultyRepo: facultyRepo}
+}
+
+func (s *studyProgramService) CreateStudyProgram(input CreateStudyProgramInput) (*models.StudyProgram, error) {
+	existing, err := s.repo.FindByKode(input.Kode)
+	if err == nil && existing != nil {
+		return nil, errors.New("program studi dengan kode tersebut sudah ada")
+	}
+
+	if _, err := s.facultyRepo.FindByID(input.FacultyID); err != nil {
+		return nil, errors.New("faculty not found")
+	}
+
+	program := &models.StudyProgram{
+		Kode:      input.Kode,
+		Nama:      input.Nama,
+		Jenjang:   input.Jenjang,
+		FacultyID: input.FacultyID,
+	}
+
+	if err := s.repo.Create(program); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(program.ID)
+}
+
+func (s *studyProgramService) GetStudyProgramByID(id uuid.UUID) (*models.StudyProgram, error) {
+	return s.repo.FindByID(id)
+}
+
+func (s *studyProgramService) GetAllStudyPrograms(facultyID *uuid.UUID) ([]models.StudyProgram, error) {
+	return s.repo.FindAll(facultyID)
+}
+
+func (s *studyProgramService) UpdateStudyProgram(id uuid.UUID, input UpdateStudyProgramInput) (*models.StudyProgram, error) {
+	program, err := s.repo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("study program not found")
+		}
+		return nil, err
+	}
+
+	if input.Kode != nil {
+		existing, err := s.repo.FindByKode(*input.Kode)
+		if err == nil && existing != nil && existing.ID != id {
+			return nil, errors.New("program studi dengan kode tersebut sudah ada")
+		}
+		program.Kode = *input.Kode
+	}
+
+	if input.Nama != nil {
+		program.Nama = *input.Nama
+	}
+
+	if input.Jenjang != nil {
+		program.Jenjang = *input.Jenjang
+	}
+
+	if input.FacultyID != nil {
+		if _, err := s.facultyRepo.FindByID(*input.FacultyID); err != nil {
+			return nil, errors.New("faculty not found")
+		}
+		program.FacultyID = *input.FacultyID
+	}
+
+	if err := s.repo.Update(program); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(id)
+}
+
+func (s *studyProgramService) DeleteStudyProgram(id uuid.UUID) error {
+	if _, err := s.repo.FindByID(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("study program not found")
+		}
+		return err
+	}
+
+	counts, err := s.repo.CountDependents(id)
+	if err != nil {
+		return err
+	}
+	for _, count := range counts {
+		if count > 0 {
+			return &DependencyError{Entity: "study program", Dependents: counts}
+		}
+	}
+
+	return s.repo.Delete(id)
+}
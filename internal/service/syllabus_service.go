@@ -0,0 +1,258 @@
+package service
+
+import (
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	SYLLABUS_REFERENCE_UTAMA     = "utama"
+	SYLLABUS_REFERENCE_PENDUKUNG = "pendukung"
+)
+
+var ErrInvalidSemester = errors.New("semester must be ganjil or genap and tahun_akademik must be set")
+
+type LearningOutcomeInput struct {
+	Kode              string
+	Deskripsi         string
+	ProgramOutcomeIDs []uuid.UUID
+}
+
+type SyllabusTopicInput struct {
+	Minggu    int
+	Topik     string
+	Deskripsi string
+	Metode    string
+	Penilaian string
+}
+
+type SyllabusReferenceInput struct {
+	Jenis    string
+	Penulis  string
+	Judul    string
+	Penerbit string
+	Tahun    *int
+}
+
+// SaveSyllabusInput mengganti seluruh isi versi silabus yang berlaku mulai TahunAkademik + Semester
+type SaveSyllabusInput struct {
+	TahunAkademik    int
+	Semester         string
+	Deskripsi        string
+	LearningOutcomes []LearningOutcomeInput
+	Topics           []SyllabusTopicInput
+	References       []SyllabusReferenceInput
+	UpdatedByID      *uuid.UUID
+}
+
+type SyllabusService interface {
+	GetEffectiveSyllabus(courseID uuid.UUID, tahunAkademik int, semester string) (*models.CourseSyllabus, error)
+	ListVersions(courseID uuid.UUID) ([]models.CourseSyllabus, error)
+	SaveSyllabus(courseID uuid.UUID, input SaveSyllabusInput) (*models.CourseSyllabus, error)
+	DeleteSyllabus(courseID uuid.UUID, tahunAkademik int, semester string) error
+	IsCourseCoordinator(courseID, dosenID uuid.UUID) (bool, error)
+	ListCoordinatedCourses(dosenID uuid.UUID) ([]models.Course, error)
+}
+
+type syllabusService struct {
+	repo               repository.SyllabusRepository
+	courseRepo         repository.CourseRepository
+	programOutcomeRepo repository.ProgramOutcomeRepository
+}
+
+func NewSyllabusService(repo repository.SyllabusRepository, courseRepo repository.CourseRepository, programOutcomeRepo repository.ProgramOutcomeRepository) SyllabusService {
+	return &syllabusService{repo: repo, courseRepo: courseRepo, programOutcomeRepo: programOutcomeRepo}
+}
+
+// ValidateSemester memeriksa pasangan tahun akademik + semester
+func ValidateSemester(tahunAkademik int, semester string) error {
+	if tahunAkademik <= 0 || (semester != SEMESTER_GANJIL && semester != SEMESTER_GENAP) {
+		return ErrInvalidSemester
+	}
+	return nil
+}
+
+// GetEffectiveSyllabus mengembalikan versi silabus yang berlaku di semester tersebut.
+// gorm.ErrRecordNotFound jika belum ada versi yang berlaku.
+func (s *syllabusService) GetEffectiveSyllabus(courseID uuid.UUID, tahunAkademik int, semester string) (*models.CourseSyllabus, error) {
+	if err := ValidateSemester(tahunAkademik, semester); err != nil {
+		return nil, err
+	}
+	return s.repo.FindEffective(courseID, SemesterOrder(tahunAkademik, semester))
+}
+
+func (s *syllabusService) ListVersions(courseID uuid.UUID) ([]models.CourseSyllabus, error) {
+	return s.repo.FindVersions(courseID)
+}
+
+func (s *syllabusService) SaveSyllabus(courseID uuid.UUID, input SaveSyllabusInput) (*models.CourseSyllabus, error) {
+	if err := ValidateSemester(input.TahunAkademik, input.Semester); err != nil {
+		return nil, err
+	}
+
+	course, err := s.courseRepo.FindByID(courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("course not found")
+		}
+		return nil, err
+	}
+
+	outcomes, err := s.buildLearningOutcomes(course, input.LearningOutcomes)
+	if err != nil {
+		return nil, err
+	}
+	topics, err := buildSyllabusTopics(input.Topics)
+	if err != nil {
+		return nil, err
+	}
+	references, err := buildSyllabusReferences(input.References)
+	if err != nil {
+		return nil, err
+	}
+
+	syllabus := &models.CourseSyllabus{
+		CourseID:         courseID,
+		TahunAkademik:    input.TahunAkademik,
+		Semester:         input.Semester,
+		UrutanSemester:   SemesterOrder(input.TahunAkademik, input.Semester),
+		Deskripsi:        input.Deskripsi,
+		LearningOutcomes: outcomes,
+		Topics:           topics,
+		References:       references,
+		UpdatedByID:      input.UpdatedByID,
+	}
+	if err := s.repo.SaveVersion(syllabus); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindEffective(courseID, syllabus.UrutanSemester)
+}
+
+// DeleteSyllabus menghapus versi yang mulai berlaku tepat di semester tersebut;
+// semester itu kembali memakai versi sebelumnya (jika ada)
+func (s *syllabusService) DeleteSyllabus(courseID uuid.UUID, tahunAkademik int, semester string) error {
+	if err := ValidateSemester(tahunAkademik, semester); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteVersion(courseID, SemesterOrder(tahunAkademik, semester)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("syllabus version not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *syllabusService) IsCourseCoordinator(courseID, dosenID uuid.UUID) (bool, error) {
+	return s.repo.IsCourseCoordinator(courseID, dosenID)
+}
+
+func (s *syllabusService) ListCoordinatedCourses(dosenID uuid.UUID) ([]models.Course, error) {
+	return s.repo.FindCoordinatedCourses(dosenID)
+}
+
+// buildLearningOutcomes memvalidasi CPMK; CPL yang dipetakan harus milik prodi course
+func (s *syllabusService) buildLearningOutcomes(course *models.Course, inputs []LearningOutcomeInput) ([]models.LearningOutcome, error) {
+	outcomes := make([]models.LearningOutcome, 0, len(inputs))
+	seenKode := make(map[string]bool, len(inputs))
+	var programOutcomeIDs []uuid.UUID
+
+	for _, in := range inputs {
+		if in.Kode == "" || in.Deskripsi == "" {
+			return nil, errors.New("cpmk kode and deskripsi are required")
+		}
+		if seenKode[in.Kode] {
+			return nil, fmt.Errorf("cpmk %s listed more than once", in.Kode)
+		}
+		seenKode[in.Kode] = true
+
+		outcome := models.LearningOutcome{Kode: in.Kode, Deskripsi: in.Deskripsi}
+		seenCPL := make(map[uuid.UUID]bool, len(in.ProgramOutcomeIDs))
+		for _, id := range in.ProgramOutcomeIDs {
+			if seenCPL[id] {
+				return nil, fmt.Errorf("cpmk %s maps the same cpl more than once", in.Kode)
+			}
+			seenCPL[id] = true
+			outcome.ProgramOutcomes = append(outcome.ProgramOutcomes, models.LearningOutcomeMapping{ProgramOutcomeID: id})
+			programOutcomeIDs = append(programOutcomeIDs, id)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	if len(programOutcomeIDs) == 0 {
+		return outcomes, nil
+	}
+	if course.StudyProgramID == nil {
+		return nil, errors.New("course belum memiliki prodi, CPMK tidak bisa dipetakan ke CPL")
+	}
+
+	found, err := s.programOutcomeRepo.FindByIDs(programOutcomeIDs)
+	if err != nil {
+		return nil, err
+	}
+	valid := make(map[uuid.UUID]bool, len(found))
+	for _, po := range found {
+		if po.StudyProgramID == *course.StudyProgramID {
+			valid[po.ID] = true
+		}
+	}
+	for _, id := range programOutcomeIDs {
+		if !valid[id] {
+			return nil, fmt.Errorf("cpl %s not found in the course's study program", id)
+		}
+	}
+	return outcomes, nil
+}
+
+func buildSyllabusTopics(inputs []SyllabusTopicInput) ([]models.SyllabusTopic, error) {
+	topics := make([]models.SyllabusTopic, 0, len(inputs))
+	seen := make(map[int]bool, len(inputs))
+	for _, in := range inputs {
+		if in.Minggu <= 0 || in.Topik == "" {
+			return nil, errors.New("rps minggu (> 0) and topik are required")
+		}
+		if seen[in.Minggu] {
+			return nil, fmt.Errorf("rps minggu %d listed more than once", in.Minggu)
+		}
+		seen[in.Minggu] = true
+		topics = append(topics, models.SyllabusTopic{
+			Minggu:    in.Minggu,
+			Topik:     in.Topik,
+			Deskripsi: in.Deskripsi,
+			Metode:    in.Metode,
+			Penilaian: in.Penilaian,
+		})
+	}
+	return topics, nil
+}
+
+func buildSyllabusReferences(inputs []SyllabusReferenceInput) ([]models.SyllabusReference, error) {
+	references := make([]models.SyllabusReference, 0, len(inputs))
+	for i, in := range inputs {
+		if in.Judul == "" {
+			return nil, errors.New("referensi judul is required")
+		}
+		jenis := in.Jenis
+		if jenis == "" {
+			jenis = SYLLABUS_REFERENCE_UTAMA
+		}
+		if jenis != SYLLABUS_REFERENCE_UTAMA && jenis != SYLLABUS_REFERENCE_PENDUKUNG {
+			return nil, fmt.Errorf("referensi jenis must be %s or %s", SYLLABUS_REFERENCE_UTAMA, SYLLABUS_REFERENCE_PENDUKUNG)
+		}
+		references = append(references, models.SyllabusReference{
+			Urutan:   i + 1,
+			Jenis:    jenis,
+			Penulis:  in.Penulis,
+			Judul:    in.Judul,
+			Penerbit: in.Penerbit,
+			Tahun:    in.Tahun,
+		})
+	}
+	return references, nil
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"course-planner-api/config"
+	"course-planner-api/internal/models"
+	"course-planner-api/internal/repository"
+	"course-planner-api/internal/totp"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// RECOVERY_CODE_COUNT adalah jumlah recovery code yang dibuat saat 2FA diaktifkan / dibuat ulang
+	RECOVERY_CODE_COUNT = 10
+
+	TWO_FACTOR_METHOD_TOTP          = "totp"
+	TWO_FACTOR_METHOD_RECOVERY_CODE = "recovery_code"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("no pending two-factor enrollment, call enroll first")
+	ErrTwoFactorRequired       = errors.New("two-factor authentication is required for your role and cannot be disabled")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	// ErrTOTPCodeRequired: akun tanpa login password (hanya SSO) harus memakai kode authenticator, bukan recovery code
+	ErrTOTPCodeRequired = errors.New("a code from your authenticator app is required, recovery codes cannot be used for accounts without password login")
+)
+
+// recoveryCodeEncoding: huruf kecil dan angka 2-7 agar mudah diketik ulang
+var recoveryCodeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// TwoFactorStatus adalah status 2FA akun sendiri
+type TwoFactorStatus struct {
+	Enabled                bool       `json:"enabled"`
+	ConfirmedAt            *time.Time `json:"confirmed_at"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+	Required               bool       `json:"required"` // diwajibkan oleh salah satu role user
+}
+
+// TwoFactorEnrollment berisi secret baru untuk didaftarkan ke authenticator app; provisioning_uri
+// ditampilkan sebagai QR code oleh client
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorService interface {
+	Status(userID uuid.UUID) (*TwoFactorStatus, error)
+	Enroll(userID uuid.UUID) (*TwoFactorEnrollment, error)
+	Confirm(userID uuid.UUID, code string) ([]string, error)
+	RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error)
+	Disable(userID uuid.UUID, password, code string) error
+	Reset(userID uuid.UUID) error
+	IsEnabled(userID uuid.UUID) (bool, error)
+	Verify(userID uuid.UUID, code string) (bool, error)
+}
+
+type twoFactorService struct {
+	repo             repository.TwoFactorRepository
+	userRepo         repository.UserRepository
+	loginAttemptRepo repository.LoginAttemptRepository
+	roleService      RoleService
+	cfg              config.AuthConfig
+}
+
+func NewTwoFactorService(repo repository.TwoFactorRepository, userRepo repository.UserRepository, loginAttemptRepo repository.LoginAttemptRepository, roleService RoleService, cfg config.AuthConfig) TwoFactorService {
+	return &twoFactorService{repo: repo, userRepo: userRepo, loginAttemptRepo: loginAttemptRepo, roleService: roleService, cfg: cfg}
+}
+
+func (s *twoFactorService) Status(userID uuid.UUID) (*TwoFactorStatus, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	required, err := s.roleService.RequiresTwoFactor(user)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &TwoFactorStatus{Required: required}
+	twoFactor, err := s.repo.FindByUser(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return status, nil
+		}
+		return nil, err
+	}
+	if twoFactor.ConfirmedAt == nil {
+		return status, nil
+	}
+
+	remaining, err := s.repo.CountUnusedRecoveryCodes(userID)
+	if err != nil {
+		return nil, err
+	}
+	status.Enabled = true
+	status.ConfirmedAt = twoFactor.ConfirmedAt
+	status.RecoveryCodesRemaining = remaining
+	return status, nil
+}
+
+// Enroll membuat secret TOTP baru yang belum aktif sampai dikonfirmasi dengan kode pertama
+func (s *twoFactorService) Enroll(userID uuid.UUID) (*TwoFactorEnrollment, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if enabled, err := s.IsEnabled(userID); err != nil {
+		return nil, err
+	} else if enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := encryptSecret(s.cfg.TOTPEncryptionKey, secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveSecret(userID, encrypted); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, s.cfg.TOTPIssuer, user.Email),
+	}, nil
+}
+
+// Confirm mengaktifkan 2FA jika kode dari authenticator cocok dan mengembalikan recovery code
+// (hanya ditampilkan sekali)
+func (s *twoFactorService) Confirm(userID uuid.UUID, code string) ([]string, error) {
+	twoFactor, err := s.repo.FindByUser(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+	if twoFactor.ConfirmedAt != nil {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := decryptSecret(s.cfg.TOTPEncryptionKey, twoFactor.SecretEncrypted)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := totp.Validate(secret, code, time.Now(), twoFactor.LastUsedStep)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Confirm(userID, step, hashes); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTwoFactorAlreadyEnabled
+		}
+		return nil, err
+	}
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes membuat recovery code baru (yang lama tidak berlaku) setelah kode 2FA valid
+func (s *twoFactorService) RegenerateRecoveryCodes(userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if err := s.requireValidCode(user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable mematikan 2FA akun sendiri; butuh password dan kode 2FA, dan ditolak jika role user mewajibkan 2FA.
+// Akun tanpa login password (hanya SSO) mengonfirmasi dengan kode TOTP dari authenticator saja.
+// Password / kode salah ikut dihitung sebagai gagal login (jeda lalu kunci akun).
+func (s *twoFactorService) Disable(userID uuid.UUID, password, code string) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if throttled := accountThrottle(s.cfg, user, time.Now()); throttled != nil {
+		return throttled
+	}
+	if user.Password != "" && !user.PasswordLoginDisabled {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+			return s.recordFailure(user, ErrInvalidCurrentPassword)
+		}
+	} else if len(normalizeTwoFactorCode(code)) != totp.DIGITS {
+		return ErrTOTPCodeRequired
+	}
+	if required, err := s.roleService.RequiresTwoFactor(user); err != nil {
+		return err
+	} else if required {
+		return ErrTwoFactorRequired
+	}
+	if err := s.requireValidCode(user, code); err != nil {
+		return err
+	}
+	return s.repo.Delete(userID)
+}
+
+// Reset menghapus 2FA user (oleh admin, misal perangkat dan recovery code hilang).
+// Jika role user mewajibkan 2FA, user harus mendaftar ulang setelah login berikutnya.
+func (s *twoFactorService) Reset(userID uuid.UUID) error {
+	if enabled, err := s.IsEnabled(userID); err != nil {
+		return err
+	} else if !enabled {
+		return ErrTwoFactorNotEnabled
+	}
+	return s.repo.Delete(userID)
+}
+
+func (s *twoFactorService) IsEnabled(userID uuid.UUID) (bool, error) {
+	twoFactor, err := s.repo.FindByUser(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return twoFactor.ConfirmedAt != nil, nil
+}
+
+// Verify memeriksa kode TOTP (6 digit) atau recovery code. Kode TOTP yang sama dan recovery code
+// yang sudah dipakai ditolak.
+func (s *twoFactorService) Verify(userID uuid.UUID, code string) (bool, error) {
+	twoFactor, err := s.repo.FindByUser(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, ErrTwoFactorNotEnabled
+		}
+		return false, err
+	}
+	if twoFactor.ConfirmedAt == nil {
+		return false, ErrTwoFactorNotEnabled
+	}
+
+	code = normalizeTwoFactorCode(code)
+	if len(code) != totp.DIGITS {
+		return s.repo.UseRecoveryCode(userID, hashToken(code))
+	}
+
+	secret, err := decryptSecret(s.cfg.TOTPEncryptionKey, twoFactor.SecretEncrypted)
+	if err != nil {
+		return false, err
+	}
+	step, ok := totp.Validate(secret, code, time.Now(), twoFactor.LastUsedStep)
+	if !ok {
+		return false, nil
+	}
+	return s.repo.UpdateLastUsedStep(userID, step)
+}
+
+// requireValidCode memeriksa kode 2FA untuk aksi akun sendiri (di luar login). Kode salah ikut
+// dihitung sebagai gagal login agar tidak bisa ditebak berulang dengan access token curian;
+// akun yang sedang diberi jeda / dikunci mendapat error yang sama seperti saat login.
+func (s *twoFactorService) requireValidCode(user *models.User, code string) error {
+	if throttled := accountThrottle(s.cfg, user, time.Now()); throttled != nil {
+		return throttled
+	}
+	ok, err := s.Verify(user.ID, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return s.recordFailure(user, ErrInvalidTwoFactorCode)
+	}
+	return nil
+}
+
+// recordFailure menambah hitungan gagal login akun lalu mengembalikan err, atau error akun dikunci
+// jika batas LoginMaxFailures tercapai
+func (s *twoFactorService) recordFailure(user *models.User, err error) error {
+	locked, recordErr := recordAccountFailure(s.loginAttemptRepo, s.cfg, user.ID, time.Now())
+	if recordErr != nil {
+		return recordErr
+	}
+	if locked != nil {
+		return locked
+	}
+	return err
+}
+
+// normalizeTwoFactorCode membuang spasi dan tanda '-' agar recovery code bisa diketik dengan atau tanpa pemisah
+func normalizeTwoFactorCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
+
+// newRecoveryCodes membuat RECOVERY_CODE_COUNT kode format xxxxx-xxxxx beserta hash-nya
+func newRecoveryCodes() (codes []string, hashes []string, err error) {
+	for i := 0; i < RECOVERY_CODE_COUNT; i++ {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		raw := recoveryCodeEncoding.EncodeToString(buf)[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+// encryptSecret mengenkripsi secret TOTP dengan AES-GCM; hasilnya base64(nonce || ciphertext)
+func encryptSecret(key []byte, secret string) (string, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func decryptSecret(key []byte, encrypted string) (string, error) {
+	gcm, err := newSecretCipher(key)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return "", errors.New("invalid encrypted two-factor secret")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("cannot decrypt two-factor secret, check TOTP_ENCRYPTION_KEY")
+	}
+	return string(plain), nil
+}
+
+func newSecretCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LOCAL_PUBLIC_PATH adalah prefix route tempat main.go menyajikan folder storage lokal
+const LOCAL_PUBLIC_PATH = "/uploads"
+
+type localStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage menyimpan file di dir. baseURL kosong berarti file disajikan aplikasi sendiri
+// di LOCAL_PUBLIC_PATH.
+func NewLocalStorage(dir, baseURL string) (Storage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	if baseURL == "" {
+		baseURL = LOCAL_PUBLIC_PATH
+	}
+	return &localStorage{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+func (s *localStorage) Put(key string, data []byte, contentType string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// tulis ke file sementara lalu rename agar file tidak pernah terbaca setengah jadi
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *localStorage) Delete(key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *localStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
@@ -0,0 +1,43 @@
+// Package storage menyimpan file upload (misal foto profil) lewat implementasi yang dipilih
+// dari konfigurasi: disk lokal untuk development, S3 / layanan kompatibel S3 untuk produksi.
+package storage
+
+import (
+	"course-planner-api/config"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// keyPattern membatasi key file ke path relatif sederhana, misal profile-photos/<uuid>/<uuid>.jpg
+var keyPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+(/[a-zA-Z0-9_-]+)*(\.[a-z0-9]+)?$`)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+type Storage interface {
+	// Put menyimpan (atau menimpa) file dengan key tertentu
+	Put(key string, data []byte, contentType string) error
+	// Delete menghapus file; key yang tidak ada tidak dianggap error
+	Delete(key string) error
+	// URL mengembalikan URL publik file
+	URL(key string) string
+}
+
+// New membuat storage sesuai cfg.Driver
+func New(cfg config.StorageConfig) (Storage, error) {
+	switch cfg.Driver {
+	case config.STORAGE_LOCAL:
+		return NewLocalStorage(cfg.LocalDir, cfg.PublicBaseURL)
+	case config.STORAGE_S3:
+		return NewS3Storage(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+func validateKey(key string) error {
+	if !keyPattern.MatchString(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
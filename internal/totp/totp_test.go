@@ -0,0 +1,79 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret adalah secret ASCII "12345678901234567890" dari lampiran B RFC 6238 (base32)
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCodeRFC6238Vectors(t *testing.T) {
+	// kode 8 digit di RFC dipotong menjadi 6 digit terakhir (DIGITS)
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d) error: %v", tt.unix, err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+	code := func(step int64) string {
+		c, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatalf("Code(%d) error: %v", step, err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", rfcSecret, code(current), 0, current, true},
+		{"previous step within skew", rfcSecret, code(current - 1), 0, current - 1, true},
+		{"next step within skew", rfcSecret, code(current + 1), 0, current + 1, true},
+		{"outside skew", rfcSecret, code(current - 2), 0, 0, false},
+		{"replay of last used step", rfcSecret, code(current), current, 0, false},
+		{"older step after newer one was used", rfcSecret, code(current - 1), current, 0, false},
+		{"newer step after older one was used", rfcSecret, code(current + 1), current, current + 1, true},
+		{"spaces are ignored", rfcSecret, code(current)[:3] + " " + code(current)[3:], 0, current, true},
+		{"lowercase secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", code(current), 0, current, true},
+		{"wrong code", rfcSecret, "000000", 0, 0, false},
+		{"too short", rfcSecret, code(current)[:5], 0, 0, false},
+		{"too long", rfcSecret, code(current) + "0", 0, 0, false},
+		{"empty", rfcSecret, "", 0, 0, false},
+		{"invalid secret", "not base32!", code(current), 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(tt.secret, tt.code, now, tt.lastStep)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate() = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
@@ -3,15 +3,21 @@ package main
 import (
 	"course-planner-api/config"
 	"course-planner-api/internal/handler"
+	"course-planner-api/internal/jwtauth"
+	"course-planner-api/internal/mailer"
 	"course-planner-api/internal/models"
+	"course-planner-api/internal/oidc"
 	"course-planner-api/internal/repository"
 	"course-planner-api/internal/router"
 	"course-planner-api/internal/service"
+	"course-planner-api/internal/storage"
 	"log"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/joho/godotenv"
+	"gorm.io/gorm"
 )
 
 func main() {
@@ -20,48 +26,177 @@ func main() {
 
 	db := config.LoadDatabase()
 	if err := db.AutoMigrate(
+		&models.Faculty{},
+		&models.StudyProgram{},
 		&models.User{},
+		&models.UserInvitation{},
+		&models.UserSession{},
+		&models.PasswordResetToken{},
+		&models.LoginAttempt{},
+		&models.UserTwoFactor{},
+		&models.RecoveryCode{},
+		&models.LoginChallenge{},
+		&models.OIDCLoginState{},
+		&models.APIKey{},
+		&models.Role{},
+		&models.UserRole{},
 		&models.Course{},
+		&models.CoursePrerequisite{},
+		&models.ProgramOutcome{},
+		&models.CourseSyllabus{},
+		&models.LearningOutcome{},
+		&models.LearningOutcomeMapping{},
+		&models.SyllabusTopic{},
+		&models.SyllabusReference{},
+		&models.Curriculum{},
+		&models.ElectiveGroup{},
+		&models.CurriculumCourse{},
 		&models.Room{},
 		&models.Class{},
+		&models.ClassDosen{},
 		&models.KRS{},
 		&models.KRSItem{},
+		&models.Notification{},
+		&models.AuditLog{},
 	); err != nil {
 		log.Fatalf("failed to migrate: %v", err)
 	}
+	if err := migrateLegacyProgramStudi(db); err != nil {
+		log.Fatalf("failed to migrate users.program_studi: %v", err)
+	}
 
-	app := fiber.New()
+	// Di belakang reverse proxy, c.IP() (pembatasan login, riwayat login, sesi, audit) diambil dari
+	// PROXY_HEADER, tetapi hanya untuk request yang datang dari TRUSTED_PROXIES
+	serverConfig := config.LoadServerConfig()
+	app := fiber.New(fiber.Config{
+		ProxyHeader:             serverConfig.ProxyHeader,
+		EnableTrustedProxyCheck: serverConfig.ProxyHeader != "",
+		TrustedProxies:          serverConfig.TrustedProxies,
+		EnableIPValidation:      true,
+	})
 
 	// Middleware logger: log setiap request ke terminal (mirip morgan di Express)
 	app.Use(logger.New())
 
+	// Middleware request id: setiap response membawa X-Request-ID (dipakai juga oleh audit log)
+	app.Use(requestid.New())
+
 	// Serve dokumentasi OpenAPI via Swagger UI pada /docs
 	app.Get("/docs", func(c *fiber.Ctx) error {
 		return c.SendFile("docs/index.html")
 	})
 	app.Static("/docs", "./docs")
 
+	// Storage file upload (foto profil); driver local disajikan langsung di /uploads
+	storageConfig := config.LoadStorageConfig()
+	fileStorage, err := storage.New(storageConfig)
+	if err != nil {
+		log.Fatalf("failed to configure storage: %v", err)
+	}
+	if storageConfig.Driver == config.STORAGE_LOCAL {
+		app.Static(storage.LOCAL_PUBLIC_PATH, storageConfig.LocalDir)
+	}
+
+	// Audit Log
+	auditRepo := repository.NewAuditRepository(db)
+	auditService := service.NewAuditService(auditRepo)
+	auditHandler := handler.NewAuditHandler(auditService)
+
 	// Auth
+	authConfig := config.LoadAuthConfig()
 	userRepo := repository.NewUserRepository(db)
-	authService := service.NewAuthService(userRepo)
+	invitationRepo := repository.NewInvitationRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	oidcRepo := repository.NewOIDCRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	roleService := service.NewRoleService(roleRepo, userRepo)
+	if err := roleService.EnsureSystemRoles(); err != nil {
+		log.Fatalf("failed to seed system roles: %v", err)
+	}
+	roleHandler := handler.NewRoleHandler(roleService)
+	tokenKeys, err := jwtauth.LoadKeySet(authConfig.JWTKeysDir, authConfig.JWTActiveKID, authConfig.JWTIssuer)
+	if err != nil {
+		log.Fatalf("failed to load JWT keys: %v", err)
+	}
+	// Login SSO lewat identity provider kampus (OIDC), aktif jika OIDC_ISSUER_URL diisi
+	oidcConfig := config.LoadOIDCConfig()
+	var oidcProvider *oidc.Provider
+	if oidcConfig.Enabled() {
+		oidcProvider = oidc.NewProvider(oidcConfig)
+	}
+	mail, err := mailer.New(config.LoadMailConfig())
+	if err != nil {
+		log.Fatalf("failed to configure mailer: %v", err)
+	}
+	twoFactorService := service.NewTwoFactorService(twoFactorRepo, userRepo, loginAttemptRepo, roleService, authConfig)
+	authService := service.NewAuthService(userRepo, invitationRepo, sessionRepo, loginAttemptRepo, twoFactorRepo, oidcRepo, roleService, twoFactorService, oidcProvider, mail, tokenKeys, authConfig, oidcConfig)
 	authHandler := handler.NewAuthHandler(authService)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService, authService)
+	invitationService := service.NewInvitationService(db, invitationRepo, authConfig)
+	invitationHandler := handler.NewInvitationHandler(invitationService)
+	passwordRepo := repository.NewPasswordRepository(db)
+	passwordService := service.NewPasswordService(passwordRepo, userRepo, sessionRepo, mail, authConfig)
+	passwordHandler := handler.NewPasswordHandler(passwordService)
+
+	// Notification
+	notificationRepo := repository.NewNotificationRepository(db)
+	notificationService := service.NewNotificationService(notificationRepo)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+
+	// Faculty & Study Program (Admin)
+	facultyRepo := repository.NewFacultyRepository(db)
+	facultyService := service.NewFacultyService(facultyRepo)
+	facultyHandler := handler.NewFacultyHandler(facultyService)
+	studyProgramRepo := repository.NewStudyProgramRepository(db)
+	studyProgramService := service.NewStudyProgramService(studyProgramRepo, facultyRepo)
+	studyProgramHandler := handler.NewStudyProgramHandler(studyProgramService)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, studyProgramRepo)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	programOutcomeRepo := repository.NewProgramOutcomeRepository(db)
+	programOutcomeService := service.NewProgramOutcomeService(programOutcomeRepo, studyProgramRepo)
+	programOutcomeHandler := handler.NewProgramOutcomeHandler(programOutcomeService)
+
+	// Course (Admin)
+	courseRepo := repository.NewCourseRepository(db)
+	courseService := service.NewCourseService(courseRepo, studyProgramRepo)
+	syllabusRepo := repository.NewSyllabusRepository(db)
+	syllabusService := service.NewSyllabusService(syllabusRepo, courseRepo, programOutcomeRepo)
+	syllabusHandler := handler.NewSyllabusHandler(syllabusService, courseService)
+	courseHandler := handler.NewCourseHandler(courseService, syllabusService)
 
 	// Class
 	classRepo := repository.NewClassRepository(db)
-	classService := service.NewClassService(classRepo)
-	classHandler := handler.NewClassHandler(classService)
+	classService := service.NewClassService(classRepo, courseRepo, notificationService)
+	classHandler := handler.NewClassHandler(classService, courseService)
+
+	// Mahasiswa Management (Admin)
+	mahasiswaRepo := repository.NewMahasiswaRepository(db)
+
+	// Curriculum
+	curriculumRepo := repository.NewCurriculumRepository(db)
+	curriculumService := service.NewCurriculumService(curriculumRepo, courseRepo, studyProgramRepo, mahasiswaRepo)
+	curriculumHandler := handler.NewCurriculumHandler(curriculumService)
 
 	// KRS
 	krsRepo := repository.NewKRSRepository(db)
-	krsService := service.NewKRSService(krsRepo)
+	krsService := service.NewKRSService(krsRepo, curriculumService)
 	krsHandler := handler.NewKRSHandler(krsService)
 	dosenService := service.NewDosenPAService(krsRepo)
 	dosenHandler := handler.NewDosenHandler(dosenService)
 
-	// Course (Admin)
-	courseRepo := repository.NewCourseRepository(db)
-	courseService := service.NewCourseService(courseRepo)
-	courseHandler := handler.NewCourseHandler(courseService)
+	// Nilai & Audit Kelulusan
+	gradeRepo := repository.NewGradeRepository(db)
+	gradeService := service.NewGradeService(gradeRepo)
+	gradeHandler := handler.NewGradeHandler(gradeService, classService)
+	degreeAuditService := service.NewDegreeAuditService(gradeRepo, curriculumRepo, mahasiswaRepo)
+
+	// Profil user (/api/me)
+	profileRepo := repository.NewProfileRepository(db)
+	profileService := service.NewProfileService(profileRepo, roleService, classService, fileStorage)
+	profileHandler := handler.NewProfileHandler(profileService)
 
 	// Room (Admin)
 	roomRepo := repository.NewRoomRepository(db)
@@ -70,8 +205,31 @@ func main() {
 
 	// Dosen Management (Admin)
 	dosenRepo := repository.NewDosenRepository(db)
-	dosenMgmtService := service.NewDosenManagementService(dosenRepo)
-	dosenMgmtHandler := handler.NewDosenManagementHandler(dosenMgmtService)
+	dosenMgmtService := service.NewDosenManagementService(dosenRepo, studyProgramRepo)
+	dosenMgmtHandler := handler.NewDosenManagementHandler(dosenMgmtService, classService)
+
+	mahasiswaMgmtService := service.NewMahasiswaManagementService(mahasiswaRepo, dosenRepo, studyProgramRepo, sessionRepo)
+	mahasiswaMgmtHandler := handler.NewMahasiswaManagementHandler(mahasiswaMgmtService)
+	// audit kelulusan admin memuat mahasiswa dulu untuk cek scope prodi
+	degreeAuditHandler := handler.NewDegreeAuditHandler(degreeAuditService, mahasiswaMgmtService)
+
+	// Dosen PA Assignment (Admin)
+	dosenPAAssignmentRepo := repository.NewDosenPAAssignmentRepository(db)
+	dosenPAAssignmentService := service.NewDosenPAAssignmentService(dosenPAAssignmentRepo, dosenRepo, notificationService)
+	dosenPAAssignmentHandler := handler.NewDosenPAAssignmentHandler(dosenPAAssignmentService)
+
+	// Bulk Import (Admin)
+	importService := service.NewImportService(db, notificationService)
+	importHandler := handler.NewImportHandler(importService)
+
+	// Exports (Admin)
+	exportRepo := repository.NewExportRepository(db)
+	exportService := service.NewExportService(exportRepo)
+	exportHandler := handler.NewExportHandler(exportService, classService)
+
+	// Katalog publik
+	catalogService := service.NewCatalogService(courseRepo, classRepo, syllabusRepo)
+	catalogHandler := handler.NewCatalogHandler(catalogService)
 
 	// Books - External API (Google Books) - UAS Feature
 	bookService := service.NewBookService()
@@ -87,9 +245,64 @@ func main() {
 		roomHandler,
 		dosenMgmtHandler,
 		bookHandler,
+		notificationHandler,
+		mahasiswaMgmtHandler,
+		dosenPAAssignmentHandler,
+		facultyHandler,
+		studyProgramHandler,
+		importHandler,
+		exportHandler,
+		auditHandler,
+		catalogHandler,
+		programOutcomeHandler,
+		syllabusHandler,
+		curriculumHandler,
+		gradeHandler,
+		degreeAuditHandler,
+		invitationHandler,
+		roleHandler,
+		passwordHandler,
+		profileHandler,
+		twoFactorHandler,
+		apiKeyHandler,
+		tokenKeys,
 	)
 
 	if err := app.Listen(":8080"); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// migrateLegacyProgramStudi memindahkan kolom teks users.program_studi (sebelum ada tabel prodi) ke
+// users.study_program_id berdasarkan nama atau kode prodi. AutoMigrate tidak pernah menghapus kolom, jadi
+// langkah ini dijalankan sendiri setiap start dan tidak melakukan apa-apa jika kolom sudah tidak ada.
+// Kolom baru dihapus setelah semua nilai yang tidak kosong berhasil dipetakan; selama masih ada nilai yang
+// belum punya prodi (misal tabel prodi masih kosong), kolom dibiarkan agar datanya tidak hilang.
+func migrateLegacyProgramStudi(db *gorm.DB) error {
+	if !db.Migrator().HasColumn("users", "program_studi") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE users SET study_program_id = study_programs.id
+			FROM study_programs
+			WHERE users.study_program_id IS NULL AND TRIM(users.program_studi) <> ''
+				AND (LOWER(study_programs.nama) = LOWER(TRIM(users.program_studi))
+					OR LOWER(study_programs.kode) = LOWER(TRIM(users.program_studi)))`).Error; err != nil {
+			return err
+		}
+
+		var unmapped []string
+		if err := tx.Table("users").
+			Where("study_program_id IS NULL AND TRIM(program_studi) <> ''").
+			Distinct().Order("1").
+			Pluck("TRIM(program_studi)", &unmapped).Error; err != nil {
+			return err
+		}
+		if len(unmapped) > 0 {
+			log.Printf("WARNING: users.program_studi is kept because %d value(s) match no study program by nama or kode: %q. "+
+				"Create the study programs (or fix the values) and restart to finish the migration.", len(unmapped), unmapped)
+			return nil
+		}
+		return tx.Migrator().DropColumn("users", "program_studi")
+	})
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openLegacySchema membuka TEST_DATABASE_URL dengan schema sementara berisi tabel users lama
+// (kolom program_studi) dan study_programs. Test dilewati jika TEST_DATABASE_URL kosong.
+func openLegacySchema(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("database handle: %v", err)
+	}
+	// satu koneksi agar search_path berlaku untuk semua query, termasuk di dalam transaksi
+	sqlDB.SetMaxOpenConns(1)
+
+	schema := "legacy_program_studi_" + uuid.NewString()[:8]
+	t.Cleanup(func() {
+		db.Exec(fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schema))
+		sqlDB.Close()
+	})
+
+	for _, stmt := range []string{
+		fmt.Sprintf("CREATE SCHEMA %s", schema),
+		fmt.Sprintf("SET search_path TO %s", schema),
+		`CREATE TABLE study_programs (id uuid PRIMARY KEY, kode varchar(20), nama varchar(100))`,
+		`CREATE TABLE users (id uuid PRIMARY KEY, program_studi varchar(100), study_program_id uuid)`,
+	} {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	return db
+}
+
+func TestMigrateLegacyProgramStudi(t *testing.T) {
+	informatika := uuid.New()
+	sistemInformasi := uuid.New()
+
+	tests := []struct {
+		name string
+		// users adalah isi program_studi per user; want adalah prodi yang diharapkan (nil = tetap kosong)
+		users      []string
+		want       []*uuid.UUID
+		wantColumn bool
+	}{
+		{
+			name:       "every value maps by nama or kode",
+			users:      []string{"Informatika", "  sistem informasi ", "SI", "", "   "},
+			want:       []*uuid.UUID{&informatika, &sistemInformasi, &sistemInformasi, nil, nil},
+			wantColumn: false,
+		},
+		{
+			name:       "unmapped value keeps the column",
+			users:      []string{"Informatika", "Teknik Sipil", "si"},
+			want:       []*uuid.UUID{&informatika, nil, &sistemInformasi},
+			wantColumn: true,
+		},
+		{
+			name:       "no users",
+			wantColumn: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openLegacySchema(t)
+
+			if err := db.Exec(`INSERT INTO study_programs (id, kode, nama) VALUES (?, 'IF', 'Informatika'), (?, 'SI', 'Sistem Informasi')`,
+				informatika, sistemInformasi).Error; err != nil {
+				t.Fatalf("seed study programs: %v", err)
+			}
+			ids := make([]uuid.UUID, len(tt.users))
+			for i, programStudi := range tt.users {
+				ids[i] = uuid.New()
+				if err := db.Exec(`INSERT INTO users (id, program_studi) VALUES (?, ?)`, ids[i], programStudi).Error; err != nil {
+					t.Fatalf("seed user: %v", err)
+				}
+			}
+
+			if err := migrateLegacyProgramStudi(db); err != nil {
+				t.Fatalf("migrateLegacyProgramStudi() error = %v", err)
+			}
+
+			if got := db.Migrator().HasColumn("users", "program_studi"); got != tt.wantColumn {
+				t.Errorf("program_studi column present = %v, want %v", got, tt.wantColumn)
+			}
+			for i, id := range ids {
+				var got *uuid.UUID
+				if err := db.Raw(`SELECT study_program_id FROM users WHERE id = ?`, id).Row().Scan(&got); err != nil {
+					t.Fatalf("read user: %v", err)
+				}
+				want := tt.want[i]
+				if (got == nil) != (want == nil) || (got != nil && *got != *want) {
+					t.Errorf("user %q study_program_id = %v, want %v", tt.users[i], got, want)
+				}
+			}
+
+			// dijalankan ulang setiap start: harus aman dipanggil lagi
+			if err := migrateLegacyProgramStudi(db); err != nil {
+				t.Errorf("second migrateLegacyProgramStudi() error = %v", err)
+			}
+		})
+	}
+}